@@ -49,8 +49,54 @@ func createHeaderGenerator(headers []HeaderField) header.ChunkHeader {
 	return headerBuiltin.KeyValueHeader(opts...)
 }
 
+// createTargets builds chunker targets from resolved target options.
+func createTargets(targets []TargetOptions) ([]chunker.Target, error) {
+	out := make([]chunker.Target, 0, len(targets))
+	for _, t := range targets {
+		tok, err := createTokenizer(t.Tokenizer)
+		if err != nil {
+			return nil, fmt.Errorf("target %q: failed to create tokenizer: %w", t.Name, err)
+		}
+		out = append(out, chunker.Target{
+			Name: t.Name,
+			Options: []chunker.Option{
+				chunker.WithChunkTokenBudget(t.Budget),
+				chunker.WithReservedOverheadRatio(t.Overhead),
+				chunker.WithMinChunkTokens(t.MinChunk),
+				chunker.WithTokenizer(tok),
+				chunker.WithChunkHeader(createHeaderGenerator(t.Headers)),
+			},
+		})
+	}
+	return out, nil
+}
+
+// createChunker creates a multi-target chunker for the given effective options.
+func createChunker(projectRoot string, opts *ChunkyOptions) (chunker.MultiChunker, error) {
+	targets, err := createTargets(opts.ResolveTargets())
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := createTransformOptions(projectRoot, opts.Tokenizer, opts.Transforms)
+	if err != nil {
+		return nil, err
+	}
+	links, err := createLinkOptions(projectRoot, opts.Links)
+	if err != nil {
+		return nil, err
+	}
+	shared = append(shared, links...)
+
+	c, err := chunker.NewMulti(shared, targets...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chunker: %w", err)
+	}
+	return c, nil
+}
+
 // processFile processes a single markdown file and returns the chunks.
-func processFile(ctx context.Context, projectRoot, filePath string, c chunker.Chunker) error {
+func processFile(ctx context.Context, projectRoot, filePath string, c chunker.MultiChunker) error {
 	// Construct absolute path
 	absPath := filepath.Join(projectRoot, filePath)
 
@@ -60,7 +106,8 @@ func processFile(ctx context.Context, projectRoot, filePath string, c chunker.Ch
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Extract title from filename (without extension)
+	// Fall back to the file name (without extension) as the title; the title
+	// derive transform replaces it with the document's own title when it has one
 	title := filepath.Base(filePath)
 	if ext := filepath.Ext(title); ext != "" {
 		title = title[:len(title)-len(ext)]
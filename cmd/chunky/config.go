@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	"gopkg.in/yaml.v3"
 )
@@ -49,8 +51,34 @@ func LoadConfig(projectRoot string) (*ChunkyOptions, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	return parseConfig(data)
+}
+
+// parseConfig strictly decodes .chunkyrc contents.
+// Unknown keys and values of the wrong type are all reported together, each
+// with its line number and, where possible, a suggested fix.
+func parseConfig(data []byte) (*ChunkyOptions, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
 	var opts ChunkyOptions
-	if err := yaml.Unmarshal(data, &opts); err != nil {
+	if node.Kind == 0 {
+		// Empty file
+		return &opts, nil
+	}
+
+	if problems := validateConfigNode(&node); len(problems) > 0 {
+		errs := make([]error, 0, len(problems)+1)
+		errs = append(errs, fmt.Errorf("%s has %d problem(s):", ConfigFileName, len(problems)))
+		for _, p := range problems {
+			errs = append(errs, fmt.Errorf("  %s:%w", ConfigFileName, p))
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	if err := node.Decode(&opts); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
@@ -78,64 +106,115 @@ func SaveConfig(projectRoot string, opts *ChunkyOptions) error {
 	return nil
 }
 
-// MergeOptions merges CLI options into config options.
-// CLI options take precedence over config options.
-// The merging logic is:
-//   - If a CLI value is explicitly set (non-zero/non-default), it overrides config
-//   - For slices, CLI values are appended to config values
-func MergeOptions(config, cli *ChunkyOptions) *ChunkyOptions {
-	result := &ChunkyOptions{}
-
-	// Files: append CLI files to config files
-	result.Files = append(result.Files, config.Files...)
-	result.Files = append(result.Files, cli.Files...)
-
-	// OutDir: CLI takes precedence if set
-	if cli.OutDir != "" && cli.OutDir != "." {
-		result.OutDir = cli.OutDir
-	} else if config.OutDir != "" {
-		result.OutDir = config.OutDir
-	} else {
-		result.OutDir = "."
+// MergeOptions resolves the effective options from config and CLI options.
+// Values are layered in the following precedence order, lowest first:
+//  1. Built-in defaults
+//  2. Top-level .chunkyrc values
+//  3. The named profile selected with --profile, if any
+//  4. Per-glob overrides matching a file (applied by ForFile, in document order)
+//  5. CLI flags that differ from their defaults
+//
+// Files and CLI headers are appended to the config values rather than replacing them.
+// Returns an error if the profile does not exist in the config.
+func MergeOptions(config, cli *ChunkyOptions, profile string) (*ChunkyOptions, error) {
+	base := &ChunkyOptions{
+		OutDir:    ".",
+		Budget:    1000,
+		Overhead:  0.05,
+		Tokenizer: "o200k_base",
 	}
 
-	// Budget: CLI takes precedence if not default
-	if cli.Budget != 0 && cli.Budget != 1000 {
-		result.Budget = cli.Budget
-	} else if config.Budget != 0 {
-		result.Budget = config.Budget
-	} else {
-		result.Budget = 1000
+	// Top-level config values
+	if config.OutDir != "" {
+		base.OutDir = config.OutDir
+		base.setSource("outDir", sourceConfig)
+	}
+	if config.Budget != 0 {
+		base.Budget = config.Budget
+		base.setSource("budget", sourceConfig)
+	}
+	if config.Overhead != 0 {
+		base.Overhead = config.Overhead
+		base.setSource("overhead", sourceConfig)
+	}
+	if config.MinChunk != 0 {
+		base.MinChunk = config.MinChunk
+		base.setSource("minChunk", sourceConfig)
+	}
+	if config.Tokenizer != "" {
+		base.Tokenizer = config.Tokenizer
+		base.setSource("tokenizer", sourceConfig)
+	}
+	if config.Strict {
+		base.Strict = true
+		base.setSource("strict", sourceConfig)
+	}
+	if config.DryRun {
+		base.DryRun = true
+		base.setSource("dryRun", sourceConfig)
+	}
+	if config.Verbose {
+		base.Verbose = true
+		base.setSource("verbose", sourceConfig)
+	}
+	if len(config.Headers) > 0 {
+		base.Headers = append(base.Headers, config.Headers...)
+		base.setSource("headers", sourceConfig)
+	}
+	if len(config.Files) > 0 {
+		base.Files = append(base.Files, config.Files...)
+		base.setSource("files", sourceConfig)
+	}
+	if len(config.Targets) > 0 {
+		base.Targets = append(base.Targets, config.Targets...)
+		base.setSource("targets", sourceConfig)
+	}
+	if config.Links.BaseURL != "" || len(config.Links.Rewrite) > 0 || config.Links.Resolve {
+		base.Links = config.Links
+		base.setSource("links", sourceConfig)
+	}
+	base.Transforms = config.Transforms.merge(nil)
+	if !reflect.ValueOf(config.Transforms).IsZero() {
+		base.setSource("transforms", sourceConfig)
 	}
 
-	// Overhead: CLI takes precedence if not default
-	if cli.Overhead != 0 && cli.Overhead != 0.05 {
-		result.Overhead = cli.Overhead
-	} else if config.Overhead != 0 {
-		result.Overhead = config.Overhead
-	} else {
-		result.Overhead = 0.05
+	// Selected profile
+	if profile != "" {
+		layer, ok := config.Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("profile %q not found in %s", profile, ConfigFileName)
+		}
+		layer.apply(base, fmt.Sprintf("profile %q", profile))
 	}
 
-	// Strict: CLI takes precedence if set
-	if cli.Strict {
-		result.Strict = true
-	} else {
-		result.Strict = config.Strict
+	// CLI flags
+	result := base.clone()
+	result.cli = cliLayer(cli)
+	result.cli.apply(result, sourceFlag)
+	result.cliHeaders = cli.Headers
+	if len(cli.Headers) > 0 {
+		result.Headers = append(result.Headers, cli.Headers...)
+		result.addSource("headers", sourceFlag)
+	}
+	if cli.DryRun && !base.DryRun {
+		result.DryRun = true
+		result.setSource("dryRun", sourceFlag)
+	}
+	if cli.Verbose && !base.Verbose {
+		result.Verbose = true
+		result.setSource("verbose", sourceFlag)
 	}
 
-	// Tokenizer: CLI takes precedence if not default
-	if cli.Tokenizer != "" && cli.Tokenizer != "o200k_base" {
-		result.Tokenizer = cli.Tokenizer
-	} else if config.Tokenizer != "" {
-		result.Tokenizer = config.Tokenizer
-	} else {
-		result.Tokenizer = "o200k_base"
+	// Files: append CLI files to config files
+	if len(cli.Files) > 0 {
+		result.Files = append(result.Files, cli.Files...)
+		result.addSource("files", sourceFlag)
 	}
 
-	// Headers: append CLI headers to config headers
-	result.Headers = append(result.Headers, config.Headers...)
-	result.Headers = append(result.Headers, cli.Headers...)
+	result.Profiles = config.Profiles
+	result.Overrides = config.Overrides
+	result.profile = profile
+	result.base = base
 
-	return result
+	return result, nil
 }
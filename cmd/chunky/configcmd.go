@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigCmd groups commands that inspect .chunkyrc.
+type ConfigCmd struct {
+	Schema ConfigSchemaCmd `cmd:"" help:"Print a JSON Schema for .chunkyrc"`
+	Show   ConfigShowCmd   `cmd:"" help:"Print the effective configuration and where each value came from"`
+}
+
+// ConfigSchemaCmd prints a JSON Schema for .chunkyrc.
+type ConfigSchemaCmd struct{}
+
+// Run executes the config schema command.
+func (c *ConfigSchemaCmd) Run() error {
+	return writeConfigSchema(os.Stdout)
+}
+
+// writeConfigSchema writes the JSON Schema for .chunkyrc to w.
+func writeConfigSchema(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(configSchema()); err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+	return nil
+}
+
+// ConfigShowCmd prints the fully merged configuration.
+type ConfigShowCmd struct {
+	ChunkyOptions
+
+	File    string `arg:"" optional:"" help:"Project-relative file to resolve overrides for"`
+	Profile string `help:"Named profile from .chunkyrc to apply" short:"p"`
+}
+
+// Run executes the config show command.
+func (c *ConfigShowCmd) Run() error {
+	projectRoot, foundConfig, err := FindProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	configOpts := &ChunkyOptions{}
+	if foundConfig {
+		configOpts, err = LoadConfig(projectRoot)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+
+	opts, err := MergeOptions(configOpts, &c.ChunkyOptions, c.Profile)
+	if err != nil {
+		return err
+	}
+
+	if foundConfig {
+		fmt.Printf("# Project root: %s\n", projectRoot)
+	} else {
+		fmt.Printf("# No %s found, showing defaults and CLI flags\n", ConfigFileName)
+	}
+	if c.File != "" {
+		fmt.Printf("# Resolved for: %s\n", c.File)
+	}
+	if err := writeEffectiveConfig(os.Stdout, opts, c.File); err != nil {
+		return err
+	}
+
+	if err := opts.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Warning: invalid options: %v\n", err)
+	}
+	return nil
+}
+
+// writeEffectiveConfig writes the merged options as YAML to w, each top-level
+// key annotated with where its value came from. Profiles are resolved by now;
+// overrides are too when file is not empty.
+func writeEffectiveConfig(w io.Writer, opts *ChunkyOptions, file string) error {
+	shown := opts.clone()
+	shown.Profiles = nil
+	if file != "" {
+		shown = opts.ForFile(file).clone()
+		shown.Profiles = nil
+		shown.Overrides = nil
+	}
+
+	var node yaml.Node
+	if err := node.Encode(shown); err != nil {
+		return fmt.Errorf("failed to serialize config: %w", err)
+	}
+	annotateSources(&node, shown)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("failed to serialize config: %w", err)
+	}
+	return enc.Close()
+}
+
+// annotateSources adds a "# from <source>" comment to every top-level key.
+func annotateSources(node *yaml.Node, opts *ChunkyOptions) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		comment := "from " + opts.Source(key.Value)
+		if key.Value == "overrides" {
+			comment = "from " + sourceConfig
+		}
+		if value.Kind == yaml.ScalarNode || len(value.Content) == 0 {
+			value.LineComment = comment
+		} else {
+			key.LineComment = comment
+		}
+	}
+}
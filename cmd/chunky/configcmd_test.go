@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestWriteConfigSchema(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeConfigSchema(&buf); err != nil {
+		t.Fatalf("writeConfigSchema failed: %v", err)
+	}
+
+	golden := filepath.Join("testdata", "config.schema.json")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+			t.Fatalf("failed to update %s: %v", golden, err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read %s (run with -update to create it): %v", golden, err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("schema differs from %s; run go test ./cmd/chunky -run TestWriteConfigSchema -update and review the diff", golden)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+}
+
+func TestWriteEffectiveConfig(t *testing.T) {
+	config, err := parseConfig([]byte(layeredConfig + "headers: [title]\n"))
+	if err != nil {
+		t.Fatalf("parseConfig failed: %v", err)
+	}
+	cli := cliDefaults()
+	cli.MinChunk = 50
+	cli.Headers = []HeaderField{{Path: "owner", Label: "owner"}}
+	opts, err := MergeOptions(config, cli, "ci")
+	if err != nil {
+		t.Fatalf("MergeOptions failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{
+			name: "top level",
+			want: `outDir: . # from default
+budget: 1500 # from profile "ci"
+overhead: 0.05 # from default
+strict: true # from profile "ci"
+minChunk: 50 # from flag
+tokenizer: cl100k_base # from .chunkyrc
+headers: # from .chunkyrc + flag
+  - path: title
+    label: title
+    required: false
+  - path: owner
+    label: owner
+    required: false
+dryRun: false # from default
+verbose: false # from default
+overrides: # from .chunkyrc
+  docs/api/**:
+    budget: 2000
+    overhead: 0.1
+  docs/api/legacy/**:
+    strict: false
+`,
+		},
+		{
+			name: "resolved for a file",
+			file: "docs/api/legacy/v1.md",
+			want: `outDir: . # from default
+budget: 2000 # from override "docs/api/**"
+overhead: 0.1 # from override "docs/api/**"
+strict: false # from override "docs/api/legacy/**"
+minChunk: 50 # from flag
+tokenizer: cl100k_base # from .chunkyrc
+headers: # from .chunkyrc + flag
+  - path: title
+    label: title
+    required: false
+  - path: owner
+    label: owner
+    required: false
+dryRun: false # from default
+verbose: false # from default
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeEffectiveConfig(&buf, opts, tt.file); err != nil {
+				t.Fatalf("writeEffectiveConfig failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
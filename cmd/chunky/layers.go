@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// OptionsLayer is a partial set of options used by profiles, overrides and CLI flags.
+// Only fields that are set replace the values beneath them; pointers distinguish
+// "unset" from an explicit zero value such as `strict: false`.
+type OptionsLayer struct {
+	OutDir     *string           `yaml:"outDir,omitempty" help:"Output directory for chunks"`
+	Budget     *int              `yaml:"budget,omitempty" help:"Token budget per chunk"`
+	Overhead   *float64          `yaml:"overhead,omitempty" help:"Overhead fraction (0.01-0.5)"`
+	Strict     *bool             `yaml:"strict,omitempty" help:"Fail on jumbo chunks"`
+	MinChunk   *int              `yaml:"minChunk,omitempty" help:"Minimum tokens in a document's last chunk"`
+	Tokenizer  *string           `yaml:"tokenizer,omitempty" help:"Tokenizer (e.g., o200k_base, char, word, cl100k_base, etc.)"`
+	Headers    []HeaderField     `yaml:"headers,omitempty" help:"Header fields to include (replaces inherited headers)"`
+	Transforms *TransformOptions `yaml:"transforms,omitempty" help:"Builtin transforms to enable"`
+}
+
+// apply copies every set field of the layer onto opts, recording source as
+// the origin of each value it sets. Headers in a layer replace the headers beneath it.
+func (l *OptionsLayer) apply(opts *ChunkyOptions, source string) {
+	if l == nil {
+		return
+	}
+	if l.OutDir != nil {
+		opts.OutDir = *l.OutDir
+		opts.setSource("outDir", source)
+	}
+	if l.Budget != nil {
+		opts.Budget = *l.Budget
+		opts.setSource("budget", source)
+	}
+	if l.Overhead != nil {
+		opts.Overhead = *l.Overhead
+		opts.setSource("overhead", source)
+	}
+	if l.Strict != nil {
+		opts.Strict = *l.Strict
+		opts.setSource("strict", source)
+	}
+	if l.MinChunk != nil {
+		opts.MinChunk = *l.MinChunk
+		opts.setSource("minChunk", source)
+	}
+	if l.Tokenizer != nil {
+		opts.Tokenizer = *l.Tokenizer
+		opts.setSource("tokenizer", source)
+	}
+	if len(l.Headers) > 0 {
+		opts.Headers = append([]HeaderField(nil), l.Headers...)
+		opts.setSource("headers", source)
+	}
+	if l.Transforms != nil {
+		opts.Transforms = opts.Transforms.merge(l.Transforms)
+		opts.addSource("transforms", source)
+	}
+}
+
+// Override changes options for files matching a glob.
+// Overrides may only change budget, overhead, minChunk, headers, strict and transforms.
+// Targets that set budget, overhead, minChunk or headers of their own are
+// changed through Targets instead.
+type Override struct {
+	Glob       string                 `yaml:"-"`
+	Budget     *int                   `yaml:"budget,omitempty" help:"Token budget per chunk"`
+	Overhead   *float64               `yaml:"overhead,omitempty" help:"Overhead fraction (0.01-0.5)"`
+	Strict     *bool                  `yaml:"strict,omitempty" help:"Fail on jumbo chunks"`
+	MinChunk   *int                   `yaml:"minChunk,omitempty" help:"Minimum tokens in a document's last chunk"`
+	Headers    []HeaderField          `yaml:"headers,omitempty" help:"Header fields to include (replaces inherited headers)"`
+	Transforms *TransformOptions      `yaml:"transforms,omitempty" help:"Builtin transforms to enable"`
+	Targets    map[string]TargetLayer `yaml:"targets,omitempty" help:"Options for named targets, keyed by target name"`
+}
+
+// TargetLayer changes the options of one named target for files matching an override.
+type TargetLayer struct {
+	Budget   *int          `yaml:"budget,omitempty" help:"Token budget per chunk"`
+	Overhead *float64      `yaml:"overhead,omitempty" help:"Overhead fraction (0.01-0.5)"`
+	MinChunk *int          `yaml:"minChunk,omitempty" help:"Minimum tokens in a document's last chunk"`
+	Headers  []HeaderField `yaml:"headers,omitempty" help:"Header fields to include (replaces the target's headers)"`
+}
+
+// apply copies every set field of the layer onto t.
+func (l TargetLayer) apply(t *TargetOptions) {
+	if l.Budget != nil {
+		t.Budget = *l.Budget
+	}
+	if l.Overhead != nil {
+		t.Overhead = *l.Overhead
+	}
+	if l.MinChunk != nil {
+		t.MinChunk = *l.MinChunk
+	}
+	if len(l.Headers) > 0 {
+		t.Headers = append([]HeaderField(nil), l.Headers...)
+	}
+}
+
+// validate checks that the override can take effect on every target: options
+// a target sets itself must be changed through the override's Targets too.
+func (o Override) validate(targets []TargetOptions) error {
+	names := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		names[t.Name] = true
+		l := o.Targets[t.Name]
+		var shadowed []string
+		if o.Budget != nil && t.Budget != 0 && l.Budget == nil {
+			shadowed = append(shadowed, "budget")
+		}
+		if o.Overhead != nil && t.Overhead != 0 && l.Overhead == nil {
+			shadowed = append(shadowed, "overhead")
+		}
+		if o.MinChunk != nil && t.MinChunk != 0 && l.MinChunk == nil {
+			shadowed = append(shadowed, "minChunk")
+		}
+		if len(o.Headers) > 0 && len(t.Headers) > 0 && len(l.Headers) == 0 {
+			shadowed = append(shadowed, "headers")
+		}
+		if len(shadowed) > 0 {
+			return fmt.Errorf("override %q: target %q sets its own %s; set them under targets.%s in the override",
+				o.Glob, t.Name, strings.Join(shadowed, ", "), t.Name)
+		}
+	}
+	for name := range o.Targets {
+		if !names[name] {
+			return fmt.Errorf("override %q: unknown target %q", o.Glob, name)
+		}
+	}
+	return nil
+}
+
+// layer returns the override as an OptionsLayer.
+func (o Override) layer() *OptionsLayer {
+	return &OptionsLayer{
+		Budget:     o.Budget,
+		Overhead:   o.Overhead,
+		Strict:     o.Strict,
+		MinChunk:   o.MinChunk,
+		Headers:    o.Headers,
+		Transforms: o.Transforms,
+	}
+}
+
+// Matches reports whether the project-relative file path matches the override glob.
+func (o Override) Matches(path string) bool {
+	ok, _ := doublestar.Match(filepath.ToSlash(o.Glob), filepath.ToSlash(path))
+	return ok
+}
+
+// Overrides is the ordered list of per-glob overrides.
+// In YAML it is a mapping keyed by glob; document order is preserved because
+// later overrides take precedence over earlier ones.
+//
+//	overrides:
+//	  "docs/api/**":
+//	    budget: 2000
+type Overrides []Override
+
+// UnmarshalYAML implements yaml.Unmarshaler, preserving key order.
+func (o *Overrides) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: overrides must be a mapping of glob to options", node.Line)
+	}
+
+	out := make(Overrides, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !doublestar.ValidatePattern(key.Value) {
+			return fmt.Errorf("line %d: invalid override glob %q", key.Line, key.Value)
+		}
+
+		var ov Override
+		if err := value.Decode(&ov); err != nil {
+			return err
+		}
+		ov.Glob = key.Value
+		out = append(out, ov)
+	}
+
+	*o = out
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler, emitting a mapping keyed by glob.
+func (o Overrides) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, ov := range o {
+		var value yaml.Node
+		if err := value.Encode(ov); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: ov.Glob},
+			&value,
+		)
+	}
+	return node, nil
+}
+
+// cliLayer converts CLI options into a layer.
+// A CLI value counts as set when it differs from its flag default.
+func cliLayer(cli *ChunkyOptions) *OptionsLayer {
+	l := &OptionsLayer{}
+	if cli.OutDir != "" && cli.OutDir != "." {
+		l.OutDir = &cli.OutDir
+	}
+	if cli.Budget != 0 && cli.Budget != 1000 {
+		l.Budget = &cli.Budget
+	}
+	if cli.Overhead != 0 && cli.Overhead != 0.05 {
+		l.Overhead = &cli.Overhead
+	}
+	if cli.Strict {
+		l.Strict = &cli.Strict
+	}
+	if cli.MinChunk != 0 {
+		l.MinChunk = &cli.MinChunk
+	}
+	if cli.Tokenizer != "" && cli.Tokenizer != "o200k_base" {
+		l.Tokenizer = &cli.Tokenizer
+	}
+	return l
+}
+
+// ForFile returns the effective options for a project-relative file path,
+// applying every matching override in order beneath the CLI flags.
+// Returns the receiver itself when no override matches.
+func (opts *ChunkyOptions) ForFile(path string) *ChunkyOptions {
+	matched := opts.matchOverrides(path)
+	if len(matched) == 0 {
+		return opts
+	}
+
+	result := opts.base.clone()
+	for _, i := range matched {
+		ov := opts.Overrides[i]
+		ov.layer().apply(result, fmt.Sprintf("override %q", ov.Glob))
+	}
+	for i := range result.Targets {
+		for _, m := range matched {
+			if l, ok := opts.Overrides[m].Targets[result.Targets[i].Name]; ok {
+				l.apply(&result.Targets[i])
+			}
+		}
+	}
+	opts.cli.apply(result, sourceFlag)
+	if len(opts.cliHeaders) > 0 {
+		result.Headers = append(result.Headers, opts.cliHeaders...)
+		result.addSource("headers", sourceFlag)
+	}
+	result.Files = opts.Files
+	result.DryRun = opts.DryRun
+	result.Verbose = opts.Verbose
+	return result
+}
+
+// matchOverrides returns the indices of overrides that match the path, in order.
+func (opts *ChunkyOptions) matchOverrides(path string) []int {
+	if opts.base == nil {
+		return nil
+	}
+	var matched []int
+	for i, ov := range opts.Overrides {
+		if ov.Matches(path) {
+			matched = append(matched, i)
+		}
+	}
+	return matched
+}
+
+// clone returns a copy of the options whose slices and maps can be modified
+// without affecting the original.
+func (opts *ChunkyOptions) clone() *ChunkyOptions {
+	c := *opts
+	c.Headers = append([]HeaderField(nil), opts.Headers...)
+	c.Files = append([]string(nil), opts.Files...)
+	c.Targets = append([]TargetOptions(nil), opts.Targets...)
+	c.Transforms.Defaults = maps.Clone(opts.Transforms.Defaults)
+	c.Links.Rewrite = append([]PathRewrite(nil), opts.Links.Rewrite...)
+	c.sources = maps.Clone(opts.sources)
+	return &c
+}
+
+// Value sources reported by "chunky config show".
+const (
+	sourceDefault = "default"
+	sourceConfig  = ConfigFileName
+	sourceFlag    = "flag"
+)
+
+// setSource records where the current value of a top-level option came from.
+func (opts *ChunkyOptions) setSource(key, source string) {
+	if opts.sources == nil {
+		opts.sources = make(map[string]string)
+	}
+	opts.sources[key] = source
+}
+
+// addSource records an additional source for an option whose value is merged
+// or appended across layers, such as headers added by CLI flags.
+func (opts *ChunkyOptions) addSource(key, source string) {
+	prev := opts.sources[key]
+	if prev == "" || prev == sourceDefault {
+		opts.setSource(key, source)
+		return
+	}
+	opts.setSource(key, prev+" + "+source)
+}
+
+// Source returns where the effective value of a top-level option came from,
+// e.g. "default", ".chunkyrc", `profile "ci"`, `override "docs/**"` or "flag".
+func (opts *ChunkyOptions) Source(key string) string {
+	if s, ok := opts.sources[key]; ok {
+		return s
+	}
+	return sourceDefault
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/wyvernzora/chunky/pkg/chunker"
+)
+
+// LinkOptions configures deep links from chunks to the published documentation site.
+type LinkOptions struct {
+	// BaseURL is the site root, e.g. "https://docs.example.com". Empty disables deep links.
+	BaseURL string `yaml:"baseURL,omitempty" help:"Site base URL that chunk deep links start with"`
+
+	// Rewrite maps file paths to URL paths. The first rule whose pattern matches
+	// a file path is applied; paths matching no rule lose their extension.
+	Rewrite []PathRewrite `yaml:"rewrite,omitempty" help:"Rules mapping file paths to URL paths; the first match wins"`
+
+	// Resolve resolves relative links between documents, rewrites them to deep
+	// links when BaseURL is set, and lists each chunk's links to other documents
+	// in its header. Links to files missing from the project are reported.
+	// Rewriting links to the IDs of target chunks is out of scope for the CLI;
+	// library users can do so with the rewrite function of WithLinkResolution.
+	Resolve bool `yaml:"resolve,omitempty" help:"Resolve relative links between documents"`
+}
+
+// PathRewrite is a regular expression rewrite of a file path into a URL path.
+type PathRewrite struct {
+	Match   string `yaml:"match" help:"Regular expression matched against the file path"`
+	Replace string `yaml:"replace" help:"Replacement URL path; $1 etc. refer to capture groups"`
+}
+
+// validate checks that the base URL is absolute and every rewrite pattern compiles.
+func (l LinkOptions) validate() error {
+	if l.BaseURL == "" {
+		if len(l.Rewrite) > 0 {
+			return fmt.Errorf("links.rewrite requires links.baseURL")
+		}
+		return nil
+	}
+	u, err := url.Parse(l.BaseURL)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("links.baseURL must be an absolute URL, got %q", l.BaseURL)
+	}
+	for i, r := range l.Rewrite {
+		if _, err := regexp.Compile(r.Match); err != nil {
+			return fmt.Errorf("links.rewrite[%d]: invalid pattern: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// createLinkOptions returns the chunker options enabling deep links and link
+// resolution, if configured. Link targets are looked up under projectRoot.
+func createLinkOptions(projectRoot string, l LinkOptions) ([]chunker.Option, error) {
+	var opts []chunker.Option
+	if l.Resolve {
+		exists := func(path string) bool {
+			// The chunker never passes paths above the root; refuse them anyway
+			if !filepath.IsLocal(filepath.FromSlash(path)) {
+				return false
+			}
+			_, err := os.Stat(filepath.Join(projectRoot, filepath.FromSlash(path)))
+			return err == nil
+		}
+		opts = append(opts, chunker.WithLinkResolution(exists, nil))
+	}
+	if l.BaseURL == "" {
+		return opts, nil
+	}
+
+	type rule struct {
+		re      *regexp.Regexp
+		replace string
+	}
+	rules := make([]rule, 0, len(l.Rewrite))
+	for i, r := range l.Rewrite {
+		re, err := regexp.Compile(r.Match)
+		if err != nil {
+			return nil, fmt.Errorf("links.rewrite[%d]: invalid pattern: %w", i, err)
+		}
+		rules = append(rules, rule{re: re, replace: r.Replace})
+	}
+
+	mapPath := func(path string) string {
+		for _, r := range rules {
+			if r.re.MatchString(path) {
+				return r.re.ReplaceAllString(path, r.replace)
+			}
+		}
+		return chunker.DefaultURLPath(path)
+	}
+	return append(opts, chunker.WithDeepLinks(l.BaseURL, mapPath)), nil
+}
@@ -11,8 +11,9 @@ var version = "dev"
 
 // CLI represents the top-level command structure.
 type CLI struct {
-	Run  RunCmd  `cmd:"" help:"Run chunking on files"`
-	Init InitCmd `cmd:"init" help:"Initialize a .chunkyrc configuration file"`
+	Run    RunCmd    `cmd:"" help:"Run chunking on files"`
+	Init   InitCmd   `cmd:"init" help:"Initialize a .chunkyrc configuration file"`
+	Config ConfigCmd `cmd:"" help:"Inspect .chunkyrc configuration"`
 }
 
 func main() {
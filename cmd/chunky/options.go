@@ -3,33 +3,169 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/jwalton/gchalk"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
 )
 
 // ChunkyOptions represents the unified configuration for both CLI and .chunkyrc.
 // This struct is used by Kong for CLI parsing and YAML for config file parsing.
 // Note: Files is separate to avoid Kong's restriction on mixing positional args with subcommands.
 type ChunkyOptions struct {
-	OutDir    string        `yaml:"outDir" help:"Output directory for chunks" short:"o" default:"."`
-	Budget    int           `yaml:"budget" help:"Token budget per chunk" short:"b" default:"1000"`
-	Overhead  float64       `yaml:"overhead" help:"Overhead fraction (0.01-0.5)" short:"e" default:"0.05"`
-	Strict    bool          `yaml:"strict" help:"Fail on jumbo chunks" short:"s"`
-	Tokenizer string        `yaml:"tokenizer" help:"Tokenizer (e.g., o200k_base, char, word, cl100k_base, etc.)" short:"t" default:"o200k_base"`
-	Headers   []HeaderField `yaml:"headers" help:"Header fields to include" short:"H"`
-	DryRun    bool          `yaml:"dryRun" help:"Print chunks without writing files" short:"d"`
-	Verbose   bool          `yaml:"verbose" help:"Show verbose output including effective configuration" short:"v"`
-	Files     []string      `yaml:"files,omitempty" json:"-" kong:"-"` // Not a CLI flag, only in config
+	OutDir    string          `yaml:"outDir" help:"Output directory for chunks" short:"o" default:"."`
+	Budget    int             `yaml:"budget" help:"Token budget per chunk" short:"b" default:"1000"`
+	Overhead  float64         `yaml:"overhead" help:"Overhead fraction (0.01-0.5)" short:"e" default:"0.05"`
+	Strict    bool            `yaml:"strict" help:"Fail on jumbo chunks" short:"s"`
+	MinChunk  int             `yaml:"minChunk" help:"Minimum tokens in a document's last chunk; smaller tails are merged or rebalanced"`
+	Tokenizer string          `yaml:"tokenizer" help:"Tokenizer (e.g., o200k_base, char, word, cl100k_base, etc.)" short:"t" default:"o200k_base"`
+	Headers   []HeaderField   `yaml:"headers" help:"Header fields to include" short:"H"`
+	DryRun    bool            `yaml:"dryRun" help:"Print chunks without writing files" short:"d"`
+	Verbose   bool            `yaml:"verbose" help:"Show verbose output including effective configuration" short:"v"`
+	Files     []string        `yaml:"files,omitempty" json:"-" kong:"-" help:"File globs to process"`    // Not a CLI flag, only in config
+	Targets   []TargetOptions `yaml:"targets,omitempty" json:"-" kong:"-" help:"Named chunking targets"` // Not a CLI flag, only in config
+
+	Transforms TransformOptions        `yaml:"transforms,omitempty" kong:"-" help:"Builtin transforms to enable"`            // Not a CLI flag, only in config
+	Links      LinkOptions             `yaml:"links,omitempty" kong:"-" help:"Deep links to the published site"`             // Not a CLI flag, only in config
+	Profiles   map[string]OptionsLayer `yaml:"profiles,omitempty" kong:"-" help:"Named option sets selected with --profile"` // Not a CLI flag, only in config
+	Overrides  Overrides               `yaml:"overrides,omitempty" kong:"-" help:"Options for files matching a glob"`        // Not a CLI flag, only in config
+
+	// Resolution state retained by MergeOptions so ForFile can re-layer overrides
+	// beneath the CLI flags.
+	profile    string         `kong:"-"`
+	base       *ChunkyOptions `kong:"-"`
+	cli        *OptionsLayer  `kong:"-"`
+	cliHeaders []HeaderField  `kong:"-"`
+
+	// sources maps top-level YAML keys to where their values came from.
+	sources map[string]string `kong:"-"`
+}
+
+// TargetOptions configures one named chunking target in .chunkyrc.
+// Each document is parsed once and then packed separately for every target.
+// Unset fields inherit from the top-level options, except OutDir, which
+// defaults to a subdirectory of the top-level output directory named after the target.
+type TargetOptions struct {
+	Name      string        `yaml:"name" help:"Unique target name"`
+	OutDir    string        `yaml:"outDir,omitempty" help:"Output directory (defaults to <outDir>/<name>)"`
+	Budget    int           `yaml:"budget,omitempty" help:"Token budget per chunk"`
+	Overhead  float64       `yaml:"overhead,omitempty" help:"Overhead fraction (0.01-0.5)"`
+	MinChunk  int           `yaml:"minChunk,omitempty" help:"Minimum tokens in a document's last chunk"`
+	Tokenizer string        `yaml:"tokenizer,omitempty" help:"Tokenizer (e.g., o200k_base, char, word, cl100k_base, etc.)"`
+	Headers   []HeaderField `yaml:"headers,omitempty" help:"Header fields to include"`
+}
+
+// defaultTargetName is the name of the implicit target used when no targets are configured.
+const defaultTargetName = "default"
+
+// ResolveTargets returns the fully populated targets to chunk for.
+// If no targets are configured, a single implicit target mirrors the top-level options.
+func (opts *ChunkyOptions) ResolveTargets() []TargetOptions {
+	if len(opts.Targets) == 0 {
+		return []TargetOptions{{
+			Name:      defaultTargetName,
+			OutDir:    opts.OutDir,
+			Budget:    opts.Budget,
+			Overhead:  opts.Overhead,
+			MinChunk:  opts.MinChunk,
+			Tokenizer: opts.Tokenizer,
+			Headers:   opts.Headers,
+		}}
+	}
+
+	targets := make([]TargetOptions, 0, len(opts.Targets))
+	for _, t := range opts.Targets {
+		if t.OutDir == "" {
+			t.OutDir = filepath.Join(opts.OutDir, t.Name)
+		}
+		if t.Budget == 0 {
+			t.Budget = opts.Budget
+		}
+		if t.Overhead == 0 {
+			t.Overhead = opts.Overhead
+		}
+		if t.MinChunk == 0 {
+			t.MinChunk = opts.MinChunk
+		}
+		if t.Tokenizer == "" {
+			t.Tokenizer = opts.Tokenizer
+		}
+		if len(t.Headers) == 0 {
+			t.Headers = opts.Headers
+		}
+		targets = append(targets, t)
+	}
+	return targets
 }
 
 func (opts *ChunkyOptions) Validate() error {
-	if opts.Budget < 100 {
-		return fmt.Errorf("budget must be at least 100, got %d", opts.Budget)
+	if err := validateBudget(opts.Budget, opts.Overhead); err != nil {
+		return err
+	}
+	if opts.MinChunk < 0 {
+		return fmt.Errorf("minChunk must be >= 0, got %d", opts.MinChunk)
+	}
+	if err := opts.Transforms.validate(); err != nil {
+		return err
+	}
+	if err := opts.Links.validate(); err != nil {
+		return err
+	}
+	if err := validateHeaders(opts.Headers); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(opts.Targets))
+	for i, t := range opts.Targets {
+		if t.Name == "" {
+			return fmt.Errorf("targets[%d]: name is required", i)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("targets[%d]: duplicate target name %q", i, t.Name)
+		}
+		seen[t.Name] = true
+	}
+	for _, ov := range opts.Overrides {
+		if err := ov.validate(opts.Targets); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.Targets) > 0 {
+		for _, t := range opts.ResolveTargets() {
+			if err := validateBudget(t.Budget, t.Overhead); err != nil {
+				return fmt.Errorf("target %q: %w", t.Name, err)
+			}
+			if t.MinChunk < 0 {
+				return fmt.Errorf("target %q: minChunk must be >= 0, got %d", t.Name, t.MinChunk)
+			}
+			if err := validateHeaders(t.Headers); err != nil {
+				return fmt.Errorf("target %q: %w", t.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateHeaders checks that every header field path is well-formed.
+func validateHeaders(headers []HeaderField) error {
+	for i, h := range headers {
+		if err := fm.ValidatePath(h.Path); err != nil {
+			return fmt.Errorf("headers[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateBudget checks the budget and overhead ranges shared by options and targets.
+func validateBudget(budget int, overhead float64) error {
+	if budget < 100 {
+		return fmt.Errorf("budget must be at least 100, got %d", budget)
 	}
 
-	if opts.Overhead < 0.01 || opts.Overhead > 0.5 {
-		return fmt.Errorf("overhead must be in range [0.01, 0.5], got %.2f", opts.Overhead)
+	if overhead < 0.01 || overhead > 0.5 {
+		return fmt.Errorf("overhead must be in range [0.01, 0.5], got %.2f", overhead)
 	}
 	return nil
 }
@@ -42,7 +178,17 @@ func (opts *ChunkyOptions) Print(root string, files []string) {
 	fmt.Printf("    Token Budget:  %d\n", opts.Budget)
 	fmt.Printf("    Overhead:      %.2f (%.0f%%)\n", opts.Overhead, opts.Overhead*100)
 	fmt.Printf("    Strict Mode:   %t\n", opts.Strict)
+	fmt.Printf("    Min Chunk:     %d\n", opts.MinChunk)
 	fmt.Printf("    Tokenizer:     %s\n", opts.Tokenizer)
+	if opts.profile != "" {
+		fmt.Printf("    Profile:       %s\n", opts.profile)
+	}
+	if opts.Links.BaseURL != "" {
+		fmt.Printf("    Deep Links:    %s (%d rewrite rule(s))\n", opts.Links.BaseURL, len(opts.Links.Rewrite))
+	}
+	if opts.Links.Resolve {
+		fmt.Printf("    Link Resolve:  %t\n", opts.Links.Resolve)
+	}
 
 	fmt.Println(gchalk.Bold("\nHeader Fields:"))
 	if len(opts.Headers) == 0 {
@@ -61,6 +207,27 @@ func (opts *ChunkyOptions) Print(root string, files []string) {
 		}
 	}
 
+	if len(opts.Overrides) > 0 {
+		fmt.Println(gchalk.Bold("\nOverrides:"))
+		for _, ov := range opts.Overrides {
+			matched := 0
+			for _, f := range files {
+				if ov.Matches(f) {
+					matched++
+				}
+			}
+			fmt.Printf("  - %s (%d file(s))\n", ov.Glob, matched)
+		}
+	}
+
+	if len(opts.Targets) > 0 {
+		fmt.Println(gchalk.Bold("\nTargets:"))
+		for _, t := range opts.ResolveTargets() {
+			fmt.Printf("  - %s: %s, budget %d, overhead %.2f, %d header field(s) → %s\n",
+				t.Name, t.Tokenizer, t.Budget, t.Overhead, len(t.Headers), t.OutDir)
+		}
+	}
+
 	fmt.Printf(gchalk.Bold("\nFiles (%d total):\n"), len(files))
 	if len(files) == 0 {
 		fmt.Println(gchalk.Dim("  (none matched)"))
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// cliDefaults returns CLI options holding only the flag defaults, as Kong
+// parses them when no flags are given.
+func cliDefaults() *ChunkyOptions {
+	return &ChunkyOptions{OutDir: ".", Budget: 1000, Overhead: 0.05, Tokenizer: "o200k_base"}
+}
+
+const layeredConfig = `
+budget: 1200
+tokenizer: cl100k_base
+profiles:
+  ci:
+    budget: 1500
+    strict: true
+overrides:
+  "docs/api/**":
+    budget: 2000
+    overhead: 0.1
+  "docs/api/legacy/**":
+    strict: false
+`
+
+func TestMergeOptions_Precedence(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		profile   string
+		cli       func(o *ChunkyOptions)
+		file      string
+		budget    int
+		source    string
+		overhead  float64
+		strict    bool
+		tokenizer string
+	}{
+		{
+			name:      "defaults",
+			budget:    1000,
+			source:    sourceDefault,
+			overhead:  0.05,
+			tokenizer: "o200k_base",
+		},
+		{
+			name:      "config",
+			config:    layeredConfig,
+			budget:    1200,
+			source:    sourceConfig,
+			overhead:  0.05,
+			tokenizer: "cl100k_base",
+		},
+		{
+			name:      "profile",
+			config:    layeredConfig,
+			profile:   "ci",
+			budget:    1500,
+			source:    `profile "ci"`,
+			overhead:  0.05,
+			strict:    true,
+			tokenizer: "cl100k_base",
+		},
+		{
+			name:      "override",
+			config:    layeredConfig,
+			profile:   "ci",
+			file:      "docs/api/users.md",
+			budget:    2000,
+			source:    `override "docs/api/**"`,
+			overhead:  0.1,
+			strict:    true,
+			tokenizer: "cl100k_base",
+		},
+		{
+			name:      "later override",
+			config:    layeredConfig,
+			profile:   "ci",
+			file:      "docs/api/legacy/v1.md",
+			budget:    2000,
+			source:    `override "docs/api/**"`,
+			overhead:  0.1,
+			strict:    false,
+			tokenizer: "cl100k_base",
+		},
+		{
+			name:      "unmatched file",
+			config:    layeredConfig,
+			file:      "docs/guide.md",
+			budget:    1200,
+			source:    sourceConfig,
+			overhead:  0.05,
+			tokenizer: "cl100k_base",
+		},
+		{
+			name:      "flag",
+			config:    layeredConfig,
+			profile:   "ci",
+			cli:       func(o *ChunkyOptions) { o.Budget = 3000; o.Tokenizer = "word" },
+			file:      "docs/api/users.md",
+			budget:    3000,
+			source:    sourceFlag,
+			overhead:  0.1,
+			strict:    true,
+			tokenizer: "word",
+		},
+		{
+			name:      "flag equal to its default is unset",
+			config:    layeredConfig,
+			cli:       func(o *ChunkyOptions) { o.Budget = 1000; o.Overhead = 0.05; o.Tokenizer = "o200k_base" },
+			file:      "docs/api/users.md",
+			budget:    2000,
+			source:    `override "docs/api/**"`,
+			overhead:  0.1,
+			tokenizer: "cl100k_base",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := parseConfig([]byte(tt.config))
+			if err != nil {
+				t.Fatalf("parseConfig failed: %v", err)
+			}
+			cli := cliDefaults()
+			if tt.cli != nil {
+				tt.cli(cli)
+			}
+			opts, err := MergeOptions(config, cli, tt.profile)
+			if err != nil {
+				t.Fatalf("MergeOptions failed: %v", err)
+			}
+			if tt.file != "" {
+				opts = opts.ForFile(tt.file)
+			}
+
+			if opts.Budget != tt.budget {
+				t.Errorf("expected budget %d, got %d", tt.budget, opts.Budget)
+			}
+			if got := opts.Source("budget"); got != tt.source {
+				t.Errorf("expected budget from %s, got %s", tt.source, got)
+			}
+			if opts.Overhead != tt.overhead {
+				t.Errorf("expected overhead %v, got %v", tt.overhead, opts.Overhead)
+			}
+			if opts.Strict != tt.strict {
+				t.Errorf("expected strict %t, got %t", tt.strict, opts.Strict)
+			}
+			if opts.Tokenizer != tt.tokenizer {
+				t.Errorf("expected tokenizer %q, got %q", tt.tokenizer, opts.Tokenizer)
+			}
+		})
+	}
+}
+
+func TestMergeOptions_UnknownProfile(t *testing.T) {
+	config, err := parseConfig([]byte(layeredConfig))
+	if err != nil {
+		t.Fatalf("parseConfig failed: %v", err)
+	}
+	if _, err := MergeOptions(config, cliDefaults(), "nightly"); err == nil || !strings.Contains(err.Error(), `profile "nightly" not found`) {
+		t.Errorf("expected an unknown profile error, got %v", err)
+	}
+}
+
+func TestMergeOptions_Headers(t *testing.T) {
+	config, err := parseConfig([]byte(`
+headers: [title]
+overrides:
+  "docs/api/**":
+    headers: ["endpoint:Endpoint"]
+`))
+	if err != nil {
+		t.Fatalf("parseConfig failed: %v", err)
+	}
+	cli := cliDefaults()
+	cli.Headers = []HeaderField{{Path: "owner", Label: "owner"}}
+	opts, err := MergeOptions(config, cli, "")
+	if err != nil {
+		t.Fatalf("MergeOptions failed: %v", err)
+	}
+
+	paths := func(headers []HeaderField) string {
+		var out []string
+		for _, h := range headers {
+			out = append(out, h.Path)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := paths(opts.Headers); got != "title,owner" {
+		t.Errorf("expected CLI headers appended to config headers, got %s", got)
+	}
+	if got := paths(opts.ForFile("docs/api/users.md").Headers); got != "endpoint,owner" {
+		t.Errorf("expected override headers replaced and CLI headers appended, got %s", got)
+	}
+}
+
+const targetsConfig = `
+budget: 1200
+targets:
+  - name: small
+    budget: 400
+    tokenizer: word
+  - name: large
+    tokenizer: char
+overrides:
+  "docs/api/**":
+    budget: 2000
+    targets:
+      small:
+        budget: 500
+`
+
+func TestForFile_Targets(t *testing.T) {
+	config, err := parseConfig([]byte(targetsConfig))
+	if err != nil {
+		t.Fatalf("parseConfig failed: %v", err)
+	}
+	opts, err := MergeOptions(config, cliDefaults(), "")
+	if err != nil {
+		t.Fatalf("MergeOptions failed: %v", err)
+	}
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	budgets := func(o *ChunkyOptions) map[string]int {
+		out := make(map[string]int)
+		for _, t := range o.ResolveTargets() {
+			out[t.Name] = t.Budget
+		}
+		return out
+	}
+	if got := budgets(opts.ForFile("docs/guide.md")); got["small"] != 400 || got["large"] != 1200 {
+		t.Errorf("expected target budgets unchanged for unmatched files, got %v", got)
+	}
+	if got := budgets(opts.ForFile("docs/api/users.md")); got["small"] != 500 || got["large"] != 2000 {
+		t.Errorf("expected override budgets per target, got %v", got)
+	}
+	if got := budgets(opts); got["small"] != 400 {
+		t.Errorf("expected ForFile to leave the options unchanged, got %v", got)
+	}
+}
+
+func TestOverride_ShadowedByTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+		wantErr  string
+	}{
+		{
+			name:     "budget set by target",
+			override: "budget: 2000",
+			wantErr:  `override "docs/**": target "small" sets its own budget; set them under targets.small in the override`,
+		},
+		{
+			name:     "headers set by target",
+			override: "headers: [title]",
+			wantErr:  `target "small" sets its own headers`,
+		},
+		{
+			name:     "unknown target",
+			override: "targets:\n      medium:\n        budget: 500",
+			wantErr:  `override "docs/**": unknown target "medium"`,
+		},
+		{
+			name:     "inherited by every target",
+			override: "overhead: 0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := parseConfig([]byte("targets:\n  - name: small\n    budget: 400\n    headers: [owner]\n" +
+				"overrides:\n  \"docs/**\":\n    " + tt.override + "\n"))
+			if err != nil {
+				t.Fatalf("parseConfig failed: %v", err)
+			}
+			opts, err := MergeOptions(config, cliDefaults(), "")
+			if err != nil {
+				t.Fatalf("MergeOptions failed: %v", err)
+			}
+			err = opts.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMergeOptions_ClearTransforms(t *testing.T) {
+	config, err := parseConfig([]byte(`
+transforms:
+  fields:
+    - {path: owner, required: true}
+  defaults: {team: docs, tags: [guide]}
+profiles:
+  ci:
+    transforms:
+      defaults: {team: platform}
+overrides:
+  "drafts/**":
+    transforms:
+      fields: []
+      defaults: {}
+`))
+	if err != nil {
+		t.Fatalf("parseConfig failed: %v", err)
+	}
+	opts, err := MergeOptions(config, cliDefaults(), "ci")
+	if err != nil {
+		t.Fatalf("MergeOptions failed: %v", err)
+	}
+
+	if len(opts.Transforms.Fields) != 1 {
+		t.Errorf("expected config fields to be kept, got %v", opts.Transforms.Fields)
+	}
+	if got := opts.Transforms.Defaults; got["team"] != "platform" || got["tags"] == nil {
+		t.Errorf("expected profile defaults merged key by key, got %v", got)
+	}
+
+	drafts := opts.ForFile("drafts/idea.md").Transforms
+	if len(drafts.Fields) > 0 || len(drafts.Defaults) > 0 {
+		t.Errorf("expected empty fields and defaults to clear them, got %v and %v", drafts.Fields, drafts.Defaults)
+	}
+	if len(opts.ForFile("docs/guide.md").Transforms.Fields) != 1 {
+		t.Error("expected unmatched files to keep their fields")
+	}
+}
@@ -74,8 +74,18 @@ func groupChunksByFile(chunks []chunker.Chunk) ([]string, map[string][]chunker.C
 	return order, grouped
 }
 
+// printTailStats prints how small final chunks were handled, if any were found.
+func printTailStats(stats chunker.Stats) {
+	if stats == (chunker.Stats{}) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Small final chunks: %d rebalanced, %d left below minimum\n\n",
+		stats.TailsRebalanced, stats.TailsBelowMinimum)
+}
+
 // printChunkOutput prints colored output to stderr showing files and their chunks.
-func printChunkOutput(chunks []chunker.Chunk, effectiveBudget int) {
+// Budgets maps each source file path to its effective budget.
+func printChunkOutput(chunks []chunker.Chunk, budgets map[string]int) {
 	order, grouped := groupChunksByFile(chunks)
 
 	for _, filePath := range order {
@@ -88,7 +98,7 @@ func printChunkOutput(chunks []chunker.Chunk, effectiveBudget int) {
 			outputFilename := generateChunkFilename(chunk)
 
 			// Determine if chunk is jumbo
-			isJumbo := chunk.Tokens > effectiveBudget
+			isJumbo := chunk.Tokens > budgets[chunk.FilePath]
 
 			// Choose marker and color based on status
 			var marker, tokenStr string
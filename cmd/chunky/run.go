@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
+	"github.com/jwalton/gchalk"
 	"github.com/wyvernzora/chunky/pkg/chunker"
 )
 
@@ -14,7 +16,8 @@ import (
 type RunCmd struct {
 	ChunkyOptions
 
-	Files []string `arg:"" optional:"" help:"File globs to process"`
+	Files   []string `arg:"" optional:"" help:"File globs to process"`
+	Profile string   `help:"Named profile from .chunkyrc to apply" short:"p"`
 }
 
 // Run executes the main chunking command.
@@ -41,7 +44,10 @@ func (r *RunCmd) Run() error {
 	}
 
 	// Merge CLI options with config
-	opts := MergeOptions(configOpts, &r.ChunkyOptions)
+	opts, err := MergeOptions(configOpts, &r.ChunkyOptions, r.Profile)
+	if err != nil {
+		return err
+	}
 
 	// Validate options
 	if err := opts.Validate(); err != nil {
@@ -62,78 +68,118 @@ func (r *RunCmd) Run() error {
 		opts.Print(projectRoot, files)
 	}
 
-	// Create tokenizer
-	tok, err := createTokenizer(opts.Tokenizer)
-	if err != nil {
-		return fmt.Errorf("failed to create tokenizer: %w", err)
-	}
+	// Resolve per-file options; files matching the same overrides share a chunker
+	fileOpts := make(map[string]*ChunkyOptions, len(files))
+	chunkers := make(map[string]chunker.MultiChunker)
+	fileChunker := make(map[string]chunker.MultiChunker, len(files))
+	for _, file := range files {
+		key := fmt.Sprint(opts.matchOverrides(file))
+		fopts := opts.ForFile(file)
+		fileOpts[file] = fopts
+
+		if c, ok := chunkers[key]; ok {
+			fileChunker[file] = c
+			continue
+		}
 
-	// Create header generator
-	headerGen := createHeaderGenerator(opts.Headers)
+		if err := fopts.Validate(); err != nil {
+			return fmt.Errorf("invalid options for %s: %w", file, err)
+		}
+		c, err := createChunker(projectRoot, fopts)
+		if err != nil {
+			return err
+		}
+		chunkers[key] = c
+		fileChunker[file] = c
+	}
 
-	// Create chunker
-	c, err := chunker.New(
-		chunker.WithChunkTokenBudget(opts.Budget),
-		chunker.WithReservedOverheadRatio(opts.Overhead),
-		chunker.WithTokenizer(tok),
-		chunker.WithChunkHeader(headerGen),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create chunker: %w", err)
+	// Process all files, collecting chunks per target in file order
+	targets := opts.ResolveTargets()
+	results := make(map[string][]chunker.Chunk, len(targets))
+	stats := make(map[string]chunker.Stats, len(targets))
+	budgets := make(map[string]map[string]int, len(targets))
+	for _, t := range targets {
+		budgets[t.Name] = make(map[string]int, len(files))
 	}
 
-	// Process all files
 	ctx := context.Background()
 	if opts.Verbose {
 		fmt.Println("\nProcessing files...")
-		for _, file := range files {
+	}
+	for _, file := range files {
+		if opts.Verbose {
 			fmt.Printf("  - %s\n", file)
-			if err := processFile(ctx, projectRoot, file, c); err != nil {
-				return fmt.Errorf("error processing %s: %w", file, err)
+		}
+		c := fileChunker[file]
+		if err := processFile(ctx, projectRoot, file, c); err != nil {
+			return fmt.Errorf("error processing %s: %w", file, err)
+		}
+		for _, t := range targets {
+			results[t.Name] = append(results[t.Name], c.Chunks(t.Name)...)
+			stats[t.Name] = stats[t.Name].Add(c.Stats(t.Name))
+			budgets[t.Name][file] = c.EffectiveBudget(t.Name)
+		}
+		c.Reset()
+	}
+
+	// Check for jumbo chunks in every target
+	strictViolation := false
+	for _, t := range targets {
+		var jumboChunks []chunker.Chunk
+		for _, chunk := range results[t.Name] {
+			if chunk.Tokens > budgets[t.Name][chunk.FilePath] {
+				jumboChunks = append(jumboChunks, chunk)
+				if fileOpts[chunk.FilePath].Strict {
+					strictViolation = true
+				}
 			}
 		}
-	} else {
-		for _, file := range files {
-			if err := processFile(ctx, projectRoot, file, c); err != nil {
-				return fmt.Errorf("error processing %s: %w", file, err)
+
+		if len(jumboChunks) > 0 && opts.Verbose {
+			fmt.Fprintf(os.Stderr, "\n⚠ Warning: Found %d jumbo chunk(s) exceeding effective budget%s:\n", len(jumboChunks), targetSuffix(opts, t.Name))
+			for _, chunk := range jumboChunks {
+				fmt.Fprintf(os.Stderr, "  - %s (chunk %d): %d tokens, budget %d\n", chunk.FilePath, chunk.ChunkIndex, chunk.Tokens, budgets[t.Name][chunk.FilePath])
 			}
 		}
 	}
+	if strictViolation {
+		return fmt.Errorf("strict mode enabled: aborting due to jumbo chunks")
+	}
 
-	// Get chunks
-	chunks := c.Chunks()
-	effectiveBudget := c.EffectiveBudget()
+	for _, t := range targets {
+		chunks := results[t.Name]
 
-	// Check for jumbo chunks if strict mode is enabled
-	var jumboChunks []chunker.Chunk
-	for _, chunk := range chunks {
-		if chunk.Tokens > effectiveBudget {
-			jumboChunks = append(jumboChunks, chunk)
+		// Print chunk output to stderr
+		if len(opts.Targets) > 0 {
+			fmt.Fprintf(os.Stderr, "%s\n\n", gchalk.WithInverse().Bold(" target: "+t.Name+" "))
 		}
-	}
+		printChunkOutput(chunks, budgets[t.Name])
+		printTailStats(stats[t.Name])
 
-	if len(jumboChunks) > 0 {
-		if opts.Verbose {
-			fmt.Fprintf(os.Stderr, "\n⚠ Warning: Found %d jumbo chunk(s) exceeding effective budget of %d tokens:\n", len(jumboChunks), effectiveBudget)
-			for _, chunk := range jumboChunks {
-				fmt.Fprintf(os.Stderr, "  - %s (chunk %d): %d tokens\n", chunk.FilePath, chunk.ChunkIndex, chunk.Tokens)
-			}
+		// Skip file writes if in dry run mode
+		if opts.DryRun {
+			continue
 		}
-		if opts.Strict {
-			return fmt.Errorf("strict mode enabled: aborting due to jumbo chunks")
+
+		if err := writeChunks(projectRoot, t.OutDir, chunks); err != nil {
+			return fmt.Errorf("target %q: %w", t.Name, err)
 		}
 	}
 
-	// Print chunk output to stderr
-	printChunkOutput(chunks, effectiveBudget)
+	return nil
+}
 
-	// Skip file writes if in dry run mode
-	if opts.DryRun {
-		return nil
+// targetSuffix returns a " in target X" suffix for messages when targets are configured.
+func targetSuffix(opts *ChunkyOptions, name string) string {
+	if len(opts.Targets) == 0 {
+		return ""
 	}
+	return fmt.Sprintf(" in target %q", name)
+}
 
-	// Write files
-	absOutDir := opts.OutDir
+// writeChunks writes chunk files into outDir, resolved relative to projectRoot.
+func writeChunks(projectRoot, outDir string, chunks []chunker.Chunk) error {
+	absOutDir := outDir
 	if !filepath.IsAbs(absOutDir) {
 		absOutDir = filepath.Join(projectRoot, absOutDir)
 	}
@@ -150,6 +196,14 @@ func (r *RunCmd) Run() error {
 		if err := os.WriteFile(outPath, []byte(chunk.Text), 0644); err != nil {
 			return fmt.Errorf("failed to write chunk file %s: %w", filename, err)
 		}
+
+		// Write the plain-text rendition next to it, if enabled
+		if chunk.PlainText != "" {
+			plainPath := strings.TrimSuffix(outPath, filepath.Ext(outPath)) + ".txt"
+			if err := os.WriteFile(plainPath, []byte(chunk.PlainText), 0644); err != nil {
+				return fmt.Errorf("failed to write plain-text chunk file %s: %w", filepath.Base(plainPath), err)
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+)
+
+// configSchema builds a JSON Schema (draft 2020-12) describing .chunkyrc.
+// Properties are derived from the yaml and help tags of ChunkyOptions, so the
+// schema stays in sync with the strict decoder in validate.go.
+func configSchema() map[string]any {
+	g := &schemaGenerator{defs: make(map[string]any)}
+
+	root := g.object(reflect.TypeFor[ChunkyOptions]())
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = ConfigFileName
+	root["description"] = "Chunky configuration file"
+	root["$defs"] = g.defs
+	return root
+}
+
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+// schema returns the schema for a Go type.
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == reflect.TypeFor[HeaderField]():
+		return g.ref(t, func() map[string]any {
+			return map[string]any{
+				"anyOf": []any{
+					map[string]any{
+						"type":        "string",
+						"description": `Compact form "path", "path!", "path:Label" or "path!:Label"; "!" marks the field required`,
+						"minLength":   1,
+					},
+					g.object(t),
+				},
+			}
+		})
+
+	case t == overridesType:
+		return map[string]any{
+			"type":                 "object",
+			"description":          "Per-glob overrides, applied in document order",
+			"additionalProperties": g.schema(reflect.TypeFor[Override]()),
+		}
+
+	case t.Kind() == reflect.Struct:
+		return g.ref(t, func() map[string]any { return g.object(t) })
+
+	case t.Kind() == reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": g.schema(t.Elem()),
+		}
+
+	case t.Kind() == reflect.Slice:
+		return map[string]any{
+			"type":  "array",
+			"items": g.schema(t.Elem()),
+		}
+
+	case t.Kind() == reflect.Interface:
+		return map[string]any{}
+
+	case t.Kind() == reflect.Bool:
+		return map[string]any{"type": "boolean"}
+
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		return map[string]any{"type": "integer"}
+
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]any{"type": "number"}
+
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// ref registers a named type under $defs and returns a reference to it.
+func (g *schemaGenerator) ref(t reflect.Type, build func() map[string]any) map[string]any {
+	if _, ok := g.defs[t.Name()]; !ok {
+		g.defs[t.Name()] = map[string]any{} // placeholder for recursive types
+		g.defs[t.Name()] = build()
+	}
+	return map[string]any{"$ref": "#/$defs/" + t.Name()}
+}
+
+// object returns an object schema for a struct type.
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	for name, f := range yamlFields(t) {
+		s := g.schema(f.Type)
+		if help := f.Tag.Get("help"); help != "" {
+			s["description"] = help
+		}
+		props[name] = s
+	}
+	return map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
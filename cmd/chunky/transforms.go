@@ -0,0 +1,437 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/wyvernzora/chunky/pkg/chunker"
+	"github.com/wyvernzora/chunky/pkg/derive"
+	dbuiltin "github.com/wyvernzora/chunky/pkg/derive/builtin"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	fmbuiltin "github.com/wyvernzora/chunky/pkg/frontmatter/builtin"
+	"github.com/wyvernzora/chunky/pkg/frontmatter/schema"
+	"github.com/wyvernzora/chunky/pkg/markdown"
+	pbuiltin "github.com/wyvernzora/chunky/pkg/parser/builtin"
+	sbuiltin "github.com/wyvernzora/chunky/pkg/section/builtin"
+)
+
+// TransformOptions enables builtin transforms from .chunkyrc.
+// Pointer fields distinguish "unset" from "disabled" so that profiles and
+// overrides can turn a transform off again.
+type TransformOptions struct {
+	// RequireSummary fails documents whose front matter lacks a non-empty summary.
+	// It is shorthand for a required string rule on "summary" in Fields.
+	RequireSummary *bool `yaml:"requireSummary,omitempty" help:"Fail documents without a summary"`
+
+	// Fields declares required fields, types, defaults and coercions by dot path.
+	// Fields in a profile or override replace the rules beneath them; an
+	// empty list (fields: []) removes them.
+	Fields []FieldOptions `yaml:"fields,omitempty" help:"Front matter field rules"`
+
+	// DirectoryMeta names the per-directory metadata file (e.g. "_meta.yaml") whose
+	// values are inherited by every document beneath it. Empty disables inheritance.
+	DirectoryMeta *string `yaml:"directoryMeta,omitempty" help:"Per-directory front matter file to inherit, e.g. _meta.yaml"`
+
+	// Git injects git_* keys with the blob hash and last commit of each document.
+	Git *bool `yaml:"git,omitempty" help:"Inject git blob hash, last commit, author and date"`
+
+	// GitCreated additionally injects git_created, the date of the first commit.
+	GitCreated *bool `yaml:"gitCreated,omitempty" help:"Also inject the first commit date (requires git)"`
+
+	// Schema is the path, relative to the project root, of a JSON Schema that
+	// every document's front matter is validated against.
+	Schema *string `yaml:"schema,omitempty" help:"JSON Schema file to validate front matter against"`
+
+	// SchemaMode is "fail" (default) to fail invalid documents or "warn" to log violations.
+	SchemaMode *string `yaml:"schemaMode,omitempty" help:"Schema violation handling: fail or warn"`
+
+	// Defaults are merged into front matter without overwriting existing keys.
+	// An empty map (defaults: {}) in a profile or override drops the defaults
+	// beneath it.
+	Defaults map[string]any `yaml:"defaults,omitempty" help:"Front matter defaults; existing keys win"`
+
+	// PromoteTitle makes a lone leading H1 the document root, so its subsections
+	// are no longer nested beneath it and breadcrumbs do not repeat the title.
+	PromoteTitle *bool `yaml:"promoteTitle,omitempty" help:"Use a lone leading H1 as the document root"`
+
+	// NestedHeadings treats headings inside block quotes and list items as
+	// sections. By default they stay part of the surrounding content.
+	NestedHeadings *bool `yaml:"nestedHeadings,omitempty" help:"Treat headings in block quotes and list items as sections"`
+
+	// Derive lists front matter fields computed from the document body. Unset
+	// derives nothing, except the title when PromoteTitle is on; an empty list
+	// turns derivation off entirely, so documents keep their file name as title.
+	Derive []string `yaml:"derive,omitempty" help:"Fields derived from the document: title, description, wordCount, readingTime, outline, language"`
+
+	// Markdown lists the goldmark extensions used to recognize Markdown structure
+	// in the parser and all transforms. Unset enables gfm, footnote and
+	// definitionList; an empty list parses plain CommonMark.
+	Markdown []string `yaml:"markdown,omitempty" help:"Markdown extensions: gfm, footnote, definitionList, typographer"`
+
+	// Tables selects how GFM tables are handled: "split" splits tables that do
+	// not fit into a chunk between rows, repeating the header rows in every
+	// piece; "records" rewrites tables as "Column: value" records. Unset or
+	// "keep" leaves tables as they are.
+	Tables *string `yaml:"tables,omitempty" help:"Table handling: keep, split or records"`
+
+	// SplitCode splits fenced code blocks that do not fit into a chunk into
+	// well-formed blocks, cutting before top-level declarations where possible.
+	SplitCode *bool `yaml:"splitCode,omitempty" help:"Split oversized fenced code blocks"`
+
+	// MaxCodeTokens replaces fenced code blocks measuring more than this many
+	// tokens with CodePlaceholder. Tokens are counted with the top-level
+	// tokenizer. 0 keeps all code blocks.
+	MaxCodeTokens *int `yaml:"maxCodeTokens,omitempty" help:"Replace code blocks larger than this many tokens with a placeholder"`
+
+	// CodePlaceholder is the line replacing code blocks over MaxCodeTokens, in
+	// which "{language}" and "{lines}" are substituted. Unset uses a default
+	// note; an empty string drops the blocks.
+	CodePlaceholder *string `yaml:"codePlaceholder,omitempty" help:"Placeholder for omitted code blocks"`
+
+	// ResolveReferences inlines reference-style links and copies footnote
+	// definitions to the sections citing them, so that chunks keep the link
+	// targets and footnotes of the sections they hold.
+	ResolveReferences *bool `yaml:"resolveReferences,omitempty" help:"Inline reference links and footnotes into the sections using them"`
+
+	// PlainText also renders every chunk as plain text without markdown syntax,
+	// written next to the chunk file with a .txt extension.
+	PlainText *bool `yaml:"plainText,omitempty" help:"Also write a plain-text rendition of every chunk"`
+
+	// PlainTextLinks keeps link URLs after the link text in the plain-text
+	// rendition.
+	PlainTextLinks *bool `yaml:"plainTextLinks,omitempty" help:"Keep link URLs in the plain-text rendition"`
+
+	// PlainTextBudget measures chunks by their plain-text rendition, for when
+	// it is what gets embedded. Implies PlainText.
+	PlainTextBudget *bool `yaml:"plainTextBudget,omitempty" help:"Apply token budgets to the plain-text rendition"`
+
+	// MergeBelow merges sections without subsections that measure fewer tokens
+	// than this into the section before them, keeping their headings inline.
+	// Tokens are counted with the top-level tokenizer. 0 disables merging.
+	MergeBelow *int `yaml:"mergeBelow,omitempty" help:"Merge sections smaller than this many tokens into their neighbors"`
+}
+
+// transforms.tables modes.
+const (
+	tablesKeep    = "keep"
+	tablesSplit   = "split"
+	tablesRecords = "records"
+)
+
+// deriveTransforms maps transforms.derive names to their builtins.
+var deriveTransforms = map[string]func() derive.Transform{
+	"title":       func() derive.Transform { return dbuiltin.Title("title") },
+	"description": func() derive.Transform { return dbuiltin.Description("description") },
+	"wordCount":   func() derive.Transform { return dbuiltin.WordCount("word_count") },
+	"readingTime": func() derive.Transform { return dbuiltin.ReadingTime("reading_time", dbuiltin.DefaultWordsPerMinute) },
+	"outline":     func() derive.Transform { return dbuiltin.Outline("outline", 0) },
+	"language":    func() derive.Transform { return dbuiltin.Language("language") },
+}
+
+// FieldOptions is a front matter field rule in .chunkyrc.
+type FieldOptions struct {
+	Path     string   `yaml:"path" help:"Field path, e.g. metadata.owner or authors[0].name"`
+	Type     string   `yaml:"type,omitempty" help:"Expected type: string, number, integer, bool, list, map or date"`
+	Required bool     `yaml:"required,omitempty" help:"Fail documents where the field is missing or empty"`
+	Default  any      `yaml:"default,omitempty" help:"Value to set when the field is missing"`
+	Coerce   []string `yaml:"coerce,omitempty" help:"Conversions applied in order: split, rfc3339, lowercase"`
+}
+
+// rule converts the options into a builtin field rule.
+func (f FieldOptions) rule() (fmbuiltin.FieldRule, error) {
+	if f.Path == "" {
+		return fmbuiltin.FieldRule{}, fmt.Errorf("path is required")
+	}
+	if err := fm.ValidatePath(f.Path); err != nil {
+		return fmbuiltin.FieldRule{}, err
+	}
+	typ, err := fmbuiltin.ParseFieldType(f.Type)
+	if err != nil {
+		return fmbuiltin.FieldRule{}, err
+	}
+	rule := fmbuiltin.FieldRule{Path: f.Path, Type: typ, Required: f.Required, Default: f.Default}
+	for _, name := range f.Coerce {
+		c, err := fmbuiltin.ParseCoercion(name)
+		if err != nil {
+			return fmbuiltin.FieldRule{}, err
+		}
+		rule.Coerce = append(rule.Coerce, c)
+	}
+	return rule, nil
+}
+
+// merge returns a copy of t with every set field of other applied on top.
+// Defaults maps are merged key by key, with keys from other winning; an
+// empty but non-nil Fields or Defaults in other clears the value beneath it.
+func (t TransformOptions) merge(other *TransformOptions) TransformOptions {
+	out := t
+	out.Defaults = maps.Clone(t.Defaults)
+	if other == nil {
+		return out
+	}
+	if other.DirectoryMeta != nil {
+		out.DirectoryMeta = other.DirectoryMeta
+	}
+	if other.Git != nil {
+		out.Git = other.Git
+	}
+	if other.GitCreated != nil {
+		out.GitCreated = other.GitCreated
+	}
+	if other.Schema != nil {
+		out.Schema = other.Schema
+	}
+	if other.SchemaMode != nil {
+		out.SchemaMode = other.SchemaMode
+	}
+	if other.RequireSummary != nil {
+		out.RequireSummary = other.RequireSummary
+	}
+	if other.Fields != nil {
+		out.Fields = slices.Clone(other.Fields)
+	}
+	if other.PromoteTitle != nil {
+		out.PromoteTitle = other.PromoteTitle
+	}
+	if other.NestedHeadings != nil {
+		out.NestedHeadings = other.NestedHeadings
+	}
+	if other.Derive != nil {
+		out.Derive = slices.Clone(other.Derive)
+	}
+	if other.Markdown != nil {
+		out.Markdown = slices.Clone(other.Markdown)
+	}
+	if other.Tables != nil {
+		out.Tables = other.Tables
+	}
+	if other.SplitCode != nil {
+		out.SplitCode = other.SplitCode
+	}
+	if other.MaxCodeTokens != nil {
+		out.MaxCodeTokens = other.MaxCodeTokens
+	}
+	if other.CodePlaceholder != nil {
+		out.CodePlaceholder = other.CodePlaceholder
+	}
+	if other.ResolveReferences != nil {
+		out.ResolveReferences = other.ResolveReferences
+	}
+	if other.PlainText != nil {
+		out.PlainText = other.PlainText
+	}
+	if other.PlainTextLinks != nil {
+		out.PlainTextLinks = other.PlainTextLinks
+	}
+	if other.PlainTextBudget != nil {
+		out.PlainTextBudget = other.PlainTextBudget
+	}
+	if other.MergeBelow != nil {
+		out.MergeBelow = other.MergeBelow
+	}
+	if other.Defaults != nil && len(other.Defaults) == 0 {
+		out.Defaults = nil
+	} else if len(other.Defaults) > 0 {
+		if out.Defaults == nil {
+			out.Defaults = make(map[string]any, len(other.Defaults))
+		}
+		maps.Copy(out.Defaults, other.Defaults)
+	}
+	return out
+}
+
+// deriveNames returns the derived fields to compute. When Derive is unset,
+// only a promoted title is derived, since the promoted H1 is the document title.
+func (t TransformOptions) deriveNames() []string {
+	if t.Derive == nil && t.PromoteTitle != nil && *t.PromoteTitle {
+		return []string{"title"}
+	}
+	return t.Derive
+}
+
+// validate checks transform settings that can be verified without reading files.
+func (t TransformOptions) validate() error {
+	if t.SchemaMode != nil {
+		if _, err := fmbuiltin.ParseSchemaMode(*t.SchemaMode); err != nil {
+			return fmt.Errorf("transforms.schemaMode: %w", err)
+		}
+	}
+	for i, f := range t.Fields {
+		if _, err := f.rule(); err != nil {
+			return fmt.Errorf("transforms.fields[%d]: %w", i, err)
+		}
+	}
+	for i, name := range t.Derive {
+		if _, ok := deriveTransforms[name]; !ok {
+			return fmt.Errorf("transforms.derive[%d]: unknown derived field %q", i, name)
+		}
+	}
+	for i, name := range t.Markdown {
+		if _, err := markdown.ParseExtension(name); err != nil {
+			return fmt.Errorf("transforms.markdown[%d]: %w", i, err)
+		}
+	}
+	if t.Tables != nil {
+		switch *t.Tables {
+		case tablesKeep, tablesSplit, tablesRecords:
+		default:
+			return fmt.Errorf("transforms.tables: must be %q, %q or %q, got %q", tablesKeep, tablesSplit, tablesRecords, *t.Tables)
+		}
+	}
+	if t.MaxCodeTokens != nil && *t.MaxCodeTokens < 0 {
+		return fmt.Errorf("transforms.maxCodeTokens: must be >= 0, got %d", *t.MaxCodeTokens)
+	}
+	if t.MergeBelow != nil && *t.MergeBelow < 0 {
+		return fmt.Errorf("transforms.mergeBelow: must be >= 0, got %d", *t.MergeBelow)
+	}
+	return nil
+}
+
+// createTransformOptions converts transform settings into chunker options.
+// Transforms are appended after the chunker defaults. Directory metadata is
+// merged before config defaults so that nearer, more specific values win,
+// and schema validation runs last so that it sees the final front matter.
+// Derived fields are computed before any of them, right after parsing.
+// Tiny sections and large code blocks are measured with the named tokenizer,
+// which is only created when needed.
+func createTransformOptions(projectRoot, tokenizerName string, t TransformOptions) ([]chunker.Option, error) {
+	var opts []chunker.Option
+	if t.Markdown != nil {
+		exts := make([]markdown.Extension, 0, len(t.Markdown))
+		for _, name := range t.Markdown {
+			ext, err := markdown.ParseExtension(name)
+			if err != nil {
+				return nil, fmt.Errorf("transforms.markdown: %w", err)
+			}
+			exts = append(exts, ext)
+		}
+		md, err := markdown.New(exts...)
+		if err != nil {
+			return nil, fmt.Errorf("transforms.markdown: %w", err)
+		}
+		opts = append(opts, chunker.WithMarkdown(md))
+	}
+	var parserOpts []pbuiltin.Option
+	if t.PromoteTitle != nil && *t.PromoteTitle {
+		parserOpts = append(parserOpts, pbuiltin.WithPromotedTitle())
+	}
+	if t.NestedHeadings != nil && *t.NestedHeadings {
+		parserOpts = append(parserOpts, pbuiltin.WithNestedHeadings())
+	}
+	if len(parserOpts) > 0 {
+		opts = append(opts, chunker.WithParser(pbuiltin.NewParser(parserOpts...)))
+	}
+	for _, name := range t.deriveNames() {
+		newTransform, ok := deriveTransforms[name]
+		if !ok {
+			return nil, fmt.Errorf("transforms.derive: unknown derived field %q", name)
+		}
+		opts = append(opts, chunker.WithDeriveTransform(newTransform()))
+	}
+	if t.DirectoryMeta != nil && *t.DirectoryMeta != "" {
+		opts = append(opts, chunker.WithFrontMatterTransform(fmbuiltin.InheritDirectoryMeta(
+			os.DirFS(projectRoot),
+			fmbuiltin.WithMetaFileName(*t.DirectoryMeta),
+		)))
+	}
+	if t.Git != nil && *t.Git {
+		var gitOpts []fmbuiltin.GitMetadataOption
+		if t.GitCreated != nil && *t.GitCreated {
+			gitOpts = append(gitOpts, fmbuiltin.WithGitCreatedDate())
+		}
+		opts = append(opts, chunker.WithFrontMatterTransform(fmbuiltin.GitMetadata(projectRoot, gitOpts...)))
+	}
+	if len(t.Defaults) > 0 {
+		opts = append(opts, chunker.WithFrontMatterTransform(fmbuiltin.MergeFrontMatter(t.Defaults)))
+	}
+	if len(t.Fields) > 0 {
+		rules := make([]fmbuiltin.FieldRule, 0, len(t.Fields))
+		for i, f := range t.Fields {
+			rule, err := f.rule()
+			if err != nil {
+				return nil, fmt.Errorf("transforms.fields[%d]: %w", i, err)
+			}
+			rules = append(rules, rule)
+		}
+		opts = append(opts, chunker.WithFrontMatterTransform(fmbuiltin.EnforceFields(rules...)))
+	}
+	if t.RequireSummary != nil && *t.RequireSummary {
+		opts = append(opts, chunker.WithFrontMatterTransform(fmbuiltin.EnforceFields(
+			fmbuiltin.FieldRule{Path: "summary", Type: fmbuiltin.FieldString, Required: true},
+		)))
+	}
+	if t.Schema != nil && *t.Schema != "" {
+		transform, err := createSchemaTransform(projectRoot, *t.Schema, t.SchemaMode)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, chunker.WithFrontMatterTransform(transform))
+	}
+	if t.ResolveReferences != nil && *t.ResolveReferences {
+		opts = append(opts, chunker.WithTreeTransform(sbuiltin.ResolveReferencesTransform()))
+	}
+	if t.MergeBelow != nil && *t.MergeBelow > 0 {
+		tok, err := createTokenizer(tokenizerName)
+		if err != nil {
+			return nil, fmt.Errorf("transforms.mergeBelow: %w", err)
+		}
+		opts = append(opts, chunker.WithTreeTransform(sbuiltin.MergeTinySectionsTransform(tok, *t.MergeBelow)))
+	}
+	if t.Tables != nil {
+		switch *t.Tables {
+		case tablesSplit:
+			opts = append(opts, chunker.WithTableSplitting())
+		case tablesRecords:
+			opts = append(opts, chunker.WithSectionTransform(sbuiltin.TableRecordsTransform()))
+		}
+	}
+	if t.MaxCodeTokens != nil && *t.MaxCodeTokens > 0 {
+		tok, err := createTokenizer(tokenizerName)
+		if err != nil {
+			return nil, fmt.Errorf("transforms.maxCodeTokens: %w", err)
+		}
+		placeholder := sbuiltin.DefaultCodePlaceholder
+		if t.CodePlaceholder != nil {
+			placeholder = *t.CodePlaceholder
+		}
+		opts = append(opts, chunker.WithSectionTransform(sbuiltin.OmitLargeCodeBlocksTransform(tok, *t.MaxCodeTokens, placeholder)))
+	}
+	if t.SplitCode != nil && *t.SplitCode {
+		opts = append(opts, chunker.WithCodeSplitting())
+	}
+	budget := t.PlainTextBudget != nil && *t.PlainTextBudget
+	if budget || (t.PlainText != nil && *t.PlainText) {
+		opts = append(opts, chunker.WithPlainText(chunker.PlainTextOptions{
+			LinkURLs: t.PlainTextLinks != nil && *t.PlainTextLinks,
+			Budget:   budget,
+		}))
+	}
+	return opts, nil
+}
+
+// createSchemaTransform loads and compiles a front matter schema file.
+func createSchemaTransform(projectRoot, path string, mode *string) (fm.Transform, error) {
+	schemaMode := fmbuiltin.SchemaModeFail
+	if mode != nil {
+		var err error
+		if schemaMode, err = fmbuiltin.ParseSchemaMode(*mode); err != nil {
+			return nil, err
+		}
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read front matter schema: %w", err)
+	}
+	s, err := schema.Compile(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid front matter schema %s: %w", path, err)
+	}
+	return fmbuiltin.ValidateSchema(s, schemaMode), nil
+}
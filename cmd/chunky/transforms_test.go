@@ -0,0 +1,28 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTransformOptions_DeriveNames(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name string
+		opts TransformOptions
+		want []string
+	}{
+		{"unset", TransformOptions{}, nil},
+		{"promoted title", TransformOptions{PromoteTitle: &on}, []string{"title"}},
+		{"title not promoted", TransformOptions{PromoteTitle: &off}, nil},
+		{"explicit", TransformOptions{PromoteTitle: &on, Derive: []string{"wordCount"}}, []string{"wordCount"}},
+		{"disabled", TransformOptions{PromoteTitle: &on, Derive: []string{}}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.deriveNames(); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
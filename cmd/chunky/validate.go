@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError describes a single problem found in a config file.
+type ConfigError struct {
+	Line    int    // 1-indexed line number in the config file
+	Column  int    // 1-indexed column number in the config file
+	Path    string // dot path of the offending key, e.g. "targets[0].budget"
+	Message string // human-readable description, including suggestions
+}
+
+// Error implements error.
+func (e ConfigError) Error() string {
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	overridesType       = reflect.TypeFor[Overrides]()
+)
+
+// validateConfigNode checks a parsed .chunkyrc document against the ChunkyOptions
+// type and returns every unknown key and type mismatch it finds.
+func validateConfigNode(doc *yaml.Node) []ConfigError {
+	node := doc
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+
+	var errs []ConfigError
+	validateValue(node, reflect.TypeFor[ChunkyOptions](), "", &errs)
+	return errs
+}
+
+// validateValue checks node against type t, appending problems to errs.
+func validateValue(node *yaml.Node, t reflect.Type, path string, errs *[]ConfigError) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Tag == "!!null" {
+		return
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Scalars that decode through UnmarshalText (e.g. "title!:Title" header specs)
+	if node.Kind == yaml.ScalarNode && reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		v := reflect.New(t).Interface().(encoding.TextUnmarshaler)
+		if err := v.UnmarshalText([]byte(node.Value)); err != nil {
+			addConfigError(errs, node, path, "%v", err)
+		}
+		return
+	}
+
+	switch {
+	case t == overridesType:
+		if !expectKind(node, yaml.MappingNode, path, "a mapping of glob to options", errs) {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			validateValue(node.Content[i+1], reflect.TypeFor[Override](), joinPath(path, fmt.Sprintf("%q", key)), errs)
+		}
+
+	case t.Kind() == reflect.Struct:
+		if !expectKind(node, yaml.MappingNode, path, "a mapping", errs) {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				msg := fmt.Sprintf("unknown key %q", key.Value)
+				if s := suggestKey(key.Value, fields); s != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", s)
+				}
+				addConfigError(errs, key, joinPath(path, key.Value), "%s", msg)
+				continue
+			}
+			validateValue(value, field.Type, joinPath(path, key.Value), errs)
+		}
+
+	case t.Kind() == reflect.Map:
+		if !expectKind(node, yaml.MappingNode, path, "a mapping", errs) {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			validateValue(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value), errs)
+		}
+
+	case t.Kind() == reflect.Slice:
+		if !expectKind(node, yaml.SequenceNode, path, "a list", errs) {
+			return
+		}
+		for i, item := range node.Content {
+			validateValue(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+
+	case t.Kind() == reflect.Interface:
+		// Arbitrary values (e.g. front matter defaults) are accepted as-is
+
+	default:
+		validateScalar(node, t, path, errs)
+	}
+}
+
+// validateScalar checks that a scalar node can be decoded into the scalar type t.
+func validateScalar(node *yaml.Node, t reflect.Type, path string, errs *[]ConfigError) {
+	if node.Kind != yaml.ScalarNode {
+		addConfigError(errs, node, path, "expected %s, got %s", scalarName(t), nodeKindName(node))
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if node.Tag != "!!bool" {
+			addConfigError(errs, node, path, "expected a boolean, got %q (use true or false)", node.Value)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if node.Tag != "!!int" {
+			hint := "use a whole number, e.g. 1000"
+			if strings.ContainsAny(node.Value, ",_") {
+				hint = "remove digit separators"
+			}
+			addConfigError(errs, node, path, "expected an integer, got %q (%s)", node.Value, hint)
+		}
+	case reflect.Float32, reflect.Float64:
+		if node.Tag != "!!int" && node.Tag != "!!float" {
+			hint := "use a decimal number, e.g. 0.05"
+			if strings.HasSuffix(node.Value, "%") {
+				hint = "use a fraction instead of a percentage, e.g. 0.2 for 20%"
+			}
+			addConfigError(errs, node, path, "expected a number, got %q (%s)", node.Value, hint)
+		}
+	case reflect.String:
+		// Any scalar decodes into a string
+	}
+}
+
+// expectKind reports whether node has the wanted kind, recording an error if not.
+func expectKind(node *yaml.Node, kind yaml.Kind, path, want string, errs *[]ConfigError) bool {
+	if node.Kind == kind {
+		return true
+	}
+	hint := ""
+	if kind == yaml.SequenceNode && node.Kind == yaml.ScalarNode {
+		hint = fmt.Sprintf(" (use a YAML list, e.g. [%s])", node.Value)
+	}
+	addConfigError(errs, node, path, "expected %s, got %s%s", want, nodeKindName(node), hint)
+	return false
+}
+
+func addConfigError(errs *[]ConfigError, node *yaml.Node, path, format string, args ...any) {
+	if path == "" {
+		path = "(root)"
+	}
+	*errs = append(*errs, ConfigError{
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// yamlFields returns the exported fields of struct type t keyed by YAML name.
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := yamlName(f)
+		if name == "" {
+			continue
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// yamlName returns the YAML key for a struct field, or "" if the field is skipped.
+func yamlName(f reflect.StructField) string {
+	tag := f.Tag.Get("yaml")
+	name, _, _ := strings.Cut(tag, ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return strings.ToLower(f.Name)
+	default:
+		return name
+	}
+}
+
+// suggestKey returns the known key closest to an unknown one, or "" if none is close.
+func suggestKey(key string, fields map[string]reflect.StructField) string {
+	best, bestDist := "", -1
+	for name := range fields {
+		d := editDistance(strings.ToLower(key), strings.ToLower(name))
+		if bestDist < 0 || d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	if bestDist < 0 || bestDist > max(2, len(key)/3) {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func nodeKindName(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return fmt.Sprintf("%q", node.Value)
+	}
+}
+
+func scalarName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	default:
+		return "a string"
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig_Problems(t *testing.T) {
+	config := `budgte: 1000
+overhead: 20%
+targets:
+  - name: small
+    budget: 1,000
+    tokenzier: word
+files: docs/*.md
+headers:
+  - title!:Title
+  - ""
+strict: yes
+overrides:
+  "docs/**":
+    outDir: x
+transforms:
+  splitCode: 2
+`
+	_, err := parseConfig([]byte(config))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	want := []string{
+		".chunkyrc has 9 problem(s):",
+		`.chunkyrc:1:1: budgte: unknown key "budgte" (did you mean "budget"?)`,
+		`.chunkyrc:2:11: overhead: expected a number, got "20%" (use a fraction instead of a percentage, e.g. 0.2 for 20%)`,
+		`.chunkyrc:5:13: targets[0].budget: expected an integer, got "1,000" (remove digit separators)`,
+		`.chunkyrc:6:5: targets[0].tokenzier: unknown key "tokenzier" (did you mean "tokenizer"?)`,
+		`.chunkyrc:7:8: files: expected a list, got "docs/*.md" (use a YAML list, e.g. [docs/*.md])`,
+		`.chunkyrc:10:5: headers[1]: empty header field specification`,
+		`.chunkyrc:11:9: strict: expected a boolean, got "yes" (use true or false)`,
+		`.chunkyrc:14:5: overrides."docs/**".outDir: unknown key "outDir"`,
+		`.chunkyrc:16:14: transforms.splitCode: expected a boolean, got "2" (use true or false)`,
+	}
+	got := strings.Split(err.Error(), "\n")
+	for i := range got {
+		got[i] = strings.TrimSpace(got[i])
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected problems:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestParseConfig_Valid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{name: "empty", config: ""},
+		{name: "comments only", config: "# nothing here\n"},
+		{name: "integer overhead", config: "overhead: 0\n"},
+		{name: "compact headers", config: "headers: [title, \"owner!:Owner\"]\n"},
+		{name: "null value", config: "budget:\n"},
+		{name: "alias", config: "budget: &b 500\nminChunk: *b\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseConfig([]byte(tt.config)); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSuggestKey(t *testing.T) {
+	fields := yamlFields(reflect.TypeFor[ChunkyOptions]())
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "budgt", want: "budget"},
+		{key: "Budget", want: "budget"},
+		{key: "tokeniser", want: "tokenizer"},
+		{key: "outdir", want: "outDir"},
+		{key: "minchunks", want: "minChunk"},
+		{key: "unrelated", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := suggestKey(tt.key, fields); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"budget", "budget", 0},
+		{"budget", "budgte", 2},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"日本", "日本語", 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q): expected %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
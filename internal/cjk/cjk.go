@@ -0,0 +1,33 @@
+// Package cjk classifies runes of Chinese, Japanese and Korean text and counts
+// words in mixed-script text.
+//
+// Han ideographs and kana are written without spaces between words, so text
+// processing that splits on whitespace treats each of them as a word of its
+// own. Hangul is excluded: Korean separates words with spaces.
+package cjk
+
+import "unicode"
+
+// IsIdeograph reports whether r is a Han ideograph or a Japanese kana
+// character. The prolonged sound mark "ー" counts as Katakana.
+func IsIdeograph(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+// IsPunct reports whether r is CJK punctuation: a punctuation rune in the CJK
+// Symbols and Punctuation block or among the halfwidth and fullwidth forms.
+func IsPunct(r rune) bool {
+	return inWideBlock(r) && unicode.IsPunct(r)
+}
+
+// IsWide reports whether r belongs to text that is written without spaces:
+// ideographs, kana, CJK symbols and punctuation, and fullwidth forms.
+func IsWide(r rune) bool {
+	return IsIdeograph(r) || inWideBlock(r)
+}
+
+// inWideBlock reports whether r is in the CJK Symbols and Punctuation block or
+// the Halfwidth and Fullwidth Forms block.
+func inWideBlock(r rune) bool {
+	return (r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
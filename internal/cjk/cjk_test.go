@@ -0,0 +1,59 @@
+package cjk
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestClassification(t *testing.T) {
+	tests := []struct {
+		r                      rune
+		ideograph, punct, wide bool
+	}{
+		{'漢', true, false, true},
+		{'か', true, false, true},
+		{'カ', true, false, true},
+		{'ー', true, false, true},
+		{'한', false, false, false},
+		{'。', false, true, true},
+		{'，', false, true, true},
+		{'Ａ', false, false, true},
+		{'a', false, false, false},
+		{'.', false, false, false},
+	}
+	for _, tt := range tests {
+		if got := IsIdeograph(tt.r); got != tt.ideograph {
+			t.Errorf("IsIdeograph(%q) = %v, want %v", tt.r, got, tt.ideograph)
+		}
+		if got := IsPunct(tt.r); got != tt.punct {
+			t.Errorf("IsPunct(%q) = %v, want %v", tt.r, got, tt.punct)
+		}
+		if got := IsWide(tt.r); got != tt.wide {
+			t.Errorf("IsWide(%q) = %v, want %v", tt.r, got, tt.wide)
+		}
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	isLetter := func(r rune) bool { return unicode.IsLetter(r) }
+	tests := []struct {
+		text string
+		word func(rune) bool
+		want int
+	}{
+		{"", nil, 0},
+		{"  hello,\tworld!\n", nil, 2},
+		{"你好，世界。", nil, 4},
+		{"go言語", nil, 3},
+		{"はい！いいえ？", nil, 5},
+		{"Hello 🌍 world", nil, 3},
+		{"Hello 🌍 world", isLetter, 2},
+		{"well-known -- e.g.", isLetter, 2},
+		{"日本語 -- です", isLetter, 5},
+	}
+	for _, tt := range tests {
+		if got := CountWords(tt.text, tt.word); got != tt.want {
+			t.Errorf("CountWords(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
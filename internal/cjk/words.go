@@ -0,0 +1,36 @@
+package cjk
+
+import "unicode"
+
+// CountWords counts the words in s. Words are separated by whitespace and CJK
+// punctuation, and every Han ideograph or kana is a word of its own.
+//
+// If word is non-nil, a run of characters only counts if word reports true
+// for at least one of its runes; this lets callers skip runs of bare
+// punctuation or symbols. Ideographs always count.
+func CountWords(s string, word func(rune) bool) int {
+	words := 0
+	inRun, counts := false, false
+	end := func() {
+		if inRun && counts {
+			words++
+		}
+		inRun, counts = false, false
+	}
+
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r) || IsPunct(r):
+			end()
+		case IsIdeograph(r):
+			// An ideograph terminates any preceding run of non-CJK characters
+			end()
+			words++
+		default:
+			inRun = true
+			counts = counts || word == nil || word(r)
+		}
+	}
+	end()
+	return words
+}
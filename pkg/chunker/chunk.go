@@ -1,6 +1,7 @@
 package chunker
 
 import (
+	"slices"
 	"strings"
 )
 
@@ -22,8 +23,37 @@ type Chunk struct {
 	// Format: "---\nfrontmatter\n---\n\nbody content"
 	Text string
 
-	// Tokens is the total token count of the Text field.
+	// Tokens is the total token count of the Text field, or of the PlainText
+	// field when budgets apply to it (see PlainTextOptions.Budget).
 	Tokens int
+
+	// PlainText is the plain-text rendition of the chunk: the same header
+	// followed by the body without markdown syntax. Empty unless WithPlainText
+	// is used.
+	PlainText string
+
+	// Anchor is the anchor ID of the section the chunk begins in (see
+	// section.Section.Anchor). Empty if it begins in content without a heading.
+	Anchor string
+
+	// URL is the deep link to where the chunk begins, built from the site base
+	// URL, the mapped file path and Anchor. Empty unless WithDeepLinks is used.
+	URL string
+
+	// Metadata is the section metadata of the sections whose content the chunk
+	// holds, each including what it inherits from its ancestors. When sections
+	// disagree on a key, their distinct values are joined with ", " in document
+	// order. Nil if there is no metadata.
+	Metadata map[string]string
+
+	// CodeLanguages are the distinct languages of the fenced code blocks in
+	// the chunk, e.g. ["go", "yaml"], in order of appearance. Nil if there
+	// are none.
+	CodeLanguages []string
+
+	// Links are the distinct links in the chunk to other documents, in order
+	// of appearance. Nil unless WithLinkResolution is used.
+	Links []Link
 }
 
 // chunkBuilder accumulates markdown content into chunks based on token budgets.
@@ -35,9 +65,23 @@ type chunkBuilder struct {
 	frontTokens int    // Token count of frontBlock
 	bodyBudget  int    // Max tokens for body content
 
-	parts  []string // Accumulated body parts for current chunk
-	tokens int      // Current token count (body only)
-	index  int      // Next chunk index (1-indexed)
+	parts  []unit // Accumulated body parts for current chunk
+	tokens int    // Current token count (body only)
+	index  int    // Next chunk index (1-indexed)
+
+	links     []Link // Distinct links of the current chunk
+	linkCost  int    // Tokens the links add to the header of the current chunk
+	linkCount func(links []Link) (int, error)
+
+	last       *emitted // Most recently emitted chunk, kept for tail balancing
+	afterBreak bool     // Current chunk was started by a break directive
+	plain      bool     // Build the plain-text rendition of chunks
+}
+
+// emitted records the body parts of an emitted chunk so that it can be rebuilt.
+type emitted struct {
+	parts []unit
+	jumbo bool
 }
 
 // newChunkBuilder creates a new builder for chunking a document.
@@ -48,62 +92,90 @@ func newChunkBuilder(filePath, fileTitle, frontBlock string, frontTokens, bodyBu
 		frontBlock:  frontBlock,
 		frontTokens: frontTokens,
 		bodyBudget:  bodyBudget,
-		parts:       make([]string, 0),
+		parts:       make([]unit, 0),
 		tokens:      0,
 		index:       1,
 	}
 }
 
+// headerCost returns the tokens that links add to the header of a chunk,
+// counted with linkCount. Zero if there are no links or linkCount is nil.
+func (b *chunkBuilder) headerCost(links []Link) (int, error) {
+	if b.linkCount == nil || len(links) == 0 {
+		return 0, nil
+	}
+	return b.linkCount(links)
+}
+
 // appendUnit adds a content unit to the builder and returns any chunks produced.
 // Units are added greedily until they don't fit, at which point a chunk is emitted.
+// The header lines listing a chunk's links count against its body budget.
 //
 // Special case: "jumbo" units that exceed bodyBudget get their own dedicated chunk.
-func (b *chunkBuilder) appendUnit(unitText string, unitTokens int) []Chunk {
-	if unitTokens <= 0 {
-		return nil
+// Units without tokens, which only occur when budgets apply to the plain-text
+// rendition, are added to the current chunk without affecting its budget.
+// Returns an error if counting the tokens of links fails.
+func (b *chunkBuilder) appendUnit(u unit) ([]Chunk, error) {
+	if u.text == "" {
+		return nil, nil
 	}
 
 	var chunks []Chunk
+	own, err := b.headerCost(u.links)
+	if err != nil {
+		return nil, err
+	}
 
 	// Case 1: JUMBO unit (exceeds body budget entirely)
-	if unitTokens > b.bodyBudget {
+	if u.tokens+own > b.bodyBudget {
 		// Flush any accumulated content first
 		if flushed := b.flush(); flushed != nil {
 			chunks = append(chunks, *flushed)
 		}
 
 		// Emit jumbo unit as its own chunk
-		text := b.frontBlock + unitText
-		tokens := b.frontTokens + unitTokens
-		chunkIndex := b.index
+		parts := []unit{u}
+		jumbo := b.build(b.index, parts)
 		b.index++
+		b.last = &emitted{parts: parts, jumbo: true}
 
-		jumbo := Chunk{
-			FilePath:   b.filePath,
-			FileTitle:  b.fileTitle,
-			ChunkIndex: chunkIndex,
-			Text:       text,
-			Tokens:     tokens,
-		}
 		chunks = append(chunks, jumbo)
-		return chunks
+		return chunks, nil
 	}
 
 	// Case 2: Normal unit
-	needTokens := b.tokens + unitTokens
+	links, cost := mergeLinks(b.links, u.links), b.linkCost
+	if len(links) > len(b.links) {
+		if cost, err = b.headerCost(links); err != nil {
+			return nil, err
+		}
+	}
 
-	if needTokens > b.bodyBudget {
+	if b.tokens+u.tokens+cost > b.bodyBudget {
 		// Won't fit: flush current chunk first
 		if flushed := b.flush(); flushed != nil {
 			chunks = append(chunks, *flushed)
 		}
+		links, cost = mergeLinks(nil, u.links), own
 	}
 
 	// Add unit to current chunk
-	b.parts = append(b.parts, unitText)
-	b.tokens += unitTokens
+	b.parts = append(b.parts, u)
+	b.tokens += u.tokens
+	b.links, b.linkCost = links, cost
 
-	return chunks
+	return chunks, nil
+}
+
+// mergeLinks returns links followed by those of more it does not hold yet.
+func mergeLinks(links, more []Link) []Link {
+	out := slices.Clone(links)
+	for _, l := range more {
+		if !slices.Contains(out, l) {
+			out = append(out, l)
+		}
+	}
+	return out
 }
 
 // flush creates a chunk from accumulated content and resets the builder.
@@ -113,24 +185,178 @@ func (b *chunkBuilder) flush() *Chunk {
 		return nil
 	}
 
-	// Build chunk text: frontmatter + accumulated body parts
-	body := strings.Join(b.parts, "")
-	text := b.frontBlock + body
-	tokens := b.frontTokens + b.tokens
-	chunkIndex := b.index
+	chunk := b.build(b.index, b.parts)
 	b.index++
+	b.last = &emitted{parts: b.parts}
+	b.afterBreak = false
 
 	// Reset builder for next chunk
-	b.parts = make([]string, 0)
+	b.parts = make([]unit, 0)
 	b.tokens = 0
+	b.links, b.linkCost = nil, 0
+
+	return &chunk
+}
+
+// breakChunk ends the current chunk at a break directive and returns it, if any.
+// The next chunk is not rebalanced with the one before it.
+func (b *chunkBuilder) breakChunk() []Chunk {
+	var chunks []Chunk
+	if flushed := b.flush(); flushed != nil {
+		chunks = append(chunks, *flushed)
+	}
+	b.afterBreak = true
+	return chunks
+}
+
+// build creates a chunk from body parts: frontmatter + accumulated body parts.
+func (b *chunkBuilder) build(index int, parts []unit) Chunk {
+	var text, plain strings.Builder
+	text.WriteString(b.frontBlock)
+	tokens := b.frontTokens
+	metas := make([]map[string]string, 0, len(parts))
+	var links []Link
+	var langs []string
+	for _, p := range parts {
+		links = mergeLinks(links, p.links)
+		for _, lang := range p.langs {
+			if !slices.Contains(langs, lang) {
+				langs = append(langs, lang)
+			}
+		}
+		text.WriteString(p.text)
+		plain.WriteString(p.plain)
+		tokens += p.tokens
+		metas = append(metas, p.meta)
+	}
 
 	chunk := Chunk{
-		FilePath:   b.filePath,
-		FileTitle:  b.fileTitle,
-		ChunkIndex: chunkIndex,
-		Text:       text,
-		Tokens:     tokens,
+		FilePath:      b.filePath,
+		FileTitle:     b.fileTitle,
+		ChunkIndex:    index,
+		Text:          text.String(),
+		Tokens:        tokens,
+		Metadata:      mergeMetadata(metas...),
+		CodeLanguages: langs,
+		Links:         links,
 	}
+	if b.plain {
+		chunk.PlainText = b.frontBlock
+		if body := strings.TrimRight(plain.String(), "\n"); body != "" {
+			chunk.PlainText += body + "\n"
+		}
+	}
+	if len(parts) > 0 {
+		chunk.Anchor = parts[0].anchor
+	}
+	return chunk
+}
 
-	return &chunk
+// mergeMetadata merges section metadata maps. Keys with different values in
+// different maps get the distinct values joined with ", " in order.
+// Returns nil if there is no metadata.
+func mergeMetadata(metas ...map[string]string) map[string]string {
+	var keys []string
+	values := make(map[string][]string)
+	for _, meta := range metas {
+		for k, v := range meta {
+			if _, ok := values[k]; !ok {
+				keys = append(keys, k)
+			}
+			if !slices.Contains(values[k], v) {
+				values[k] = append(values[k], v)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(keys))
+	for _, k := range keys {
+		merged[k] = strings.Join(values[k], ", ")
+	}
+	return merged
+}
+
+// balanceTail handles a pending final chunk whose body is smaller than
+// minTokens. It must be called before the final flush.
+//
+// The parts of the tail and the previous chunk are split again at the point
+// that leaves the two chunks closest in size, provided that both fit and the
+// tail grows. Greedy packing only closes a chunk when the next unit does not
+// fit, so the tail never fits into the previous chunk as a whole.
+// Jumbo chunks and chunks started by a break directive are never rebalanced.
+//
+// Returns what happened and, if the previous chunk changed, its replacement.
+// Returns an error if counting the tokens of links fails.
+func (b *chunkBuilder) balanceTail(minTokens int) (tailOutcome, *Chunk, error) {
+	if minTokens <= 0 || len(b.parts) == 0 || b.tokens >= minTokens {
+		return tailNone, nil, nil
+	}
+	if b.last == nil || b.last.jumbo || b.afterBreak {
+		return tailSmall, nil, nil
+	}
+
+	parts := append(append([]unit(nil), b.last.parts...), b.parts...)
+	total := 0
+	for _, p := range parts {
+		total += p.tokens
+	}
+
+	// Rebalance: pick the split where both halves fit and are closest in size
+	best, bestDiff := -1, 0
+	left := 0
+	for k := 1; k < len(parts); k++ {
+		left += parts[k-1].tokens
+		right := total - left
+		if left > b.bodyBudget || right > b.bodyBudget || right <= b.tokens {
+			continue
+		}
+		fits, err := b.fitsWithLinks(parts[:k], left)
+		if err == nil && fits {
+			fits, err = b.fitsWithLinks(parts[k:], right)
+		}
+		if err != nil {
+			return tailNone, nil, err
+		}
+		if !fits {
+			continue
+		}
+		diff := left - right
+		if diff < 0 {
+			diff = -diff
+		}
+		if best < 0 || diff < bestDiff {
+			best, bestDiff = k, diff
+		}
+	}
+	if best < 0 {
+		return tailSmall, nil, nil
+	}
+
+	prev := b.build(b.index-1, parts[:best])
+	b.last = &emitted{parts: parts[:best]}
+	b.parts = append(make([]unit, 0), parts[best:]...)
+	b.tokens = 0
+	b.links = nil
+	for _, p := range b.parts {
+		b.tokens += p.tokens
+		b.links = mergeLinks(b.links, p.links)
+	}
+	var err error
+	if b.linkCost, err = b.headerCost(b.links); err != nil {
+		return tailNone, nil, err
+	}
+	return tailRebalanced, &prev, nil
+}
+
+// fitsWithLinks reports whether parts holding tokens fit the body budget
+// together with the header lines listing their links.
+func (b *chunkBuilder) fitsWithLinks(parts []unit, tokens int) (bool, error) {
+	var links []Link
+	for _, p := range parts {
+		links = mergeLinks(links, p.links)
+	}
+	cost, err := b.headerCost(links)
+	return tokens+cost <= b.bodyBudget, err
 }
@@ -0,0 +1,128 @@
+package chunker
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+)
+
+// packUnits packs units of the given sizes, each rendered as "u<i>;", and
+// returns the chunk bodies and tail outcome.
+func packUnits(bodyBudget, minTokens int, sizes ...int) ([]string, tailOutcome) {
+	b := newChunkBuilder("doc.md", "Doc", "", 0, bodyBudget)
+	var chunks []Chunk
+	for i, n := range sizes {
+		produced, _ := b.appendUnit(unit{text: fmt.Sprintf("u%d;", i), tokens: n})
+		chunks = append(chunks, produced...)
+	}
+	outcome, prev, _ := b.balanceTail(minTokens)
+	if prev != nil {
+		chunks[len(chunks)-1] = *prev
+	}
+	if final := b.flush(); final != nil {
+		chunks = append(chunks, *final)
+	}
+
+	bodies := make([]string, len(chunks))
+	for i, c := range chunks {
+		bodies[i] = fmt.Sprintf("%d:%s", c.ChunkIndex, c.Text)
+	}
+	return bodies, outcome
+}
+
+func TestBalanceTail(t *testing.T) {
+	tests := []struct {
+		name     string
+		budget   int
+		min      int
+		sizes    []int
+		expected string
+		outcome  tailOutcome
+	}{
+		{
+			name:     "disabled",
+			budget:   10,
+			min:      0,
+			sizes:    []int{6, 3, 2},
+			expected: "1:u0;u1; 2:u2;",
+			outcome:  tailNone,
+		},
+		{
+			name:     "tail large enough",
+			budget:   10,
+			min:      2,
+			sizes:    []int{6, 3, 2},
+			expected: "1:u0;u1; 2:u2;",
+			outcome:  tailNone,
+		},
+		{
+			name:     "rebalanced",
+			budget:   10,
+			min:      3,
+			sizes:    []int{6, 3, 2},
+			expected: "1:u0; 2:u1;u2;",
+			outcome:  tailRebalanced,
+		},
+		{
+			name:     "rebalanced to closest sizes",
+			budget:   10,
+			min:      4,
+			sizes:    []int{2, 2, 2, 2, 2, 1},
+			expected: "1:u0;u1;u2; 2:u3;u4;u5;",
+			outcome:  tailRebalanced,
+		},
+		{
+			name:     "cannot grow tail",
+			budget:   10,
+			min:      5,
+			sizes:    []int{8, 3, 1},
+			expected: "1:u0; 2:u1;u2;",
+			outcome:  tailSmall,
+		},
+		{
+			name:     "after jumbo",
+			budget:   10,
+			min:      5,
+			sizes:    []int{20, 1},
+			expected: "1:u0; 2:u1;",
+			outcome:  tailSmall,
+		},
+		{
+			name:     "single chunk",
+			budget:   10,
+			min:      5,
+			sizes:    []int{1, 1},
+			expected: "1:u0;u1;",
+			outcome:  tailSmall,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodies, outcome := packUnits(tt.budget, tt.min, tt.sizes...)
+			if got := strings.Join(bodies, " "); got != tt.expected {
+				t.Errorf("expected chunks %q, got %q", tt.expected, got)
+			}
+			if outcome != tt.outcome {
+				t.Errorf("expected outcome %v, got %v", tt.outcome, outcome)
+			}
+		})
+	}
+}
+
+func TestMergeMetadata(t *testing.T) {
+	got := mergeMetadata(
+		map[string]string{"audience": "admins", "product": "pro"},
+		nil,
+		map[string]string{"audience": "users"},
+		map[string]string{"audience": "admins"},
+	)
+	want := map[string]string{"audience": "admins, users", "product": "pro"}
+	if !maps.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := mergeMetadata(nil, map[string]string{}); got != nil {
+		t.Errorf("expected nil without metadata, got %v", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	cctx "github.com/wyvernzora/chunky/pkg/context"
 	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
@@ -26,7 +27,7 @@ type Chunker interface {
 	// The returned slice should not be modified by the caller.
 	Chunks() []Chunk
 
-	// Reset clears all accumulated chunks, preparing for a new batch.
+	// Reset clears all accumulated chunks and stats, preparing for a new batch.
 	Reset()
 
 	// EffectiveBudget returns the actual token budget available for body content
@@ -40,6 +41,7 @@ type Input struct {
 	Path string
 
 	// Title is the human-readable title for the document.
+	// Derive transforms may replace it with a title found in the document.
 	Title string
 
 	// Markdown is the raw markdown content including optional frontmatter.
@@ -53,10 +55,16 @@ type Input struct {
 //
 // Optional configuration:
 //   - WithReservedOverheadRatio: Fraction reserved for overhead (default: 0.1)
+//   - WithMinChunkTokens: Minimum body size of a document's last chunk (default: 0)
+//   - WithTableSplitting: Split oversized tables, repeating header rows (default: off)
+//   - WithCodeSplitting: Split oversized fenced code, repeating fences (default: off)
 //   - WithTokenizer: Custom tokenizer (default: TiktokenTokenizer with o200k_base)
 //   - WithParser: Custom parser (default: DefaultParser from parser/builtin)
+//   - WithMarkdown: Goldmark configuration for parsing (default: markdown.Default)
 //   - WithChunkHeaderGenerator: Custom header generator (default: YAML frontmatter)
+//   - WithDeriveTransform: Add derive transforms (run after parsing)
 //   - WithFrontMatterTransform: Add frontmatter transforms (appends to defaults)
+//   - WithTreeTransform: Add tree transforms (restructure the section tree)
 //   - WithSectionTransform: Add section transforms (appends to defaults)
 //
 // Default frontmatter transforms (applied in order):
@@ -98,8 +106,19 @@ type Input struct {
 //	    WithTokenizer(tok),
 //	)
 func New(opts ...Option) (Chunker, error) {
-	// Initialize config with defaults
-	cfg := &options{
+	cfg := defaultOptions()
+
+	// Apply options (these append to or override defaults)
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return newDefaultChunker(cfg)
+}
+
+// defaultOptions returns the chunker configuration before any options are applied.
+func defaultOptions() *options {
+	return &options{
 		chunkTokenBudget:      0,
 		reservedOverheadRatio: 0.1,
 		tokenizer:             nil,
@@ -120,12 +139,11 @@ func New(opts ...Option) (Chunker, error) {
 			sbuiltin.HeadingPathCommentTransform(),
 		},
 	}
+}
 
-	// Apply options (these append to or override defaults)
-	for _, opt := range opts {
-		opt(cfg)
-	}
-
+// newDefaultChunker validates a fully applied configuration, fills in default
+// components, and constructs the chunker.
+func newDefaultChunker(cfg *options) (*defaultChunker, error) {
 	// Validate configuration
 	if cfg.chunkTokenBudget <= 0 {
 		return nil, fmt.Errorf("WithChunkTokenBudget is required and must be > 0, got %d", cfg.chunkTokenBudget)
@@ -135,6 +153,18 @@ func New(opts ...Option) (Chunker, error) {
 		return nil, fmt.Errorf("WithReservedOverheadRatio must be >= 0 and < 1, got %f", cfg.reservedOverheadRatio)
 	}
 
+	if cfg.minChunkTokens < 0 {
+		return nil, fmt.Errorf("WithMinChunkTokens must be >= 0, got %d", cfg.minChunkTokens)
+	}
+
+	var links *deepLinks
+	if cfg.deepLinks != nil {
+		var err error
+		if links, err = newDeepLinks(cfg.deepLinks.baseURL, cfg.deepLinks.mapPath); err != nil {
+			return nil, fmt.Errorf("WithDeepLinks: %w", err)
+		}
+	}
+
 	// Set defaults
 	if cfg.tokenizer == nil {
 		tok, err := tbuiltin.NewTiktokenTokenizer()
@@ -157,6 +187,7 @@ func New(opts ...Option) (Chunker, error) {
 	return &defaultChunker{
 		config:          cfg,
 		effectiveBudget: effectiveBudget,
+		links:           links,
 		chunks:          nil,
 	}, nil
 }
@@ -165,28 +196,71 @@ func New(opts ...Option) (Chunker, error) {
 type defaultChunker struct {
 	config          *options
 	effectiveBudget int
+	links           *deepLinks
 	chunks          []Chunk
+	stats           Stats
 }
 
 // Push implements Chunker.Push.
 func (c *defaultChunker) Push(ctx context.Context, input Input) error {
+	ctx, err := documentContext(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	doc, err := prepareDocument(ctx, c.config, input)
+	if err != nil || doc == nil {
+		return err
+	}
+
+	chunks, stats, err := c.pack(ctx, doc)
+	if err != nil {
+		return err
+	}
+
+	// Accumulate chunks
+	c.chunks = append(c.chunks, chunks...)
+	c.stats = c.stats.Add(stats)
+
+	return nil
+}
+
+// document is a parsed and transformed document, ready to be packed into chunks.
+// The section tree is shared between packing passes and must not be mutated
+// after preparation.
+type document struct {
+	input       Input
+	title       string
+	frontmatter fm.FrontMatter
+	root        *section.Section
+	links       resolvedLinks
+}
+
+// documentContext validates the input and returns a context carrying its file info.
+func documentContext(ctx context.Context, input Input) (context.Context, error) {
 	// Validate input
 	if input.Path == "" {
-		return fmt.Errorf("Input.Path cannot be empty")
+		return nil, fmt.Errorf("Input.Path cannot be empty")
 	}
 	if input.Title == "" {
-		return fmt.Errorf("Input.Title cannot be empty")
+		return nil, fmt.Errorf("Input.Title cannot be empty")
 	}
 	if input.Markdown == "" {
-		return fmt.Errorf("Input.Markdown cannot be empty")
+		return nil, fmt.Errorf("Input.Markdown cannot be empty")
 	}
 
 	// Add file info to context
-	ctx = cctx.WithFileInfo(ctx, cctx.FileInfo{
+	return cctx.WithFileInfo(ctx, cctx.FileInfo{
 		Path:  input.Path,
 		Title: input.Title,
-	})
+	}), nil
+}
+
+// prepareDocument runs the parse and transform stages of the pipeline.
+// Returns a nil document if the document opted out of embedding.
+func prepareDocument(ctx context.Context, cfg *options, input Input) (*document, error) {
 	logger := cctx.Logger(ctx)
+	ctx = cctx.WithMarkdown(ctx, cfg.markdown)
 
 	logger.Debug("chunker: parsing document",
 		slog.String("path", input.Path),
@@ -194,28 +268,54 @@ func (c *defaultChunker) Push(ctx context.Context, input Input) error {
 
 	// Check for cancellation
 	if err := ctx.Err(); err != nil {
-		return fmt.Errorf("context cancelled before parsing %s: %w", input.Path, err)
+		return nil, fmt.Errorf("context cancelled before parsing %s: %w", input.Path, err)
 	}
 
 	// Parse markdown
-	root, frontmatter, err := c.config.parser(ctx, []byte(input.Markdown))
+	root, frontmatter, err := cfg.parser(ctx, []byte(input.Markdown))
 	if err != nil {
 		logger.Error("chunker: parse failed", slog.Any("error", err))
-		return fmt.Errorf("parse failed for %s: %w", input.Path, err)
+		return nil, fmt.Errorf("parse failed for %s: %w", input.Path, err)
+	}
+
+	// Apply derive transforms
+	for i, transform := range cfg.deriveTransforms {
+		// Check for cancellation
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("context cancelled during derive transform for %s: %w", input.Path, err)
+		}
+
+		if err := transform(ctx, frontmatter, root); err != nil {
+			logger.Error("chunker: derive transform failed",
+				slog.Int("transform_index", i),
+				slog.Any("error", err))
+			return nil, fmt.Errorf("derive transform %d failed for %s: %w", i, input.Path, err)
+		}
+	}
+
+	// Derive transforms may have retitled the document
+	title := root.Title()
+	if title != input.Title {
+		logger.Debug("chunker: document retitled",
+			slog.String("from", input.Title),
+			slog.String("to", title))
+		fi := cctx.MustFileInfo(ctx)
+		fi.Title = title
+		ctx = cctx.WithFileInfo(ctx, fi)
 	}
 
 	// Apply frontmatter transforms
-	for i, transform := range c.config.fmTransforms {
+	for i, transform := range cfg.fmTransforms {
 		// Check for cancellation
 		if err := ctx.Err(); err != nil {
-			return fmt.Errorf("context cancelled during frontmatter transform for %s: %w", input.Path, err)
+			return nil, fmt.Errorf("context cancelled during frontmatter transform for %s: %w", input.Path, err)
 		}
 
 		if err := transform(ctx, frontmatter); err != nil {
 			logger.Error("chunker: frontmatter transform failed",
 				slog.Int("transform_index", i),
 				slog.Any("error", err))
-			return fmt.Errorf("frontmatter transform %d failed for %s: %w", i, input.Path, err)
+			return nil, fmt.Errorf("frontmatter transform %d failed for %s: %w", i, input.Path, err)
 		}
 	}
 
@@ -223,36 +323,88 @@ func (c *defaultChunker) Push(ctx context.Context, input Input) error {
 	if doNotEmbed, ok := frontmatter["do_not_embed"].(bool); ok && doNotEmbed {
 		logger.Debug("chunker: skipping document with do_not_embed=true",
 			slog.String("path", input.Path))
-		return nil
+		return nil, nil
+	}
+
+	// Apply tree transforms
+	for i, transform := range cfg.treeTransforms {
+		// Check for cancellation
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("context cancelled during tree transform for %s: %w", input.Path, err)
+		}
+
+		if err := section.ApplyTreeTransform(ctx, frontmatter, root, transform); err != nil {
+			logger.Error("chunker: tree transform failed",
+				slog.Int("transform_index", i),
+				slog.Any("error", err))
+			return nil, fmt.Errorf("tree transform %d failed for %s: %w", i, input.Path, err)
+		}
 	}
 
 	// Apply section transforms
-	for i, transform := range c.config.sectionTransforms {
+	for i, transform := range cfg.sectionTransforms {
 		// Check for cancellation
 		if err := ctx.Err(); err != nil {
-			return fmt.Errorf("context cancelled during section transform for %s: %w", input.Path, err)
+			return nil, fmt.Errorf("context cancelled during section transform for %s: %w", input.Path, err)
 		}
 
 		if err := section.ApplyTransform(ctx, frontmatter, root, transform); err != nil {
 			logger.Error("chunker: section transform failed",
 				slog.Int("transform_index", i),
 				slog.Any("error", err))
-			return fmt.Errorf("section transform %d failed for %s: %w", i, input.Path, err)
+			return nil, fmt.Errorf("section transform %d failed for %s: %w", i, input.Path, err)
 		}
 	}
 
-	// Generate chunk header
-	frontBlock, err := c.config.headerGenerator(ctx, frontmatter.View())
+	// Resolve relative links once the content is final
+	var links resolvedLinks
+	if cfg.linkResolution != nil {
+		resolver, err := newLinkResolver(cfg)
+		if err != nil {
+			return nil, err
+		}
+		links = resolver.resolveLinks(ctx, cctx.Markdown(ctx), root)
+	}
+
+	return &document{
+		input:       input,
+		title:       title,
+		frontmatter: frontmatter,
+		root:        root,
+		links:       links,
+	}, nil
+}
+
+// pack runs the header, tokenize and packing stages of the pipeline against a
+// prepared document, returning the produced chunks and their stats.
+func (c *defaultChunker) pack(ctx context.Context, doc *document) ([]Chunk, Stats, error) {
+	logger := cctx.Logger(ctx)
+	input := doc.input
+
+	md := cctx.Markdown(cctx.WithMarkdown(ctx, c.config.markdown))
+
+	// Generate chunk header. When sections carry metadata, deep links are
+	// enabled or the document links to others, the budget is based on a header
+	// holding the metadata of the whole document and its longest deep link,
+	// and every chunk's header is rendered again with its own metadata and
+	// links once packed. The lines listing links are counted per chunk while
+	// packing, since a document may link to far more targets than one chunk.
+	docMeta, docAnchor := treeInfo(doc.root)
+	headerCtx := cctx.WithChunkMetadata(ctx, docMeta)
+	if c.links != nil {
+		headerCtx = cctx.WithChunkURL(headerCtx, c.links.url(input.Path, docAnchor))
+	}
+	frontBlock, err := c.config.headerGenerator(headerCtx, doc.frontmatter.View())
 	if err != nil {
 		logger.Error("chunker: header generation failed", slog.Any("error", err))
-		return fmt.Errorf("header generation failed for %s: %w", input.Path, err)
+		return nil, Stats{}, fmt.Errorf("header generation failed for %s: %w", input.Path, err)
 	}
 
 	// Count header tokens
 	frontTokens, err := c.config.tokenizer.Count(frontBlock)
 	if err != nil {
 		logger.Error("chunker: frontmatter token counting failed", slog.Any("error", err))
-		return fmt.Errorf("frontmatter token counting failed for %s: %w", input.Path, err)
+		return nil, Stats{}, fmt.Errorf("frontmatter token counting failed for %s: %w", input.Path, err)
 	}
 
 	logger.Debug("chunker: frontmatter counted",
@@ -265,7 +417,7 @@ func (c *defaultChunker) Push(ctx context.Context, input Input) error {
 		logger.Warn("chunker: no budget remaining for body content",
 			slog.Int("frontmatter_tokens", frontTokens),
 			slog.Int("effective_budget", c.effectiveBudget))
-		return fmt.Errorf("frontmatter (%d tokens) exceeds effective budget (%d tokens) for %s",
+		return nil, Stats{}, fmt.Errorf("frontmatter (%d tokens) exceeds effective budget (%d tokens) for %s",
 			frontTokens, c.effectiveBudget, input.Path)
 	}
 
@@ -273,35 +425,139 @@ func (c *defaultChunker) Push(ctx context.Context, input Input) error {
 		slog.Int("body_budget", bodyBudget))
 
 	// Tokenize section tree
-	tokenizedRoot, err := c.config.tokenizer.Tokenize(ctx, root)
+	tokenizedRoot, err := c.config.tokenizer.Tokenize(ctx, doc.root)
 	if err != nil {
 		logger.Error("chunker: tokenization failed", slog.Any("error", err))
-		return fmt.Errorf("tokenization failed for %s: %w", input.Path, err)
+		return nil, Stats{}, fmt.Errorf("tokenization failed for %s: %w", input.Path, err)
 	}
 
 	logger.Debug("chunker: section tree tokenized",
 		slog.Int("subtree_tokens", tokenizedRoot.GetSubtreeTokens()))
 
 	// Generate chunks
-	chunks := chunkDocument(chunkDocumentParams{
+	var split *splitter
+	if c.config.splitTables || c.config.splitCode {
+		split = &splitter{
+			count:  c.config.tokenizer.Count,
+			tables: c.config.splitTables,
+			code:   c.config.splitCode,
+		}
+	}
+	chunks, tail, err := chunkDocument(chunkDocumentParams{
 		filePath:    input.Path,
-		fileTitle:   input.Title,
+		fileTitle:   doc.title,
 		frontBlock:  frontBlock,
 		frontTokens: frontTokens,
 		bodyBudget:  bodyBudget,
+		minTokens:   c.config.minChunkTokens,
 		root:        tokenizedRoot,
+		count:       c.config.tokenizer.Count,
+		split:       split,
+		md:          md,
+		links:       doc.links,
+		plain:       newPlainText(c.config.tokenizer.Count, c.config.plainText),
+		linkCount:   c.linkCounter(headerCtx, doc, frontTokens),
 	})
+	if err != nil {
+		logger.Error("chunker: chunking failed", slog.Any("error", err))
+		return nil, Stats{}, fmt.Errorf("chunking failed for %s: %w", input.Path, err)
+	}
+
+	if c.links != nil {
+		for i := range chunks {
+			chunks[i].URL = c.links.url(input.Path, chunks[i].Anchor)
+		}
+	}
+
+	if docMeta != nil || c.links != nil || len(doc.links.byDest) > 0 {
+		if err := c.renderHeaders(ctx, doc, chunks, frontBlock, frontTokens); err != nil {
+			logger.Error("chunker: header generation failed", slog.Any("error", err))
+			return nil, Stats{}, fmt.Errorf("header generation failed for %s: %w", input.Path, err)
+		}
+	}
 
 	logger.Debug("chunker: document chunked",
 		slog.Int("chunk_count", len(chunks)),
+		slog.String("tail", tail.String()),
 		slog.String("path", input.Path))
 
-	// Accumulate chunks
-	c.chunks = append(c.chunks, chunks...)
+	return chunks, tail.stats(), nil
+}
 
+// linkCounter returns a function counting the tokens that links add to the
+// header generated in headerCtx, whose block counts frontTokens. Results are
+// cached by link targets. Returns nil if the document has no links.
+func (c *defaultChunker) linkCounter(headerCtx context.Context, doc *document, frontTokens int) func([]Link) (int, error) {
+	if len(doc.links.byDest) == 0 {
+		return nil
+	}
+	counted := make(map[string]int)
+	return func(links []Link) (int, error) {
+		targets := linkTargets(links)
+		key := strings.Join(targets, "\n")
+		if tokens, ok := counted[key]; ok {
+			return tokens, nil
+		}
+		block, err := c.config.headerGenerator(cctx.WithChunkLinks(headerCtx, targets), doc.frontmatter.View())
+		if err != nil {
+			return 0, fmt.Errorf("header generation failed: %w", err)
+		}
+		tokens, err := c.config.tokenizer.Count(block)
+		if err != nil {
+			return 0, fmt.Errorf("header token counting failed: %w", err)
+		}
+		counted[key] = tokens - frontTokens
+		return tokens - frontTokens, nil
+	}
+}
+
+// renderHeaders replaces the document-wide header at the start of each chunk
+// with one generated for the chunk's own section metadata, URL and links.
+func (c *defaultChunker) renderHeaders(ctx context.Context, doc *document, chunks []Chunk, frontBlock string, frontTokens int) error {
+	for i := range chunks {
+		chunk := &chunks[i]
+		chunkCtx := cctx.WithChunkURL(cctx.WithChunkMetadata(ctx, chunk.Metadata), chunk.URL)
+		chunkCtx = cctx.WithChunkLinks(chunkCtx, linkTargets(chunk.Links))
+		block, err := c.config.headerGenerator(chunkCtx, doc.frontmatter.View())
+		if err != nil {
+			return fmt.Errorf("chunk %d: %w", chunk.ChunkIndex, err)
+		}
+		tokens, err := c.config.tokenizer.Count(block)
+		if err != nil {
+			return fmt.Errorf("chunk %d: %w", chunk.ChunkIndex, err)
+		}
+		chunk.Text = block + strings.TrimPrefix(chunk.Text, frontBlock)
+		if chunk.PlainText != "" {
+			chunk.PlainText = block + strings.TrimPrefix(chunk.PlainText, frontBlock)
+		}
+		chunk.Tokens += tokens - frontTokens
+	}
 	return nil
 }
 
+// treeInfo merges the inherited metadata of every section in the tree and
+// finds the longest section anchor. The metadata is nil if there is none.
+func treeInfo(root *section.Section) (map[string]string, string) {
+	var metas []map[string]string
+	var anchor string
+	stack := []*section.Section{root}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if meta := s.InheritedMetadata(); meta != nil {
+			metas = append(metas, meta)
+		}
+		if len(s.Anchor()) > len(anchor) {
+			anchor = s.Anchor()
+		}
+		children := s.Children()
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, children[i])
+		}
+	}
+	return mergeMetadata(metas...), anchor
+}
+
 // Chunks implements Chunker.Chunks.
 func (c *defaultChunker) Chunks() []Chunk {
 	return c.chunks
@@ -310,9 +566,15 @@ func (c *defaultChunker) Chunks() []Chunk {
 // Reset implements Chunker.Reset.
 func (c *defaultChunker) Reset() {
 	c.chunks = nil
+	c.stats = Stats{}
 }
 
 // EffectiveBudget implements Chunker.EffectiveBudget.
 func (c *defaultChunker) EffectiveBudget() int {
 	return c.effectiveBudget
 }
+
+// Stats implements StatsReporter.Stats.
+func (c *defaultChunker) Stats() Stats {
+	return c.stats
+}
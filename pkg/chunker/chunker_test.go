@@ -1,13 +1,18 @@
 package chunker
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
 	"testing"
 
 	cctx "github.com/wyvernzora/chunky/pkg/context"
 	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	hbuiltin "github.com/wyvernzora/chunky/pkg/header/builtin"
 	"github.com/wyvernzora/chunky/pkg/section"
 	"github.com/wyvernzora/chunky/pkg/tokenizer"
 	tbuiltin "github.com/wyvernzora/chunky/pkg/tokenizer/builtin"
@@ -586,6 +591,603 @@ func TestWithFrontMatterTransforms(t *testing.T) {
 	}
 }
 
+// TestWithDeriveTransforms tests that derive transforms see the section tree
+// and can retitle the document
+func TestWithDeriveTransforms(t *testing.T) {
+	var order []string
+	derived := func(ctx context.Context, fm fm.FrontMatter, root *section.Section) error {
+		order = append(order, "derive")
+		fm["headings"] = len(root.Children())
+		root.SetTitle("Derived Title")
+		return nil
+	}
+	var seenTitle string
+	after := func(ctx context.Context, fm fm.FrontMatter) error {
+		order = append(order, "frontmatter")
+		seenTitle = cctx.MustFileInfo(ctx).Title
+		return nil
+	}
+
+	c, err := New(
+		WithChunkTokenBudget(1000),
+		WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		WithFrontMatterTransform(after),
+		WithDeriveTransforms(derived),
+	)
+	if err != nil {
+		t.Fatalf("failed to create chunker: %v", err)
+	}
+
+	err = c.Push(context.Background(), Input{
+		Path:     "test.md",
+		Title:    "test",
+		Markdown: "# One\n\nText\n\n# Two\n\nMore text",
+	})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	if strings.Join(order, ",") != "derive,frontmatter" {
+		t.Errorf("expected derive transforms before frontmatter transforms, got %v", order)
+	}
+	if seenTitle != "Derived Title" {
+		t.Errorf("expected frontmatter transforms to see derived title, got %q", seenTitle)
+	}
+
+	chunks := c.Chunks()
+	if len(chunks) == 0 {
+		t.Fatal("expected at least one chunk")
+	}
+	if chunks[0].FileTitle != "Derived Title" {
+		t.Errorf("expected FileTitle 'Derived Title', got %q", chunks[0].FileTitle)
+	}
+	if !strings.Contains(chunks[0].Text, "headings: 2") {
+		t.Errorf("expected derived field in header, got %q", chunks[0].Text)
+	}
+	if !strings.Contains(chunks[0].Text, "<!-- path: Derived Title / One -->") {
+		t.Errorf("expected heading path to start from derived title, got %q", chunks[0].Text)
+	}
+}
+
+// TestWithTreeTransforms tests that tree transforms run once per document,
+// before section transforms, and that their changes reach the chunks
+func TestWithTreeTransforms(t *testing.T) {
+	var order []string
+	drop := func(ctx context.Context, fmView fm.FrontMatterView, root *section.Section) error {
+		order = append(order, "tree")
+		for _, c := range root.Children() {
+			if c.Title() == "Changelog" {
+				root.RemoveChild(c)
+			}
+		}
+		return nil
+	}
+	visit := func(ctx context.Context, fmView fm.FrontMatterView, s *section.Section) error {
+		order = append(order, "section")
+		return nil
+	}
+
+	c, err := New(
+		WithChunkTokenBudget(1000),
+		WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		WithSectionTransforms(visit),
+		WithTreeTransforms(drop),
+	)
+	if err != nil {
+		t.Fatalf("failed to create chunker: %v", err)
+	}
+
+	err = c.Push(context.Background(), Input{
+		Path:     "test.md",
+		Title:    "test",
+		Markdown: "# Usage\n\nRun it\n\n# Changelog\n\nFixed bugs",
+	})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	if strings.Join(order, ",") != "tree,section,section" {
+		t.Errorf("expected one tree transform call before section transforms, got %v", order)
+	}
+	for _, chunk := range c.Chunks() {
+		if strings.Contains(chunk.Text, "Fixed bugs") {
+			t.Errorf("expected dropped section to be absent, got %q", chunk.Text)
+		}
+	}
+}
+
+// TestTreeTransformError tests that tree transform errors fail the document
+func TestTreeTransformError(t *testing.T) {
+	expectedErr := errors.New("tree failed")
+	c, err := New(
+		WithChunkTokenBudget(1000),
+		WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		WithTreeTransform(func(ctx context.Context, fmView fm.FrontMatterView, root *section.Section) error {
+			return expectedErr
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create chunker: %v", err)
+	}
+
+	err = c.Push(context.Background(), Input{Path: "test.md", Title: "test", Markdown: "# A\n\nText"})
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("expected wrapped tree transform error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "tree transform 0 failed for test.md") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+// TestWithMinChunkTokens tests that a small final chunk is rebalanced and
+// that the outcome is reported in Stats
+func TestWithMinChunkTokens(t *testing.T) {
+	para := strings.Repeat("word ", 60)
+	markdown := "# A\n\n" + para + "\n\n# B\n\n" + para + "\n\n# See also\n\n- link"
+
+	// The two paragraphs fill the effective budget of 140 tokens, leaving
+	// "See also" alone in the final chunk
+	push := func(minTokens int) ([]Chunk, Stats) {
+		c, err := New(
+			WithChunkTokenBudget(156),
+			WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+			WithChunkHeader(func(ctx context.Context, fm fm.FrontMatterView) (string, error) { return "", nil }),
+			WithMinChunkTokens(minTokens),
+		)
+		if err != nil {
+			t.Fatalf("failed to create chunker: %v", err)
+		}
+		if err := c.Push(context.Background(), Input{Path: "test.md", Title: "test", Markdown: markdown}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		return c.Chunks(), c.(StatsReporter).Stats()
+	}
+
+	plain, stats := push(0)
+	if len(plain) < 2 {
+		t.Fatalf("expected at least 2 chunks, got %d", len(plain))
+	}
+	if stats != (Stats{}) {
+		t.Errorf("expected empty stats without a minimum, got %+v", stats)
+	}
+	tail := plain[len(plain)-1].Tokens
+	if tail >= 30 {
+		t.Fatalf("expected a small final chunk, got %d tokens", tail)
+	}
+
+	balanced, stats := push(30)
+	if len(balanced) != len(plain) {
+		t.Errorf("expected rebalancing to keep %d chunks, got %d", len(plain), len(balanced))
+	}
+	if stats.TailsRebalanced != 1 || stats.TailsBelowMinimum != 0 {
+		t.Errorf("expected one rebalanced tail, got %+v", stats)
+	}
+	if got := balanced[len(balanced)-1].Tokens; got <= tail {
+		t.Errorf("expected final chunk to grow from %d tokens, got %d", tail, got)
+	}
+	if !strings.Contains(balanced[len(balanced)-1].Text, "- link") {
+		t.Error("expected final chunk to keep the trailing content")
+	}
+	for _, chunk := range balanced {
+		if chunk.Tokens > 140 {
+			t.Errorf("expected chunk %d within budget, got %d tokens", chunk.ChunkIndex, chunk.Tokens)
+		}
+	}
+}
+
+// pushDirectives chunks markdown with the word tokenizer and no header
+func pushDirectives(t *testing.T, budget, minTokens int, markdown string) ([]Chunk, Stats) {
+	t.Helper()
+	c, err := New(
+		WithChunkTokenBudget(budget),
+		WithReservedOverheadRatio(0),
+		WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		WithChunkHeader(func(ctx context.Context, fm fm.FrontMatterView) (string, error) { return "", nil }),
+		WithMinChunkTokens(minTokens),
+	)
+	if err != nil {
+		t.Fatalf("failed to create chunker: %v", err)
+	}
+	if err := c.Push(context.Background(), Input{Path: "test.md", Title: "test", Markdown: markdown}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	chunks := c.Chunks()
+	for _, chunk := range chunks {
+		if strings.Contains(chunk.Text, "chunky:") {
+			t.Errorf("expected directives stripped from chunk %d, got %q", chunk.ChunkIndex, chunk.Text)
+		}
+	}
+	return chunks, c.(StatsReporter).Stats()
+}
+
+// TestDirectiveBreak tests that a break directive forces a chunk boundary
+// and is not undone by tail balancing
+func TestDirectiveBreak(t *testing.T) {
+	markdown := "# A\n\nalpha beta gamma\n\n<!-- chunky:break -->\n\ndelta epsilon\n"
+
+	chunks, stats := pushDirectives(t, 1000, 10, markdown)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if !strings.Contains(chunks[0].Text, "gamma") || strings.Contains(chunks[0].Text, "delta") {
+		t.Errorf("unexpected first chunk: %q", chunks[0].Text)
+	}
+	if !strings.HasPrefix(chunks[1].Text, "delta epsilon") {
+		t.Errorf("unexpected second chunk: %q", chunks[1].Text)
+	}
+	if stats.TailsBelowMinimum != 1 || stats.TailsRebalanced != 0 {
+		t.Errorf("expected the tail after a break to stay below minimum, got %+v", stats)
+	}
+}
+
+// TestDirectiveKeepTogether tests that a keep-together region is never split
+func TestDirectiveKeepTogether(t *testing.T) {
+	para := strings.Repeat("word ", 8)
+	markdown := "# A\n\n" + para + "\n\n<!-- chunky:keep-together -->\n\n# B\n\nfirst half of the region\n\n" +
+		"## C\n\nsecond half of the region\n\n<!-- chunky:end -->\n\n# D\n\n" + para + "\n"
+
+	plain, _ := pushDirectives(t, 20, 0, strings.ReplaceAll(strings.ReplaceAll(markdown,
+		"<!-- chunky:keep-together -->\n\n", ""), "<!-- chunky:end -->\n\n", ""))
+	split := false
+	for _, chunk := range plain {
+		if strings.Contains(chunk.Text, "first half") != strings.Contains(chunk.Text, "second half") {
+			split = true
+		}
+	}
+	if !split {
+		t.Fatal("expected the region to be split without directives")
+	}
+
+	chunks, _ := pushDirectives(t, 20, 0, markdown)
+	found := false
+	for _, chunk := range chunks {
+		if strings.Contains(chunk.Text, "first half") {
+			found = true
+			if !strings.Contains(chunk.Text, "second half") {
+				t.Errorf("expected keep-together region in one chunk, got %q", chunk.Text)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected keep-together region in output")
+	}
+}
+
+// TestDirectiveIgnore tests that ignored content is dropped from chunks
+func TestDirectiveIgnore(t *testing.T) {
+	markdown := "# A\n\nvisible\n\n<!-- chunky:ignore -->\n\nsecret notes\n\n<!-- chunky:end -->\n\nalso visible\n"
+
+	chunks, _ := pushDirectives(t, 1000, 0, markdown)
+	if len(chunks) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(chunks))
+	}
+	if strings.Contains(chunks[0].Text, "secret") {
+		t.Errorf("expected ignored content dropped, got %q", chunks[0].Text)
+	}
+	if !strings.Contains(chunks[0].Text, "also visible") {
+		t.Errorf("expected content after the region kept, got %q", chunks[0].Text)
+	}
+}
+
+// TestChunkSectionMetadata tests that chunks carry the metadata of their
+// sections and that headers are rendered per chunk
+func TestChunkSectionMetadata(t *testing.T) {
+	para := strings.Repeat("word ", 30)
+	markdown := "# Intro\n\n" + para + "\n\n# Admin {audience=admins}\n\n" + para +
+		"\n\n## Backup\n\n" + para + "\n"
+
+	c, err := New(
+		WithChunkTokenBudget(50),
+		WithReservedOverheadRatio(0),
+		WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		WithChunkHeader(hbuiltin.KeyValueHeader(hbuiltin.OptionalField("section_meta.audience", "Audience"))),
+	)
+	if err != nil {
+		t.Fatalf("failed to create chunker: %v", err)
+	}
+	if err := c.Push(context.Background(), Input{Path: "test.md", Title: "test", Markdown: markdown}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	chunks := c.Chunks()
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	if chunks[0].Metadata != nil || strings.Contains(chunks[0].Text, "Audience") {
+		t.Errorf("expected no metadata on first chunk, got %v in %q", chunks[0].Metadata, chunks[0].Text)
+	}
+	for _, chunk := range chunks[1:] {
+		if chunk.Metadata["audience"] != "admins" {
+			t.Errorf("expected inherited audience on chunk %d, got %v", chunk.ChunkIndex, chunk.Metadata)
+		}
+		if !strings.HasPrefix(chunk.Text, "Audience: admins\n") {
+			t.Errorf("expected metadata in header of chunk %d, got %q", chunk.ChunkIndex, chunk.Text)
+		}
+	}
+	for _, chunk := range chunks {
+		count, _ := tbuiltin.NewWordCountTokenizer().Count(chunk.Text)
+		if chunk.Tokens != count {
+			t.Errorf("expected %d tokens for chunk %d, got %d", count, chunk.ChunkIndex, chunk.Tokens)
+		}
+	}
+}
+
+// TestWithDeepLinks tests that chunks get the anchor and URL of the section
+// they begin in, and that headers can print the URL
+func TestWithDeepLinks(t *testing.T) {
+	para := strings.Repeat("word ", 30)
+	markdown := "Preamble.\n\n# Install\n\n" + para + "\n\n## On Linux {#linux}\n\n" + para + "\n"
+
+	c, err := New(
+		WithChunkTokenBudget(60),
+		WithReservedOverheadRatio(0),
+		WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		WithChunkHeader(hbuiltin.KeyValueHeader(hbuiltin.OptionalField("section_url", "Source"))),
+		WithDeepLinks("https://docs.example.com/v2/", func(path string) string {
+			return strings.TrimPrefix(DefaultURLPath(path), "docs/")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create chunker: %v", err)
+	}
+	if err := c.Push(context.Background(), Input{Path: "docs/guide.md", Title: "guide", Markdown: markdown}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	chunks := c.Chunks()
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	wantAnchors := []string{"", "linux"}
+	wantURLs := []string{"https://docs.example.com/v2/guide", "https://docs.example.com/v2/guide#linux"}
+	for i, chunk := range chunks {
+		if chunk.Anchor != wantAnchors[i] {
+			t.Errorf("chunk %d: expected anchor %q, got %q", chunk.ChunkIndex, wantAnchors[i], chunk.Anchor)
+		}
+		if chunk.URL != wantURLs[i] {
+			t.Errorf("chunk %d: expected URL %q, got %q", chunk.ChunkIndex, wantURLs[i], chunk.URL)
+		}
+		if !strings.HasPrefix(chunk.Text, "Source: "+wantURLs[i]+"\n") {
+			t.Errorf("chunk %d: expected URL in header, got %q", chunk.ChunkIndex, chunk.Text)
+		}
+		if chunk.Tokens > 60 {
+			t.Errorf("chunk %d: expected chunk within budget, got %d tokens", chunk.ChunkIndex, chunk.Tokens)
+		}
+	}
+}
+
+// TestWithLinkResolution tests that relative links to other documents are
+// rewritten to deep links and recorded per chunk, and that broken links are
+// reported and left alone
+func TestWithLinkResolution(t *testing.T) {
+	para := strings.Repeat("word ", 30)
+	markdown := "See [install](../setup/install.md#linux) and [FAQ](faq.md).\n\n" +
+		"# More\n\n" + para + "\n\n[Gone](missing.md), [site](https://example.com/x.md), [image](diagram.png) and `[code](faq.md)`.\n"
+
+	var logs bytes.Buffer
+	ctx := cctx.WithLogger(context.Background(), slog.New(slog.NewTextHandler(&logs, nil)))
+	c, err := New(
+		WithChunkTokenBudget(60),
+		WithReservedOverheadRatio(0),
+		WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		WithChunkHeader(hbuiltin.FrontMatterYamlHeader()),
+		WithDeepLinks("https://docs.example.com/", nil),
+		WithLinkResolution(func(path string) bool { return path != "docs/guide/missing.md" }, nil),
+	)
+	if err != nil {
+		t.Fatalf("failed to create chunker: %v", err)
+	}
+	if err := c.Push(ctx, Input{Path: "docs/guide/index.md", Title: "guide", Markdown: markdown}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	chunks := c.Chunks()
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	first := chunks[0]
+	if !strings.Contains(first.Text, "[install](https://docs.example.com/docs/setup/install#linux) and [FAQ](https://docs.example.com/docs/guide/faq)") {
+		t.Errorf("expected links rewritten to deep links, got %q", first.Text)
+	}
+	want := []Link{
+		{Path: "docs/setup/install.md", Anchor: "linux", URL: "https://docs.example.com/docs/setup/install#linux"},
+		{Path: "docs/guide/faq.md", URL: "https://docs.example.com/docs/guide/faq"},
+	}
+	if fmt.Sprint(first.Links) != fmt.Sprint(want) {
+		t.Errorf("expected links %v, got %v", want, first.Links)
+	}
+	if !strings.Contains(first.Text, "section_links:\n- https://docs.example.com/docs/guide/faq\n") {
+		t.Errorf("expected links in the header, got %q", first.Text)
+	}
+
+	second := chunks[1]
+	if second.Links != nil || strings.Contains(second.Text, "section_links") {
+		t.Errorf("expected no links in the second chunk, got %v", second.Links)
+	}
+	if !strings.Contains(second.Text, "[Gone](missing.md), [site](https://example.com/x.md), [image](diagram.png) and `[code](faq.md)`") {
+		t.Errorf("expected other links unchanged, got %q", second.Text)
+	}
+	if !strings.Contains(logs.String(), "broken internal link") || !strings.Contains(logs.String(), "missing.md") {
+		t.Errorf("expected a warning for the broken link, got %q", logs.String())
+	}
+	for _, chunk := range chunks {
+		if count, _ := tbuiltin.NewWordCountTokenizer().Count(chunk.Text); chunk.Tokens != count {
+			t.Errorf("chunk %d: expected %d tokens, got %d", chunk.ChunkIndex, count, chunk.Tokens)
+		}
+	}
+}
+
+// TestWithLinkResolution_References tests that link reference definitions are
+// resolved like inline links, that the links are recorded in the chunks that
+// use them, and that links climbing out of the project are not looked up
+func TestWithLinkResolution_References(t *testing.T) {
+	markdown := "See the [guide][g] and [setup].\n\n" +
+		"# Elsewhere\n\n[Gone][missing] and [up](../../../outside.md).\n\n" +
+		"[g]: ../guide/install.md#linux \"Install\"\n" +
+		"[setup]:\n  <setup notes.md>\n" +
+		"[missing]: missing.md\n"
+
+	var logs bytes.Buffer
+	ctx := cctx.WithLogger(context.Background(), slog.New(slog.NewTextHandler(&logs, nil)))
+	var looked []string
+	exists := func(path string) bool {
+		looked = append(looked, path)
+		return path != "docs/api/missing.md"
+	}
+	c, err := New(
+		WithChunkTokenBudget(30),
+		WithReservedOverheadRatio(0),
+		WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		WithChunkHeader(hbuiltin.FrontMatterYamlHeader()),
+		WithDeepLinks("https://docs.example.com/", nil),
+		WithLinkResolution(exists, nil),
+	)
+	if err != nil {
+		t.Fatalf("failed to create chunker: %v", err)
+	}
+	if err := c.Push(ctx, Input{Path: "docs/api/index.md", Title: "api", Markdown: markdown}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	// The definitions end up in the second chunk, yet the first chunk, which
+	// uses them, records the links
+	chunks := c.Chunks()
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	for _, want := range []string{
+		"[g]: https://docs.example.com/docs/guide/install#linux \"Install\"\n",
+		"[setup]:\n  <https://docs.example.com/docs/api/setup%20notes>\n",
+		"[missing]: missing.md\n",
+		"[up](../../../outside.md)",
+	} {
+		if !strings.Contains(chunks[1].Text, want) {
+			t.Errorf("expected second chunk to contain %q, got %q", want, chunks[1].Text)
+		}
+	}
+	want := []Link{
+		{Path: "docs/guide/install.md", Anchor: "linux", URL: "https://docs.example.com/docs/guide/install#linux"},
+		{Path: "docs/api/setup notes.md", URL: "https://docs.example.com/docs/api/setup%20notes"},
+	}
+	if fmt.Sprint(chunks[0].Links) != fmt.Sprint(want) {
+		t.Errorf("expected links %v, got %v", want, chunks[0].Links)
+	}
+	if !strings.Contains(logs.String(), "broken internal link") || !strings.Contains(logs.String(), "link=missing.md") {
+		t.Errorf("expected a warning for the broken reference link, got %q", logs.String())
+	}
+	if !strings.Contains(logs.String(), "leaves the project") {
+		t.Errorf("expected a warning for the link leaving the project, got %q", logs.String())
+	}
+	for _, path := range looked {
+		if strings.HasPrefix(path, "..") {
+			t.Errorf("expected no lookup outside the project, got %q", path)
+		}
+	}
+}
+
+// TestWithLinkResolution_ManyLinks tests that a document linking to many
+// others reserves header space for the links of each chunk only.
+func TestWithLinkResolution_ManyLinks(t *testing.T) {
+	var markdown strings.Builder
+	for i := range 200 {
+		fmt.Fprintf(&markdown, "# Part %d\n\nSee [page %d](page-%d.md) for %s\n\n", i, i, i, strings.Repeat("word ", 20))
+	}
+
+	pack := func(opts ...Option) []Chunk {
+		t.Helper()
+		opts = append([]Option{
+			WithChunkTokenBudget(300),
+			WithReservedOverheadRatio(0),
+			WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+			WithChunkHeader(hbuiltin.FrontMatterYamlHeader()),
+		}, opts...)
+		c, err := New(opts...)
+		if err != nil {
+			t.Fatalf("failed to create chunker: %v", err)
+		}
+		if err := c.Push(context.Background(), Input{Path: "docs/index.md", Title: "index", Markdown: markdown.String()}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		return c.Chunks()
+	}
+
+	plain := pack()
+	linked := pack(WithLinkResolution(nil, nil))
+	if len(linked) > len(plain)+len(plain)/5 {
+		t.Errorf("expected about %d chunks, got %d", len(plain), len(linked))
+	}
+	for _, chunk := range linked {
+		if chunk.Tokens > 300 {
+			t.Errorf("chunk %d: %d tokens exceed the budget", chunk.ChunkIndex, chunk.Tokens)
+		}
+		if count, _ := tbuiltin.NewWordCountTokenizer().Count(chunk.Text); chunk.Tokens != count {
+			t.Errorf("chunk %d: expected %d tokens, got %d", chunk.ChunkIndex, count, chunk.Tokens)
+		}
+	}
+}
+
+// TestResolveLink tests which link destinations count as links to documents
+func TestResolveLink(t *testing.T) {
+	tests := []struct {
+		dest string
+		want string
+		ok   bool
+	}{
+		{"install.md", "docs/guide/install.md", true},
+		{"../api/Auth.MD#login", "docs/api/Auth.MD#login", true},
+		{"./my%20notes.markdown", "docs/guide/my notes.markdown", true},
+		{"#local", "", false},
+		{"/docs/other.md", "", false},
+		{"https://example.com/a.md", "", false},
+		{"mailto:a@example.com", "", false},
+		{"image.png", "", false},
+		{"../../../outside.md", "../outside.md", true},
+	}
+	for _, tt := range tests {
+		link, ok := resolveLink("docs/guide/index.md", tt.dest)
+		if ok != tt.ok || (ok && link.String() != tt.want) {
+			t.Errorf("resolveLink(%q) = %v, %v; want %q, %v", tt.dest, link, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestWithDeepLinks_Invalid tests that a relative base URL is rejected
+func TestWithDeepLinks_Invalid(t *testing.T) {
+	_, err := New(WithChunkTokenBudget(1000), WithTokenizer(tbuiltin.NewWordCountTokenizer()), WithDeepLinks("/docs", nil))
+	if err == nil {
+		t.Fatal("expected error for relative base URL")
+	}
+	if !strings.Contains(err.Error(), "WithDeepLinks") {
+		t.Errorf("expected error to mention WithDeepLinks, got %v", err)
+	}
+}
+
+// TestDefaultURLPath tests the default document path mapping
+func TestDefaultURLPath(t *testing.T) {
+	tests := map[string]string{
+		"docs/guide.md":   "docs/guide",
+		"README.markdown": "README",
+		"notes":           "notes",
+		"v1.2/intro.md":   "v1.2/intro",
+	}
+	for in, want := range tests {
+		if got := DefaultURLPath(in); got != want {
+			t.Errorf("DefaultURLPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestWithMinChunkTokens_Invalid tests that a negative minimum is rejected
+func TestWithMinChunkTokens_Invalid(t *testing.T) {
+	_, err := New(WithChunkTokenBudget(1000), WithMinChunkTokens(-1))
+	if err == nil {
+		t.Fatal("expected error for negative minimum chunk size")
+	}
+	if !strings.Contains(err.Error(), "WithMinChunkTokens") {
+		t.Errorf("expected error to mention WithMinChunkTokens, got %v", err)
+	}
+}
+
 // TestWithSectionTransforms tests batch section transform addition
 func TestWithSectionTransforms(t *testing.T) {
 	callCount := 0
@@ -727,3 +1329,200 @@ func (m *mockTokenizerError) Count(text string) (int, error) {
 func (m *mockTokenizerError) Tokenize(ctx context.Context, root *section.Section) (*tokenizer.TokenizedSection, error) {
 	return nil, m.err
 }
+
+// TestTableSplitting tests that an oversized table is split between rows and
+// that every chunk repeats the header rows
+func TestTableSplitting(t *testing.T) {
+	var rows strings.Builder
+	for i := range 12 {
+		fmt.Fprintf(&rows, "| E%d | error number %d |\n", i, i)
+	}
+	markdown := "# Errors\n\n| Code | Meaning |\n| --- | --- |\n" + rows.String()
+
+	push := func(opts ...Option) []Chunk {
+		c, err := New(append([]Option{
+			WithChunkTokenBudget(40),
+			WithReservedOverheadRatio(0),
+			WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+			WithChunkHeader(func(ctx context.Context, fm fm.FrontMatterView) (string, error) { return "", nil }),
+		}, opts...)...)
+		if err != nil {
+			t.Fatalf("failed to create chunker: %v", err)
+		}
+		if err := c.Push(context.Background(), Input{Path: "test.md", Title: "test", Markdown: markdown}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		return c.Chunks()
+	}
+
+	if chunks := push(); len(chunks) != 1 {
+		t.Fatalf("expected a single jumbo chunk without splitting, got %d", len(chunks))
+	}
+
+	chunks := push(WithTableSplitting())
+	if len(chunks) < 2 {
+		t.Fatalf("expected the table to be split, got %d chunk(s)", len(chunks))
+	}
+	if !strings.Contains(chunks[0].Text, "# Errors\n\n| Code |") {
+		t.Errorf("expected the heading to stay with the table, got %q", chunks[0].Text)
+	}
+	seen := 0
+	for _, chunk := range chunks {
+		if chunk.Tokens > 40 {
+			t.Errorf("chunk %d exceeds the budget: %d tokens", chunk.ChunkIndex, chunk.Tokens)
+		}
+		if !strings.Contains(chunk.Text, "| Code | Meaning |\n| --- | --- |\n") {
+			t.Errorf("expected header rows in chunk %d, got %q", chunk.ChunkIndex, chunk.Text)
+		}
+		seen += strings.Count(chunk.Text, "| E")
+	}
+	if seen != 12 {
+		t.Errorf("expected every row exactly once, got %d rows", seen)
+	}
+}
+
+func TestCodeSplitting(t *testing.T) {
+	var funcs strings.Builder
+	for i := range 8 {
+		fmt.Fprintf(&funcs, "// F%d does thing %d.\nfunc F%d() int {\n\treturn %d\n}\n\n", i, i, i, i)
+	}
+	markdown := "# Example\n\n```go\npackage example\n\n" + funcs.String() + "```\n\n```yaml\nkey: value\n```\n"
+
+	push := func(opts ...Option) []Chunk {
+		c, err := New(append([]Option{
+			WithChunkTokenBudget(40),
+			WithReservedOverheadRatio(0),
+			WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+			WithChunkHeader(func(ctx context.Context, fm fm.FrontMatterView) (string, error) { return "", nil }),
+		}, opts...)...)
+		if err != nil {
+			t.Fatalf("failed to create chunker: %v", err)
+		}
+		if err := c.Push(context.Background(), Input{Path: "test.md", Title: "test", Markdown: markdown}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		return c.Chunks()
+	}
+
+	if chunks := push(); len(chunks) != 1 {
+		t.Fatalf("expected a single jumbo chunk without splitting, got %d", len(chunks))
+	}
+
+	chunks := push(WithCodeSplitting())
+	if len(chunks) < 2 {
+		t.Fatalf("expected the code block to be split, got %d chunk(s)", len(chunks))
+	}
+	seen := 0
+	for _, chunk := range chunks {
+		if chunk.Tokens > 40 {
+			t.Errorf("chunk %d exceeds the budget: %d tokens", chunk.ChunkIndex, chunk.Tokens)
+		}
+		if strings.Count(chunk.Text, "```")%2 != 0 {
+			t.Errorf("expected balanced fences in chunk %d, got %q", chunk.ChunkIndex, chunk.Text)
+		}
+		if strings.Count(chunk.Text, "// F") != strings.Count(chunk.Text, "func F") {
+			t.Errorf("expected functions to stay with their doc comments in chunk %d, got %q", chunk.ChunkIndex, chunk.Text)
+		}
+		if strings.Contains(chunk.Text, "func F") && !slices.Contains(chunk.CodeLanguages, "go") {
+			t.Errorf("expected the go language in chunk %d, got %v", chunk.ChunkIndex, chunk.CodeLanguages)
+		}
+		seen += strings.Count(chunk.Text, "func F")
+	}
+	if seen != 8 {
+		t.Errorf("expected every function exactly once, got %d", seen)
+	}
+	if got := chunks[len(chunks)-1].CodeLanguages; !slices.Contains(got, "yaml") {
+		t.Errorf("expected the yaml language in the last chunk, got %v", got)
+	}
+	for _, chunk := range chunks {
+		if chunk.Metadata != nil {
+			t.Errorf("expected no section metadata in chunk %d, got %v", chunk.ChunkIndex, chunk.Metadata)
+		}
+	}
+}
+
+func TestWithPlainText(t *testing.T) {
+	markdown := "# Guide\n\nRead **the** [install guide](https://example.com/install) first.\n\n" +
+		"<div align=\"center\">\n  <img src=\"logo.png\">\n  <p>Project logo</p>\n</div>\n\n" +
+		"## Usage\n\n- run *it*\n- stop it\n"
+
+	push := func(opts ...Option) []Chunk {
+		c, err := New(append([]Option{
+			WithChunkTokenBudget(1000),
+			WithReservedOverheadRatio(0),
+			WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+			WithChunkHeader(func(ctx context.Context, fm fm.FrontMatterView) (string, error) { return "Header\n\n", nil }),
+		}, opts...)...)
+		if err != nil {
+			t.Fatalf("failed to create chunker: %v", err)
+		}
+		if err := c.Push(context.Background(), Input{Path: "test.md", Title: "test", Markdown: markdown}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		return c.Chunks()
+	}
+
+	if chunks := push(); chunks[0].PlainText != "" {
+		t.Errorf("expected no plain text by default, got %q", chunks[0].PlainText)
+	}
+
+	markdownTokens := push()[0].Tokens
+	chunks := push(WithPlainText(PlainTextOptions{}))
+	if len(chunks) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(chunks))
+	}
+	want := "Header\n\nGuide\n\nRead the install guide first.\n\nProject logo\n\nUsage\n\n- run it\n- stop it\n"
+	if chunks[0].PlainText != want {
+		t.Errorf("expected plain text %q, got %q", want, chunks[0].PlainText)
+	}
+	if !strings.Contains(chunks[0].Text, "**the**") {
+		t.Errorf("expected the markdown text to be kept, got %q", chunks[0].Text)
+	}
+	if chunks[0].Tokens != markdownTokens {
+		t.Errorf("expected tokens of the markdown text (%d), got %d", markdownTokens, chunks[0].Tokens)
+	}
+
+	chunks = push(WithPlainText(PlainTextOptions{LinkURLs: true, Budget: true}))
+	if !strings.Contains(chunks[0].PlainText, "install guide (https://example.com/install)") {
+		t.Errorf("expected the link URL in the plain text, got %q", chunks[0].PlainText)
+	}
+	if got := len(strings.Fields(chunks[0].PlainText)); chunks[0].Tokens != got {
+		t.Errorf("expected tokens of the plain text (%d), got %d", got, chunks[0].Tokens)
+	}
+
+	// An empty footnote renders as its number alone
+	c, err := New(
+		WithChunkTokenBudget(1000),
+		WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		WithPlainText(PlainTextOptions{}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create chunker: %v", err)
+	}
+	if err := c.Push(context.Background(), Input{Path: "note.md", Title: "note", Markdown: "See note[^1].\n\n[^1]:\n"}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if got := c.Chunks()[0].PlainText; !strings.HasSuffix(got, "See note[1].\n\n[1]\n") {
+		t.Errorf("expected the empty footnote as its number, got %q", got)
+	}
+
+	// Budgets apply to the plain text: every chunk fits, though its markdown may not
+	c, err = New(
+		WithChunkTokenBudget(8),
+		WithReservedOverheadRatio(0),
+		WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		WithChunkHeader(func(ctx context.Context, fm fm.FrontMatterView) (string, error) { return "", nil }),
+		WithPlainText(PlainTextOptions{Budget: true}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create chunker: %v", err)
+	}
+	if err := c.Push(context.Background(), Input{Path: "test.md", Title: "test", Markdown: markdown}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	for _, chunk := range c.Chunks() {
+		if chunk.Tokens > 8 || len(strings.Fields(chunk.PlainText)) > 8 {
+			t.Errorf("chunk %d exceeds the budget: %d tokens in %q", chunk.ChunkIndex, chunk.Tokens, chunk.PlainText)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package chunker
+
+import "strings"
+
+// declarations returns a function reporting whether a top-level declaration
+// starts at line i of a code block in the given language, so that the block
+// can be cut before it. Comments and decorators directly above a declaration
+// belong to it, so the cut is placed before them instead. Returns nil for
+// languages without a heuristic.
+//
+// Supported languages are Go, Python, JavaScript and TypeScript, which are
+// cut before unindented declaration keywords; YAML, which is cut before
+// unindented keys, list items and document markers; and JSON, which is cut
+// before the members of the outermost object or array.
+func declarations(lang string, lines []string) func(i int) bool {
+	var decl, preamble func(line string) bool
+	switch strings.ToLower(lang) {
+	case "go", "golang":
+		decl = hasPrefix("func ", "type ", "var ", "const ", "import ")
+		preamble = hasPrefix("//")
+	case "python", "py", "python3":
+		decl = hasPrefix("def ", "async def ", "class ")
+		preamble = hasPrefix("@", "#")
+	case "javascript", "js", "jsx", "mjs", "cjs", "typescript", "ts", "tsx":
+		decl = hasPrefix("function ", "async function ", "class ", "abstract class ", "export ",
+			"const ", "let ", "var ", "interface ", "type ", "enum ", "import ", "declare ")
+		preamble = func(line string) bool {
+			t := strings.TrimSpace(line)
+			return strings.HasPrefix(t, "//") || strings.HasPrefix(t, "/*") || strings.HasPrefix(t, "*") || strings.HasPrefix(t, "@")
+		}
+	case "yaml", "yml":
+		decl = isYAMLEntry
+		preamble = hasPrefix("#")
+	case "json", "jsonc", "json5":
+		return jsonMembers(lines)
+	default:
+		return nil
+	}
+
+	return func(i int) bool {
+		if i > 0 && preamble(lines[i-1]) {
+			return false // cut before the preamble instead
+		}
+		for j := i; j < len(lines); j++ {
+			if decl(lines[j]) {
+				return true
+			}
+			if !preamble(lines[j]) {
+				return false
+			}
+		}
+		return false
+	}
+}
+
+// hasPrefix returns a function reporting whether a line starts with any of
+// the prefixes, without indentation.
+func hasPrefix(prefixes ...string) func(line string) bool {
+	return func(line string) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(line, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isYAMLEntry reports whether a line starts a top-level YAML mapping entry,
+// sequence item or document.
+func isYAMLEntry(line string) bool {
+	if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' {
+		return false
+	}
+	if strings.HasPrefix(line, "---") || strings.HasPrefix(line, "- ") {
+		return true
+	}
+	key, _, found := strings.Cut(strings.TrimRight(line, "\r\n"), ":")
+	return found && key != "" && !strings.ContainsAny(key[:1], "{[")
+}
+
+// jsonMembers returns a function reporting whether line i starts a member of
+// the outermost JSON object or array, by tracking bracket depth outside of
+// strings.
+func jsonMembers(lines []string) func(i int) bool {
+	depths := make([]int, len(lines)) // depth at the start of each line
+	depth, inString, escaped := 0, false, false
+	for i, line := range lines {
+		depths[i] = depth
+		for _, r := range line {
+			switch {
+			case escaped:
+				escaped = false
+			case inString && r == '\\':
+				escaped = true
+			case r == '"':
+				inString = !inString
+			case inString:
+			case r == '{' || r == '[':
+				depth++
+			case r == '}' || r == ']':
+				depth--
+			}
+		}
+	}
+	return func(i int) bool {
+		t := strings.TrimSpace(lines[i])
+		return depths[i] == 1 && t != "" && t[0] != '}' && t[0] != ']'
+	}
+}
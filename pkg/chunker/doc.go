@@ -28,10 +28,12 @@
 // The chunker operates through a pipeline:
 //
 //  1. Parse markdown into section tree with frontmatter
-//  2. Apply frontmatter transforms (inject metadata, validate, etc.)
-//  3. Apply section transforms (normalize text, add annotations)
-//  4. Tokenize the section tree
-//  5. Generate chunks using greedy algorithm to fit token budget
+//  2. Apply derive transforms (compute frontmatter fields from the section tree)
+//  3. Apply frontmatter transforms (inject metadata, validate, etc.)
+//  4. Apply tree transforms (drop, merge or flatten sections)
+//  5. Apply section transforms (normalize text, add annotations)
+//  6. Tokenize the section tree
+//  7. Generate chunks using greedy algorithm to fit token budget
 //
 // # Transforms
 //
@@ -40,7 +42,7 @@
 //
 // Default section transforms:
 //   - NormalizeNewlines: Convert CRLF to LF
-//   - NormalizeHardWraps: Merge hard-wrapped paragraphs
+//   - NormalizeHardWraps: Merge hard-wrapped paragraphs (tables are left intact)
 //   - PruneLeadingBlankLines: Remove leading blank lines
 //   - PruneTrailingBlankLines: Remove trailing blank lines
 //   - CollapseBlankLines: Collapse 3+ blank lines to 2
@@ -56,11 +58,24 @@
 //	    chunker.WithReservedOverheadRatio(0.15),
 //	    chunker.WithTokenizer(myTokenizer),
 //	    chunker.WithParser(myParser),
+//	    chunker.WithMarkdown(myGoldmark),
 //	    chunker.WithChunkHeaderGenerator(myGenerator),
 //	    chunker.WithFrontMatterTransform(myTransform),
 //	    chunker.WithSectionTransform(myTransform),
 //	)
 //
+// # Multiple Targets
+//
+// NewMulti creates a MultiChunker that parses and transforms each document
+// once, then tokenizes and packs it separately for every named target:
+//
+//	mc, err := chunker.NewMulti(nil,
+//	    chunker.Target{Name: "small", Options: []chunker.Option{chunker.WithChunkTokenBudget(512)}},
+//	    chunker.Target{Name: "large", Options: []chunker.Option{chunker.WithChunkTokenBudget(8000)}},
+//	)
+//	err = mc.Push(ctx, input)
+//	small := mc.Chunks("small")
+//
 // # Chunk Headers
 //
 // The chunk header appears at the beginning of each chunk and typically
@@ -95,4 +110,76 @@
 // downstream processing in the embedding pipeline, ensuring the total
 // doesn't exceed limits. Chunk header tokens are separately accounted for
 // in each chunk's token count.
+//
+// # Inline Directives
+//
+// The packer honors directive markers left in section content by the parser
+// (see section.BreakMarker): content after a break starts a new chunk, and a
+// keep-together region is packed as one unit even across sections. Markers
+// are stripped from chunk text and do not count toward the budget.
+//
+// # Section Metadata
+//
+// Each chunk's Metadata holds the merged inherited metadata of the sections
+// whose content it contains (see section.Section.InheritedMetadata). When a
+// document has section metadata, the chunk header is generated again for
+// every chunk with its metadata in the context (context.ChunkMetadataFrom),
+// while the body budget is based on a header holding the metadata of the
+// whole document.
+//
+// # Deep Links
+//
+// Each chunk's Anchor is the anchor ID of the section it begins in. With
+// WithDeepLinks, chunks also get a URL made of the site base URL, the mapped
+// document path and that anchor, which header generators can print:
+//
+//	chunker, err := chunker.New(
+//	    chunker.WithChunkTokenBudget(1000),
+//	    chunker.WithDeepLinks("https://docs.example.com", nil),
+//	)
+//	// docs/guide.md, chunk starting in "## Install" → https://docs.example.com/docs/guide#install
+//
+// WithLinkResolution resolves relative links to other documents against the
+// path of the linking document. They are rewritten, by default to deep links,
+// and recorded in each chunk's Links, from which a link graph between chunks
+// and documents can be built. Links to missing documents, or to paths outside
+// the project, are logged as warnings.
+//
+// # Table and Code Splitting
+//
+// A section holding a large table exceeds the body budget as a whole and is
+// normally emitted as a single oversized chunk. With WithTableSplitting, such
+// content is split between table rows instead, every piece repeating the
+// header and delimiter rows:
+//
+//	| Code | Meaning |          | Code | Meaning |
+//	| ---- | ------- |          | ---- | ------- |
+//	| E1   | ...     |    +     | E3   | ...     |
+//	| E2   | ...     |          | E4   | ...     |
+//
+// WithCodeSplitting splits fenced code blocks the same way: every piece is a
+// well-formed block with the original opening fence. Cuts go before top-level
+// declarations in Go, Python, JavaScript, TypeScript, YAML and JSON, then at
+// blank lines, and between arbitrary lines only when nothing else fits. The
+// languages of the code blocks in a chunk are recorded in its CodeLanguages.
+//
+// # Plain Text
+//
+// Embedding models gain nothing from emphasis markers, link destinations or
+// HTML tags. With WithPlainText, every chunk also carries a plain-text
+// rendition of its body in PlainText, after the same header: markup is
+// dropped while link text, image alt text, the text of HTML blocks, list
+// markers and code lines are kept. With PlainTextOptions.Budget, the budget
+// applies to the plain text instead of the markdown, so chunks hold as much
+// as fits in the rendition that is embedded.
+//
+// # Minimum Chunk Size
+//
+// Greedy packing can leave a document's last chunk with only a few tokens of
+// body, e.g. a trailing "See also" list, repeating the full header. With
+// WithMinChunkTokens, the last two chunks are split again so that the tail
+// grows while both stay within the budget. The chunker's Stats (see
+// StatsReporter) reports how many tails were rebalanced or left below the
+// minimum because that was not possible. A chunk started by a break directive
+// is never rebalanced with the chunk before it.
 package chunker
@@ -0,0 +1,246 @@
+package chunker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	"github.com/wyvernzora/chunky/pkg/markdown"
+	"github.com/wyvernzora/chunky/pkg/section"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	gtext "github.com/yuin/goldmark/text"
+)
+
+// PathMapper maps a document path, as given in Input.Path, to a URL path
+// relative to the site base URL.
+type PathMapper func(path string) string
+
+// DefaultURLPath is the PathMapper used when WithDeepLinks is given none.
+// It uses forward slashes and strips the file extension, so "docs/guide.md"
+// becomes "docs/guide".
+func DefaultURLPath(p string) string {
+	p = filepath.ToSlash(p)
+	return strings.TrimSuffix(p, path.Ext(p))
+}
+
+// deepLinks builds chunk URLs from a site base URL and a path mapping.
+type deepLinks struct {
+	base    *url.URL
+	mapPath PathMapper
+}
+
+// newDeepLinks validates the base URL and returns the link builder.
+func newDeepLinks(baseURL string, mapPath PathMapper) (*deepLinks, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if !base.IsAbs() || base.Host == "" {
+		return nil, fmt.Errorf("base URL must be absolute, got %q", baseURL)
+	}
+	if mapPath == nil {
+		mapPath = DefaultURLPath
+	}
+	return &deepLinks{base: base, mapPath: mapPath}, nil
+}
+
+// url returns the deep link to the anchor in the document at path.
+// Without an anchor, the link points to the document itself.
+func (l *deepLinks) url(path, anchor string) string {
+	u := l.base.JoinPath(l.mapPath(path))
+	u.Fragment = anchor
+	return u.String()
+}
+
+// Link is a link from a chunk to another document of the same project.
+type Link struct {
+	// Path is the target document path, resolved against the path of the
+	// linking document into the form of Input.Path, e.g. "docs/install.md".
+	Path string
+
+	// Anchor is the fragment of the link, e.g. "linux". Empty if none.
+	Anchor string
+
+	// URL is the destination the link was rewritten to. Empty if the link
+	// was left as written.
+	URL string
+}
+
+// String returns the rewritten destination of the link or, if it was not
+// rewritten, its path and anchor.
+func (l Link) String() string {
+	switch {
+	case l.URL != "":
+		return l.URL
+	case l.Anchor != "":
+		return l.Path + "#" + l.Anchor
+	default:
+		return l.Path
+	}
+}
+
+// LinkRewriter returns the destination a link to another document is
+// rewritten to, e.g. a site URL or the ID of the target chunk in a search
+// index. Returning "" leaves the link as written.
+type LinkRewriter func(link Link) string
+
+// documentExtensions are the file extensions of link targets that are
+// treated as documents.
+var documentExtensions = []string{".md", ".markdown", ".mdx"}
+
+// linkResolver resolves and rewrites the relative links of a document.
+type linkResolver struct {
+	exists  func(path string) bool
+	rewrite LinkRewriter
+}
+
+// newLinkResolver returns the resolver configured by WithLinkResolution.
+// Links are rewritten to deep links when no rewriter is given and deep links
+// are enabled.
+func newLinkResolver(cfg *options) (*linkResolver, error) {
+	rewrite := cfg.linkResolution.rewrite
+	if rewrite == nil && cfg.deepLinks != nil {
+		links, err := newDeepLinks(cfg.deepLinks.baseURL, cfg.deepLinks.mapPath)
+		if err != nil {
+			return nil, fmt.Errorf("WithDeepLinks: %w", err)
+		}
+		rewrite = func(l Link) string { return links.url(l.Path, l.Anchor) }
+	}
+	return &linkResolver{exists: cfg.linkResolution.exists, rewrite: rewrite}, nil
+}
+
+// resolvedLinks are the links of a document after resolution.
+type resolvedLinks struct {
+	// byDest holds the resolved links by their destination as it now reads.
+	byDest map[string]Link
+
+	// references are the document's link reference definitions, with their
+	// destinations as they now read. Chunks are parsed with them, so that a
+	// reference link is recognized in a chunk without its definition.
+	references []parser.Reference
+}
+
+// resolveLinks rewrites the relative links to other documents in the content
+// of every section of the tree, in inline links and in link reference
+// definitions. Links to documents that do not exist or that lie outside the
+// project are left as written and reported as warnings.
+func (r *linkResolver) resolveLinks(ctx context.Context, md goldmark.Markdown, root *section.Section) resolvedLinks {
+	logger := cctx.Logger(ctx)
+	docPath := filepath.ToSlash(cctx.MustFileInfo(ctx).Path)
+	resolved := resolvedLinks{byDest: make(map[string]Link)}
+
+	var walk func(s *section.Section)
+	walk = func(s *section.Section) {
+		content := s.Content()
+		if strings.Contains(content, "](") || strings.Contains(content, "]:") {
+			src := []byte(content)
+			pc := parser.NewContext()
+			doc := md.Parser().Parse(gtext.NewReader(src), parser.WithContext(pc))
+			dests := append(markdown.LinkDestinations(doc, src), markdown.DefinitionDestinations(doc, src, pc)...)
+			slices.SortFunc(dests, func(a, b markdown.LinkDestination) int { return a.Start - b.Start })
+
+			var out strings.Builder
+			cursor := 0
+			rewritten := make(map[string]string)
+			for _, d := range dests {
+				link, ok := resolveLink(docPath, d.Dest)
+				if !ok {
+					continue
+				}
+				if link.Path == ".." || strings.HasPrefix(link.Path, "../") {
+					logger.Warn("chunker: internal link leaves the project",
+						slog.String("path", docPath),
+						slog.String("section", s.Title()),
+						slog.String("link", d.Dest))
+					continue
+				}
+				if r.exists != nil && !r.exists(link.Path) {
+					logger.Warn("chunker: broken internal link",
+						slog.String("path", docPath),
+						slog.String("section", s.Title()),
+						slog.String("link", d.Dest))
+					continue
+				}
+				dest := d.Dest
+				if r.rewrite != nil {
+					if link.URL = r.rewrite(link); link.URL != "" {
+						dest = link.URL
+						rewritten[d.Dest] = dest
+						out.WriteString(content[cursor:d.Start])
+						out.WriteString(dest)
+						cursor = d.End
+					}
+				}
+				resolved.byDest[dest] = link
+			}
+			if cursor > 0 {
+				out.WriteString(content[cursor:])
+				s.SetContent(out.String())
+			}
+
+			for _, ref := range pc.References() {
+				if dest, ok := rewritten[string(ref.Destination())]; ok {
+					ref = parser.NewReference(ref.Label(), []byte(dest), ref.Title())
+				}
+				resolved.references = append(resolved.references, ref)
+			}
+		}
+		for _, c := range s.Children() {
+			walk(c)
+		}
+	}
+	walk(root)
+	return resolved
+}
+
+// resolveLink resolves a link destination written in the document at docPath.
+// Returns false unless it is a relative link to a document. The resolved path
+// starts with ".." if the link climbs above the project root.
+func resolveLink(docPath, dest string) (Link, bool) {
+	u, err := url.Parse(dest)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return Link{}, false
+	}
+	if !slices.Contains(documentExtensions, strings.ToLower(path.Ext(u.Path))) {
+		return Link{}, false
+	}
+	return Link{Path: path.Join(path.Dir(docPath), u.Path), Anchor: u.Fragment}, true
+}
+
+// outgoingLinks returns the distinct links among the links in doc, inline or
+// by reference, looked up by destination.
+func outgoingLinks(doc parsedText, links map[string]Link) []Link {
+	if len(links) == 0 {
+		return nil
+	}
+	var out []Link
+	_ = ast.Walk(doc.node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		link, ok := n.(*ast.Link)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		if l, ok := links[string(link.Destination)]; ok && !slices.Contains(out, l) {
+			out = append(out, l)
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return out
+}
+
+// linkTargets returns the distinct targets of links (see Link.String), sorted.
+func linkTargets(links []Link) []string {
+	var targets []string
+	for _, l := range links {
+		targets = append(targets, l.String())
+	}
+	slices.Sort(targets)
+	return slices.Compact(targets)
+}
@@ -0,0 +1,202 @@
+package chunker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	pbuiltin "github.com/wyvernzora/chunky/pkg/parser/builtin"
+)
+
+// Target is a named packing configuration for a MultiChunker.
+//
+// Target options configure the stages that run once per target: the token
+// budget, reserved overhead, minimum chunk size, tokenizer and chunk header. Parser and transform
+// options are shared across targets and must be passed to NewMulti instead;
+// transforms supplied here are ignored.
+type Target struct {
+	// Name identifies the target, e.g. "openai-8k". Must be unique and non-empty.
+	Name string
+
+	// Options configure budget, overhead, minimum chunk size, tokenizer and
+	// header for this target.
+	Options []Option
+}
+
+// MultiChunker chunks documents for several targets in one pass.
+//
+// Each pushed document is parsed and transformed once, and the resulting
+// section tree is then tokenized and packed separately for every target.
+// This is useful when the same corpus is embedded with several models that
+// have different tokenizers and context limits.
+type MultiChunker interface {
+	// Push processes a document and adds its chunks to every target's collection.
+	// Documents with "do_not_embed: true" in frontmatter are skipped.
+	Push(ctx context.Context, input Input) error
+
+	// Targets returns the target names in the order they were configured.
+	Targets() []string
+
+	// Chunks returns all accumulated chunks for the named target.
+	// Returns nil if the target does not exist.
+	Chunks(target string) []Chunk
+
+	// EffectiveBudget returns the effective body budget for the named target.
+	// Returns 0 if the target does not exist.
+	EffectiveBudget(target string) int
+
+	// Stats returns packing statistics for the named target accumulated since
+	// the last Reset. Returns zero Stats if the target does not exist.
+	Stats(target string) Stats
+
+	// Reset clears all accumulated chunks and stats for every target.
+	Reset()
+}
+
+// NewMulti creates a MultiChunker with the given shared options and targets.
+//
+// Shared options configure the parser and transforms, and also act as the
+// defaults for every target; target options are applied after them.
+//
+// Returns an error if no targets are given, a target name is empty or
+// duplicated, or any target's configuration is invalid (see New).
+//
+// Example:
+//
+//	mc, err := chunker.NewMulti(
+//	    []chunker.Option{chunker.WithFrontMatterTransform(myTransform)},
+//	    chunker.Target{Name: "small", Options: []chunker.Option{
+//	        chunker.WithChunkTokenBudget(512),
+//	        chunker.WithTokenizer(wordTok),
+//	    }},
+//	    chunker.Target{Name: "large", Options: []chunker.Option{
+//	        chunker.WithChunkTokenBudget(8000),
+//	    }},
+//	)
+func NewMulti(shared []Option, targets ...Target) (MultiChunker, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("NewMulti requires at least one target")
+	}
+
+	// Shared configuration drives the parse and transform stages
+	prep := defaultOptions()
+	for _, opt := range shared {
+		opt(prep)
+	}
+	if prep.parser == nil {
+		prep.parser = pbuiltin.DefaultParser
+	}
+
+	mc := &multiChunker{
+		prep:   prep,
+		byName: make(map[string]*defaultChunker, len(targets)),
+	}
+
+	for _, t := range targets {
+		if t.Name == "" {
+			return nil, fmt.Errorf("target name cannot be empty")
+		}
+		if _, exists := mc.byName[t.Name]; exists {
+			return nil, fmt.Errorf("duplicate target name %q", t.Name)
+		}
+
+		cfg := defaultOptions()
+		for _, opt := range shared {
+			opt(cfg)
+		}
+		for _, opt := range t.Options {
+			opt(cfg)
+		}
+
+		c, err := newDefaultChunker(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("target %q: %w", t.Name, err)
+		}
+
+		mc.names = append(mc.names, t.Name)
+		mc.byName[t.Name] = c
+	}
+
+	return mc, nil
+}
+
+// multiChunker is the standard implementation of the MultiChunker interface.
+type multiChunker struct {
+	prep   *options
+	names  []string
+	byName map[string]*defaultChunker
+}
+
+// Push implements MultiChunker.Push.
+func (m *multiChunker) Push(ctx context.Context, input Input) error {
+	ctx, err := documentContext(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	doc, err := prepareDocument(ctx, m.prep, input)
+	if err != nil || doc == nil {
+		return err
+	}
+
+	// Pack every target before accumulating, so a failing target
+	// does not leave the others with a partial document.
+	packed := make([][]Chunk, len(m.names))
+	stats := make([]Stats, len(m.names))
+	for i, name := range m.names {
+		tctx := cctx.WithAttrs(ctx, slog.String("target", name))
+		chunks, s, err := m.byName[name].pack(tctx, doc)
+		if err != nil {
+			return fmt.Errorf("target %q: %w", name, err)
+		}
+		packed[i] = chunks
+		stats[i] = s
+	}
+
+	for i, name := range m.names {
+		c := m.byName[name]
+		c.chunks = append(c.chunks, packed[i]...)
+		c.stats = c.stats.Add(stats[i])
+	}
+
+	return nil
+}
+
+// Targets implements MultiChunker.Targets.
+func (m *multiChunker) Targets() []string {
+	out := make([]string, len(m.names))
+	copy(out, m.names)
+	return out
+}
+
+// Chunks implements MultiChunker.Chunks.
+func (m *multiChunker) Chunks(target string) []Chunk {
+	if c, ok := m.byName[target]; ok {
+		return c.Chunks()
+	}
+	return nil
+}
+
+// EffectiveBudget implements MultiChunker.EffectiveBudget.
+func (m *multiChunker) EffectiveBudget(target string) int {
+	if c, ok := m.byName[target]; ok {
+		return c.EffectiveBudget()
+	}
+	return 0
+}
+
+// Stats implements MultiChunker.Stats.
+func (m *multiChunker) Stats(target string) Stats {
+	if c, ok := m.byName[target]; ok {
+		return c.Stats()
+	}
+	return Stats{}
+}
+
+// Reset implements MultiChunker.Reset.
+func (m *multiChunker) Reset() {
+	for _, c := range m.byName {
+		c.Reset()
+	}
+}
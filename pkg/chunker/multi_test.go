@@ -0,0 +1,207 @@
+package chunker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	pbuiltin "github.com/wyvernzora/chunky/pkg/parser/builtin"
+	"github.com/wyvernzora/chunky/pkg/section"
+	tbuiltin "github.com/wyvernzora/chunky/pkg/tokenizer/builtin"
+)
+
+func newTestMulti(t *testing.T, shared []Option) MultiChunker {
+	t.Helper()
+	mc, err := NewMulti(shared,
+		Target{Name: "small", Options: []Option{
+			WithChunkTokenBudget(40),
+			WithReservedOverheadRatio(0),
+			WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		}},
+		Target{Name: "large", Options: []Option{
+			WithChunkTokenBudget(1000),
+			WithReservedOverheadRatio(0),
+			WithTokenizer(tbuiltin.NewCharCountTokenizer()),
+			WithChunkHeader(func(ctx context.Context, _ fm.FrontMatterView) (string, error) {
+				return "LARGE\n", nil
+			}),
+		}},
+	)
+	if err != nil {
+		t.Fatalf("NewMulti failed: %v", err)
+	}
+	return mc
+}
+
+const multiDoc = `# Intro
+
+one two three four five six seven eight nine ten
+
+## Part A
+
+one two three four five six seven eight nine ten
+
+## Part B
+
+one two three four five six seven eight nine ten
+`
+
+// TestNewMulti_Validation tests that NewMulti rejects invalid target sets
+func TestNewMulti_Validation(t *testing.T) {
+	tok := tbuiltin.NewWordCountTokenizer()
+	valid := []Option{WithChunkTokenBudget(100), WithTokenizer(tok)}
+
+	tests := []struct {
+		name    string
+		targets []Target
+		wantErr string
+	}{
+		{"no targets", nil, "at least one target"},
+		{"empty name", []Target{{Name: "", Options: valid}}, "name cannot be empty"},
+		{"duplicate name", []Target{{Name: "a", Options: valid}, {Name: "a", Options: valid}}, "duplicate target"},
+		{"invalid budget", []Target{{Name: "a", Options: []Option{WithTokenizer(tok)}}}, `target "a"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMulti(nil, tt.targets...)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestMultiChunker_ParsesOnce tests that each document is parsed once regardless of target count
+func TestMultiChunker_ParsesOnce(t *testing.T) {
+	calls := 0
+	counting := func(ctx context.Context, markdown []byte) (*section.Section, fm.FrontMatter, error) {
+		calls++
+		return pbuiltin.DefaultParser(ctx, markdown)
+	}
+
+	mc := newTestMulti(t, []Option{WithParser(counting)})
+	err := mc.Push(context.Background(), Input{Path: "doc.md", Title: "Doc", Markdown: multiDoc})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("expected parser to be called once, got %d", calls)
+	}
+}
+
+// TestMultiChunker_PerTargetPacking tests that each target packs with its own settings
+func TestMultiChunker_PerTargetPacking(t *testing.T) {
+	mc := newTestMulti(t, nil)
+	err := mc.Push(context.Background(), Input{Path: "doc.md", Title: "Doc", Markdown: multiDoc})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	if got := mc.Targets(); len(got) != 2 || got[0] != "small" || got[1] != "large" {
+		t.Errorf("unexpected targets: %v", got)
+	}
+
+	small := mc.Chunks("small")
+	large := mc.Chunks("large")
+	if len(small) < 2 {
+		t.Errorf("expected small target to split the document, got %d chunks", len(small))
+	}
+	if len(large) != 1 {
+		t.Fatalf("expected large target to produce 1 chunk, got %d", len(large))
+	}
+	if !strings.HasPrefix(large[0].Text, "LARGE\n") {
+		t.Errorf("expected large target header, got %q", large[0].Text)
+	}
+	for _, c := range small {
+		if strings.Contains(c.Text, "LARGE") {
+			t.Errorf("small target used large target header: %q", c.Text)
+		}
+	}
+
+	if mc.EffectiveBudget("small") != 40 || mc.EffectiveBudget("large") != 1000 {
+		t.Errorf("unexpected effective budgets: %d, %d", mc.EffectiveBudget("small"), mc.EffectiveBudget("large"))
+	}
+	if mc.Chunks("missing") != nil || mc.EffectiveBudget("missing") != 0 {
+		t.Error("expected zero values for unknown target")
+	}
+
+	mc.Reset()
+	if len(mc.Chunks("small")) != 0 || len(mc.Chunks("large")) != 0 {
+		t.Error("expected Reset to clear all targets")
+	}
+}
+
+// TestMultiChunker_TargetFailureIsAtomic tests that a failing target leaves no partial chunks
+func TestMultiChunker_TargetFailureIsAtomic(t *testing.T) {
+	mc, err := NewMulti(nil,
+		Target{Name: "ok", Options: []Option{
+			WithChunkTokenBudget(1000),
+			WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		}},
+		Target{Name: "tiny", Options: []Option{
+			WithChunkTokenBudget(1),
+			WithTokenizer(tbuiltin.NewWordCountTokenizer()),
+		}},
+	)
+	if err != nil {
+		t.Fatalf("NewMulti failed: %v", err)
+	}
+
+	err = mc.Push(context.Background(), Input{Path: "doc.md", Title: "Doc", Markdown: multiDoc})
+	if err == nil || !strings.Contains(err.Error(), `target "tiny"`) {
+		t.Fatalf("expected tiny target error, got %v", err)
+	}
+	if len(mc.Chunks("ok")) != 0 {
+		t.Errorf("expected no chunks for ok target after failure, got %d", len(mc.Chunks("ok")))
+	}
+}
+
+// TestMultiChunker_Stats tests that stats are tracked per target and cleared by Reset
+func TestMultiChunker_Stats(t *testing.T) {
+	tok := tbuiltin.NewWordCountTokenizer()
+	mc, err := NewMulti(nil,
+		Target{Name: "small", Options: []Option{
+			WithChunkTokenBudget(40),
+			WithReservedOverheadRatio(0),
+			WithTokenizer(tok),
+			WithMinChunkTokens(1000),
+		}},
+		Target{Name: "large", Options: []Option{
+			WithChunkTokenBudget(1000),
+			WithReservedOverheadRatio(0),
+			WithTokenizer(tok),
+		}},
+	)
+	if err != nil {
+		t.Fatalf("NewMulti failed: %v", err)
+	}
+
+	for _, path := range []string{"a.md", "b.md"} {
+		if err := mc.Push(context.Background(), Input{Path: path, Title: "Doc", Markdown: multiDoc}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+
+	// Every final chunk is below the minimum, so each document is counted once
+	small := mc.Stats("small")
+	if got := small.TailsRebalanced + small.TailsBelowMinimum; got != 2 {
+		t.Errorf("expected 2 small tails in small target, got %+v", small)
+	}
+	if large := mc.Stats("large"); large != (Stats{}) {
+		t.Errorf("expected no stats for target without minimum, got %+v", large)
+	}
+	if missing := mc.Stats("missing"); missing != (Stats{}) {
+		t.Errorf("expected zero stats for unknown target, got %+v", missing)
+	}
+
+	mc.Reset()
+	if s := mc.Stats("small"); s != (Stats{}) {
+		t.Errorf("expected Reset to clear stats, got %+v", s)
+	}
+}
@@ -1,11 +1,13 @@
 package chunker
 
 import (
+	"github.com/wyvernzora/chunky/pkg/derive"
 	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
 	"github.com/wyvernzora/chunky/pkg/header"
 	"github.com/wyvernzora/chunky/pkg/parser"
 	"github.com/wyvernzora/chunky/pkg/section"
 	"github.com/wyvernzora/chunky/pkg/tokenizer"
+	"github.com/yuin/goldmark"
 )
 
 // Option configures a Chunker instance.
@@ -15,11 +17,32 @@ type Option func(*options)
 type options struct {
 	chunkTokenBudget      int
 	reservedOverheadRatio float64
+	minChunkTokens        int
 	tokenizer             tokenizer.Tokenizer
 	parser                parser.Parser
 	headerGenerator       header.ChunkHeader
+	deriveTransforms      []derive.Transform
 	fmTransforms          []fm.Transform
+	treeTransforms        []section.TreeTransform
 	sectionTransforms     []section.Transform
+	deepLinks             *deepLinkOptions
+	linkResolution        *linkResolutionOptions
+	markdown              goldmark.Markdown
+	splitTables           bool
+	splitCode             bool
+	plainText             *PlainTextOptions
+}
+
+// deepLinkOptions holds the arguments of WithDeepLinks until New validates them.
+type deepLinkOptions struct {
+	baseURL string
+	mapPath PathMapper
+}
+
+// linkResolutionOptions holds the arguments of WithLinkResolution.
+type linkResolutionOptions struct {
+	exists  func(path string) bool
+	rewrite LinkRewriter
 }
 
 // WithChunkTokenBudget sets the maximum total tokens per chunk (frontmatter + body).
@@ -60,6 +83,90 @@ func WithReservedOverheadRatio(ratio float64) Option {
 	}
 }
 
+// WithDeepLinks enables deep links to the published site: each chunk's URL is
+// the base URL joined with mapPath(Input.Path), with the anchor of the section
+// the chunk begins in as the fragment, e.g.
+// "https://docs.example.com/guide/install#linux". A nil mapPath uses
+// DefaultURLPath.
+//
+// Chunk headers are rendered per chunk with the URL available from the
+// context (see context.ChunkURLFrom). New() will return an error if baseURL is
+// not an absolute URL.
+//
+// Example:
+//
+//	chunker, err := New(
+//	    WithChunkTokenBudget(1000),
+//	    WithDeepLinks("https://docs.example.com", func(path string) string {
+//	        return strings.TrimPrefix(DefaultURLPath(path), "docs/")
+//	    }),
+//	)
+func WithDeepLinks(baseURL string, mapPath PathMapper) Option {
+	return func(opts *options) {
+		opts.deepLinks = &deepLinkOptions{baseURL: baseURL, mapPath: mapPath}
+	}
+}
+
+// WithLinkResolution resolves relative links to other documents, such as
+// "../guide/install.md#linux", against the path of the linking document and
+// records them in Chunk.Links, so that a link graph can be built from the
+// chunks. Links whose target has a Markdown extension (.md, .markdown, .mdx)
+// count as links to documents; other links are left alone. Both inline links
+// and link reference definitions ("[guide]: ../guide/install.md") are
+// resolved, and a reference link is recorded in the chunks that use it even
+// if its definition ends up in another chunk.
+//
+// Each link is rewritten to rewrite(link), or left as written if that is
+// empty. A nil rewrite uses the deep link to the target section when
+// WithDeepLinks is also used, and otherwise leaves links as written. Links are
+// rewritten after all transforms, so the rewritten text is counted against the
+// budget, and the targets are available to chunk headers from the context (see
+// context.ChunkLinksFrom).
+//
+// Links that climb above the project root, such as "../../outside.md" from
+// "docs/index.md", are reported as warnings and left as written; exists is
+// never called for them. If exists is not nil, links to paths for which it
+// returns false are broken: they are reported as warnings and left as
+// written.
+//
+// Example:
+//
+//	chunker, err := New(
+//	    WithChunkTokenBudget(1000),
+//	    WithDeepLinks("https://docs.example.com", nil),
+//	    WithLinkResolution(func(path string) bool {
+//	        _, err := os.Stat(path)
+//	        return err == nil
+//	    }, nil),
+//	)
+func WithLinkResolution(exists func(path string) bool, rewrite LinkRewriter) Option {
+	return func(opts *options) {
+		opts.linkResolution = &linkResolutionOptions{exists: exists, rewrite: rewrite}
+	}
+}
+
+// WithMinChunkTokens sets the minimum body size, in tokens, of the last chunk
+// of each document. Default: 0 (no minimum).
+//
+// When the final chunk has a smaller body, such as a trailing "See also" list,
+// the last two chunks are rebalanced so the final chunk grows while both stay
+// within the body budget. StatsReporter.Stats reports how often this happens
+// and how often it is not possible.
+//
+// New() will return an error if the minimum is negative.
+//
+// Example:
+//
+//	chunker, err := New(
+//	    WithChunkTokenBudget(1000),
+//	    WithMinChunkTokens(100),
+//	)
+func WithMinChunkTokens(tokens int) Option {
+	return func(opts *options) {
+		opts.minChunkTokens = tokens
+	}
+}
+
 // WithTokenizer sets a custom tokenizer for counting tokens.
 // If not provided, defaults to TiktokenTokenizer with o200k_base encoding.
 //
@@ -92,6 +199,109 @@ func WithParser(p parser.Parser) Option {
 	}
 }
 
+// WithMarkdown sets the goldmark configuration used by the parser and all
+// transforms to recognize Markdown structure. If not provided, defaults to
+// markdown.Default(), which enables GFM, footnotes and definition lists.
+//
+// The configuration is passed to each stage in the context (see
+// context.Markdown), so custom parsers and transforms can use it too. A custom
+// goldmark instance should enable heading IDs and attributes, which the default
+// parser turns into section anchors and metadata; markdown.New does this.
+//
+// Example:
+//
+//	md, err := markdown.New(markdown.GFM, markdown.Typographer)
+//	if err != nil {
+//	    return err
+//	}
+//	chunker := New(
+//	    WithChunkTokenBudget(1000),
+//	    WithMarkdown(md),
+//	)
+func WithMarkdown(md goldmark.Markdown) Option {
+	return func(opts *options) {
+		opts.markdown = md
+	}
+}
+
+// WithTableSplitting splits GFM tables that do not fit into a chunk between
+// rows instead of emitting them as a single oversized chunk. Every piece
+// repeats the table's header and delimiter rows, so each chunk holds a
+// well-formed table with its column names.
+//
+// Only units exceeding the body budget are split, and only at top-level
+// tables recognized by the goldmark configuration (see WithMarkdown). Content
+// inside a keep-together directive is never split. To rewrite tables as
+// row-wise records instead, use section/builtin.TableRecordsTransform.
+//
+// Example:
+//
+//	chunker := New(
+//	    WithChunkTokenBudget(1000),
+//	    WithTableSplitting(),
+//	)
+func WithTableSplitting() Option {
+	return func(opts *options) {
+		opts.splitTables = true
+	}
+}
+
+// WithCodeSplitting splits fenced code blocks that do not fit into a chunk
+// instead of emitting them as a single oversized chunk. Every piece repeats
+// the opening fence with its language tag and ends with a closing fence, so
+// each chunk holds a well-formed code block.
+//
+// Code is cut before top-level declarations for Go, Python, JavaScript,
+// TypeScript, YAML and JSON, otherwise at blank lines, and only as a last
+// resort between arbitrary lines. As with WithTableSplitting, only units
+// exceeding the body budget are split, only at top-level blocks, and never
+// inside a keep-together directive.
+//
+// Example:
+//
+//	chunker := New(
+//	    WithChunkTokenBudget(1000),
+//	    WithCodeSplitting(),
+//	)
+func WithCodeSplitting() Option {
+	return func(opts *options) {
+		opts.splitCode = true
+	}
+}
+
+// PlainTextOptions configures the plain-text rendition of chunks added by
+// WithPlainText.
+type PlainTextOptions struct {
+	// LinkURLs keeps link destinations after the link text, e.g.
+	// "the docs (https://example.com/docs)". By default only the text is kept.
+	LinkURLs bool
+
+	// Budget measures chunks by their plain-text rendition instead of their
+	// markdown: token budgets, the minimum chunk size and Chunk.Tokens apply
+	// to Chunk.PlainText. Use it when the plain text is what gets embedded.
+	Budget bool
+}
+
+// WithPlainText adds a plain-text rendition of every chunk to
+// Chunk.PlainText, for embedding models that gain nothing from markdown
+// syntax. The rendition is produced from the goldmark AST of the chunk body
+// (see markdown.PlainText): emphasis markers, link destinations, images and
+// HTML tags and comments are dropped, keeping link text, alt text and the
+// text content of HTML blocks, while lists and code keep their structure.
+// The chunk header is kept as is.
+//
+// Example:
+//
+//	chunker := New(
+//	    WithChunkTokenBudget(1000),
+//	    WithPlainText(PlainTextOptions{Budget: true}),
+//	)
+func WithPlainText(opts PlainTextOptions) Option {
+	return func(o *options) {
+		o.plainText = &opts
+	}
+}
+
 // WithChunkHeader sets a custom generator for chunk headers.
 // If not provided, defaults to YAML frontmatter serialization.
 //
@@ -119,6 +329,33 @@ func WithChunkHeader(generator header.ChunkHeader) Option {
 	}
 }
 
+// WithDeriveTransform adds a derive transform to apply right after parsing,
+// before frontmatter transforms. Derive transforms see the section tree and
+// can compute frontmatter fields from the document body.
+//
+// Can be called multiple times to add multiple transforms.
+//
+// Example:
+//
+//	chunker := New(
+//	    WithChunkTokenBudget(1000),
+//	    WithDeriveTransform(builtin.Title("title")),
+//	)
+func WithDeriveTransform(transform derive.Transform) Option {
+	return func(opts *options) {
+		opts.deriveTransforms = append(opts.deriveTransforms, transform)
+	}
+}
+
+// WithDeriveTransforms adds multiple derive transforms at once.
+// This is a convenience function equivalent to calling WithDeriveTransform
+// multiple times.
+func WithDeriveTransforms(transforms ...derive.Transform) Option {
+	return func(opts *options) {
+		opts.deriveTransforms = append(opts.deriveTransforms, transforms...)
+	}
+}
+
 // WithFrontMatterTransform adds a frontmatter transform to apply after parsing.
 // Transforms run in the order they are added and can add, modify, or remove
 // frontmatter fields.
@@ -156,6 +393,34 @@ func WithFrontMatterTransforms(transforms ...fm.Transform) Option {
 	}
 }
 
+// WithTreeTransform adds a tree transform to apply after frontmatter
+// transforms and before section transforms. Tree transforms are called once
+// per document with the root section and can restructure the whole tree,
+// e.g. to drop or flatten sections.
+//
+// Can be called multiple times to add multiple transforms.
+//
+// Example:
+//
+//	chunker := New(
+//	    WithChunkTokenBudget(1000),
+//	    WithTreeTransform(builtin.FlattenTransform(3)),
+//	)
+func WithTreeTransform(transform section.TreeTransform) Option {
+	return func(opts *options) {
+		opts.treeTransforms = append(opts.treeTransforms, transform)
+	}
+}
+
+// WithTreeTransforms adds multiple tree transforms at once.
+// This is a convenience function equivalent to calling WithTreeTransform
+// multiple times.
+func WithTreeTransforms(transforms ...section.TreeTransform) Option {
+	return func(opts *options) {
+		opts.treeTransforms = append(opts.treeTransforms, transforms...)
+	}
+}
+
 // WithSectionTransform adds a section transform to apply before tokenization.
 // Transforms run in the order they are added and can modify the section tree.
 //
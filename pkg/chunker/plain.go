@@ -0,0 +1,42 @@
+package chunker
+
+import (
+	"github.com/wyvernzora/chunky/pkg/markdown"
+	"github.com/wyvernzora/chunky/pkg/tokenizer"
+)
+
+// plainText renders units as plain text for WithPlainText.
+type plainText struct {
+	count tokenizer.TokenCounter
+	opts  PlainTextOptions
+}
+
+// newPlainText returns a plain-text renderer, or nil if opts is nil.
+func newPlainText(count tokenizer.TokenCounter, opts *PlainTextOptions) *plainText {
+	if opts == nil {
+		return nil
+	}
+	return &plainText{count: count, opts: *opts}
+}
+
+// render sets the plain-text rendition of u, whose text was parsed into doc,
+// followed by a blank line to separate it from the next unit, and measures u
+// by it when budgets apply to it. Does nothing on a nil renderer.
+func (r *plainText) render(u *unit, doc parsedText) error {
+	if r == nil {
+		return nil
+	}
+	u.plain = markdown.PlainText(doc.node, doc.src, r.opts.LinkURLs)
+	if u.plain != "" {
+		u.plain += "\n\n"
+	}
+	if !r.opts.Budget {
+		return nil
+	}
+	tokens, err := r.count(u.plain)
+	if err != nil {
+		return err
+	}
+	u.tokens = tokens
+	return nil
+}
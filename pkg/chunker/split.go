@@ -0,0 +1,149 @@
+package chunker
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/wyvernzora/chunky/pkg/markdown"
+	"github.com/wyvernzora/chunky/pkg/tokenizer"
+)
+
+// splitter breaks units that exceed the body budget at structural points
+// inside them, so that they are packed into several chunks instead of a
+// single jumbo chunk.
+type splitter struct {
+	count  tokenizer.TokenCounter
+	tables bool // split GFM tables between rows, repeating the header rows
+	code   bool // split fenced code blocks, repeating the fences
+}
+
+// block is a splittable top-level block of a unit's text.
+type block struct {
+	start, end int
+	groups     func(lead string, budget int) ([]string, error)
+}
+
+// split returns the pieces of u, whose text was parsed into doc, each measured
+// with count. Pieces carry the anchor and metadata of u; only the first
+// inherits its break. Units kept together by a directive, and units without
+// anything to split at, are returned unchanged.
+func (s *splitter) split(u unit, doc parsedText, budget int) ([]unit, error) {
+	if (!s.tables && !s.code) || u.keep {
+		return []unit{u}, nil
+	}
+	src := doc.src
+
+	var blocks []block
+	if s.tables {
+		for _, t := range markdown.Tables(doc.node, src) {
+			blocks = append(blocks, block{t.Start, t.End, func(lead string, budget int) ([]string, error) {
+				return s.tableGroups(lead, t, budget)
+			}})
+		}
+	}
+	if s.code {
+		for _, c := range markdown.CodeBlocks(doc.node, src) {
+			blocks = append(blocks, block{c.Start, c.End, func(lead string, budget int) ([]string, error) {
+				return s.codeGroups(lead, c, budget)
+			}})
+		}
+	}
+	if len(blocks) == 0 {
+		return []unit{u}, nil
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].start < blocks[j].start })
+
+	var texts []string
+	cursor := 0
+	for _, b := range blocks {
+		groups, err := b.groups(string(src[cursor:b.start]), budget)
+		if err != nil {
+			return nil, err
+		}
+		texts = append(texts, groups...)
+		cursor = b.end
+	}
+	if rest := string(src[cursor:]); strings.TrimSpace(rest) != "" {
+		texts = append(texts, rest)
+	} else {
+		texts[len(texts)-1] += rest
+	}
+
+	units := make([]unit, 0, len(texts))
+	for i, t := range texts {
+		tokens, err := s.count(t)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, unit{
+			text:        t,
+			tokens:      tokens,
+			anchor:      u.anchor,
+			meta:        u.meta,
+			breakBefore: u.breakBefore && i == 0,
+		})
+	}
+	return units, nil
+}
+
+// leads reports whether the text before a block fits into the block's first
+// group, together with the repeated frame of the block and its first row or
+// line, so that a heading stays with its table or code. Returns the lead's
+// tokens when it does.
+func (s *splitter) leads(lead string, frame, first, budget int) (bool, int, error) {
+	if strings.TrimSpace(lead) == "" {
+		return true, 0, nil
+	}
+	tokens, err := s.count(lead)
+	if err != nil {
+		return false, 0, err
+	}
+	return tokens+frame+first <= budget, tokens, nil
+}
+
+// tableGroups splits a table into groups of consecutive rows that fit the
+// budget together with the header and delimiter rows, which every group
+// repeats. The text before the table leads the first group when it fits
+// there with at least one row; otherwise it is returned as a piece of its
+// own. A single row that does not fit is given a group of its own.
+func (s *splitter) tableGroups(lead string, t markdown.Table, budget int) ([]string, error) {
+	head := t.Header + t.Delimiter
+	headTokens, err := s.count(head)
+	if err != nil {
+		return nil, err
+	}
+	rowTokens := make([]int, len(t.Rows))
+	for i, row := range t.Rows {
+		if rowTokens[i], err = s.count(row); err != nil {
+			return nil, err
+		}
+	}
+
+	var groups []string
+	first := 0
+	if len(rowTokens) > 0 {
+		first = rowTokens[0]
+	}
+	ok, leadTokens, err := s.leads(lead, headTokens, first, budget)
+	if err != nil {
+		return nil, err
+	}
+	used := headTokens + leadTokens
+	if !ok {
+		groups = append(groups, lead)
+		lead, used = "", headTokens
+	}
+
+	var rows strings.Builder
+	for i, row := range t.Rows {
+		if rows.Len() > 0 && used+rowTokens[i] > budget {
+			groups = append(groups, lead+head+rows.String())
+			lead = ""
+			rows.Reset()
+			used = headTokens
+		}
+		rows.WriteString(row)
+		used += rowTokens[i]
+	}
+	return append(groups, lead+head+rows.String()), nil
+}
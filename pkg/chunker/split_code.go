@@ -0,0 +1,165 @@
+package chunker
+
+import (
+	"strings"
+
+	"github.com/wyvernzora/chunky/pkg/markdown"
+	"github.com/wyvernzora/chunky/pkg/tokenizer"
+)
+
+// Levels at which fenced code is split, from coarsest to finest.
+const (
+	levelBlock       = iota // the whole block
+	levelDeclaration        // before top-level declarations
+	levelBlank              // at blank lines
+	levelLine               // between any two lines
+)
+
+// codeGroups splits a fenced code block into groups of lines that fit the
+// budget together with the fences, which every group repeats, so that each
+// piece is a well-formed code block with the original language tag.
+//
+// Lines are cut before top-level declarations where the language is known
+// (see declarations), then at blank lines, and only as a last resort between
+// arbitrary lines; a coarser cut is always preferred when the code between
+// cuts fits. The text before the block leads the first group when it fits
+// there with the first line; otherwise it is returned as a piece of its own.
+func (s *splitter) codeGroups(lead string, c markdown.CodeBlock, budget int) ([]string, error) {
+	// Every piece but the last needs a closing fence ending in a line break
+	closing := c.Close
+	if closing == "" {
+		closing = closingFence(c.Fence)
+	} else if !strings.HasSuffix(closing, "\n") {
+		closing += "\n"
+	}
+	frame, err := s.count(c.Fence + closing)
+	if err != nil {
+		return nil, err
+	}
+	first := 0
+	if len(c.Lines) > 0 {
+		if first, err = s.count(c.Lines[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	var groups []string
+	ok, leadTokens, err := s.leads(lead, frame, first, budget)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		groups = append(groups, lead)
+		lead, leadTokens = "", 0
+	}
+
+	p := &codePacker{
+		count:  s.count,
+		budget: budget - frame,
+		used:   leadTokens,
+		cut:    declarations(c.Language, c.Lines),
+	}
+	if err := p.add(c.Lines, 0, levelBlock); err != nil {
+		return nil, err
+	}
+	p.flush()
+
+	for i, lines := range p.groups {
+		end := closing
+		if i == len(p.groups)-1 {
+			end = c.Close
+		}
+		groups = append(groups, lead+c.Fence+strings.Join(lines, "")+end)
+		lead = ""
+	}
+	if len(p.groups) == 0 {
+		groups = append(groups, lead+c.Fence+c.Close)
+	}
+	return groups, nil
+}
+
+// codePacker packs runs of code lines greedily into groups.
+type codePacker struct {
+	count  tokenizer.TokenCounter
+	budget int              // tokens available for code lines in a group
+	cut    func(i int) bool // reports a top-level declaration at line i; nil if unknown
+	groups [][]string       // completed groups
+	cur    []string         // lines of the open group
+	used   int              // tokens of the open group, including a leading text
+}
+
+// add packs lines, which start at index offset of the code block. A run that
+// does not fit into the open group starts a new one if it fits a group on its
+// own, and is otherwise cut at the next finer level.
+func (p *codePacker) add(lines []string, offset, level int) error {
+	n, err := p.count(strings.Join(lines, ""))
+	if err != nil {
+		return err
+	}
+	switch {
+	case p.used+n <= p.budget:
+		// Fits into the open group
+	case len(p.cur) > 0 && n <= p.budget:
+		p.flush()
+	case level < levelLine:
+		for _, r := range p.runs(lines, offset, level+1) {
+			if err := p.add(lines[r[0]:r[1]], offset+r[0], level+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		// A single line larger than the budget gets a group of its own
+		p.flush()
+	}
+	p.cur = append(p.cur, lines...)
+	p.used += n
+	return nil
+}
+
+// flush completes the open group, if any.
+func (p *codePacker) flush() {
+	if len(p.cur) > 0 {
+		p.groups = append(p.groups, p.cur)
+	}
+	p.cur = nil
+	p.used = 0
+}
+
+// runs cuts lines at the given level and returns the [start, end) bounds of
+// the resulting runs.
+func (p *codePacker) runs(lines []string, offset, level int) [][2]int {
+	var out [][2]int
+	start := 0
+	for i := 1; i < len(lines); i++ {
+		var cut bool
+		switch level {
+		case levelDeclaration:
+			cut = p.cut != nil && p.cut(offset+i)
+		case levelBlank:
+			cut = isBlankLine(lines[i-1]) && !isBlankLine(lines[i])
+		default:
+			cut = true
+		}
+		if cut {
+			out = append(out, [2]int{start, i})
+			start = i
+		}
+	}
+	return append(out, [2]int{start, len(lines)})
+}
+
+// closingFence returns a closing fence line matching an opening fence line.
+func closingFence(fence string) string {
+	trimmed := strings.TrimLeft(fence, " ")
+	indent := fence[:len(fence)-len(trimmed)]
+	if trimmed == "" {
+		return "```\n"
+	}
+	run := trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+	return indent + run + "\n"
+}
+
+func isBlankLine(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
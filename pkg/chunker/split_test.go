@@ -0,0 +1,207 @@
+package chunker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/wyvernzora/chunky/pkg/markdown"
+)
+
+// countLines counts one token per line, so table rows cost one token each.
+func countLines(s string) (int, error) {
+	return strings.Count(s, "\n"), nil
+}
+
+// splitUnit parses the text of u and splits it with s
+func splitUnit(s *splitter, u unit, budget int) ([]unit, error) {
+	return s.split(u, parseText(markdown.Default(), u.text, nil), budget)
+}
+
+func newTableSplitter() *splitter {
+	return &splitter{count: countLines, tables: true}
+}
+
+func TestSplitTable(t *testing.T) {
+	text := "## Codes\n\n| Code | Meaning |\n| --- | --- |\n| E1 | one |\n| E2 | two |\n| E3 | three |\n| E4 | four |\n\nAfter.\n"
+	u := unit{text: text, tokens: 11, anchor: "codes", meta: map[string]string{"a": "b"}, breakBefore: true}
+
+	pieces, err := splitUnit(newTableSplitter(), u, 5)
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+	want := []string{
+		"## Codes\n\n| Code | Meaning |\n| --- | --- |\n| E1 | one |\n",
+		"| Code | Meaning |\n| --- | --- |\n| E2 | two |\n| E3 | three |\n| E4 | four |\n",
+		"\nAfter.\n",
+	}
+	if len(pieces) != len(want) {
+		t.Fatalf("expected %d pieces, got %d: %+v", len(want), len(pieces), pieces)
+	}
+	for i, p := range pieces {
+		if p.text != want[i] {
+			t.Errorf("piece %d: expected %q, got %q", i, want[i], p.text)
+		}
+		if p.anchor != "codes" || p.meta["a"] != "b" {
+			t.Errorf("piece %d: expected anchor and metadata of the unit, got %q %v", i, p.anchor, p.meta)
+		}
+		if p.breakBefore != (i == 0) {
+			t.Errorf("piece %d: unexpected breakBefore %v", i, p.breakBefore)
+		}
+	}
+	if pieces[1].tokens != 5 {
+		t.Errorf("expected pieces to be measured, got %d tokens", pieces[1].tokens)
+	}
+}
+
+func TestSplitTable_LongLead(t *testing.T) {
+	lead := strings.Repeat("intro\n", 4)
+	text := lead + "| A |\n| - |\n| 1 |\n"
+	pieces, err := splitUnit(newTableSplitter(), unit{text: text, tokens: 7}, 5)
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+	if len(pieces) != 2 || pieces[0].text != lead || pieces[1].text != "| A |\n| - |\n| 1 |\n" {
+		t.Errorf("expected the lead as a piece of its own, got %+v", pieces)
+	}
+}
+
+func TestSplitTable_Unchanged(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *splitter
+		u    unit
+	}{
+		{"no table", newTableSplitter(), unit{text: "a\nb\nc\n", tokens: 3}},
+		{"keep together", newTableSplitter(), unit{text: "| A |\n| - |\n| 1 |\n| 2 |\n", tokens: 4, keep: true}},
+		{"disabled", &splitter{count: countLines}, unit{text: "| A |\n| - |\n| 1 |\n| 2 |\n", tokens: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pieces, err := splitUnit(tt.s, tt.u, 1)
+			if err != nil {
+				t.Fatalf("split failed: %v", err)
+			}
+			if len(pieces) != 1 || pieces[0].text != tt.u.text {
+				t.Errorf("expected the unit unchanged, got %+v", pieces)
+			}
+		})
+	}
+}
+
+func newCodeSplitter() *splitter {
+	return &splitter{count: countLines, code: true}
+}
+
+func TestSplitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		budget int
+		want   []string
+	}{
+		{
+			name:   "go declarations",
+			text:   "Intro.\n```go\npackage main\n\n// A does a.\nfunc A() {\n\n\treturn\n}\n\nfunc B() {}\n```\n",
+			budget: 8,
+			want: []string{
+				"Intro.\n```go\npackage main\n\n```\n",
+				"```go\n// A does a.\nfunc A() {\n\n\treturn\n}\n\n```\n",
+				"```go\nfunc B() {}\n```\n",
+			},
+		},
+		{
+			name:   "blank lines",
+			text:   "```\na\nb\n\nc\nd\n```\n",
+			budget: 5,
+			want: []string{
+				"```\na\nb\n\n```\n",
+				"```\nc\nd\n```\n",
+			},
+		},
+		{
+			name:   "any line",
+			text:   "~~~~ sh\na\nb\nc\n~~~~\n",
+			budget: 4,
+			want: []string{
+				"~~~~ sh\na\nb\n~~~~\n",
+				"~~~~ sh\nc\n~~~~\n",
+			},
+		},
+		{
+			name:   "unclosed",
+			text:   "```\na\nb\nc\n",
+			budget: 3,
+			want: []string{
+				"```\na\n```\n",
+				"```\nb\n```\n",
+				"```\nc\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pieces, err := splitUnit(newCodeSplitter(), unit{text: tt.text, tokens: countLinesOf(tt.text)}, tt.budget)
+			if err != nil {
+				t.Fatalf("split failed: %v", err)
+			}
+			var got []string
+			for _, p := range pieces {
+				got = append(got, p.text)
+				if p.tokens > tt.budget {
+					t.Errorf("piece exceeds budget: %q", p.text)
+				}
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("unexpected pieces:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitCode_Disabled(t *testing.T) {
+	text := "```\na\nb\nc\n```\n"
+	pieces, err := splitUnit(newTableSplitter(), unit{text: text, tokens: 5}, 3)
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+	if len(pieces) != 1 || pieces[0].text != text {
+		t.Errorf("expected the unit unchanged, got %+v", pieces)
+	}
+}
+
+func TestDeclarations(t *testing.T) {
+	tests := []struct {
+		lang  string
+		lines []string
+		want  []int
+	}{
+		{"go", []string{"package x", "", "// T is a type.", "type T int", "", "func f() {", "\tvar x int", "}"}, []int{2, 5}},
+		{"python", []string{"import os", "@cache", "def f():", "    def g():", "class C:"}, []int{1, 4}},
+		{"ts", []string{"/**", " * Doc.", " */", "export function f() {", "  const x = 1", "}", "interface I {}"}, []int{0, 6}},
+		{"yaml", []string{"# Settings", "a: 1", "b:", "  c: 2", "- item", "---"}, []int{0, 2, 4, 5}},
+		{"json", []string{"{", `  "a": {`, `    "b": "}"`, "  },", `  "c": [1, 2]`, "}"}, []int{1, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			cut := declarations(tt.lang, tt.lines)
+			var got []int
+			for i := range tt.lines {
+				if cut(i) {
+					got = append(got, i)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("expected cuts at %v, got %v", tt.want, got)
+			}
+		})
+	}
+	if declarations("brainfuck", nil) != nil {
+		t.Error("expected no heuristic for an unknown language")
+	}
+}
+
+func countLinesOf(s string) int {
+	n, _ := countLines(s)
+	return n
+}
@@ -0,0 +1,68 @@
+package chunker
+
+// StatsReporter is implemented by chunkers that report packing statistics,
+// including those returned by New:
+//
+//	if r, ok := c.(chunker.StatsReporter); ok {
+//	    stats := r.Stats()
+//	}
+type StatsReporter interface {
+	// Stats returns packing statistics accumulated since the last Reset.
+	Stats() Stats
+}
+
+// Stats counts how the final chunks of documents were handled when a minimum
+// chunk size is configured (see WithMinChunkTokens).
+type Stats struct {
+	// TailsRebalanced is the number of documents whose last two chunks were
+	// split again so that the final chunk grew.
+	TailsRebalanced int
+
+	// TailsBelowMinimum is the number of small final chunks that could be
+	// not be rebalanced and were emitted as they are.
+	TailsBelowMinimum int
+}
+
+// Add returns the sum of two Stats.
+func (s Stats) Add(other Stats) Stats {
+	return Stats{
+		TailsRebalanced:   s.TailsRebalanced + other.TailsRebalanced,
+		TailsBelowMinimum: s.TailsBelowMinimum + other.TailsBelowMinimum,
+	}
+}
+
+// tailOutcome describes what happened to the final chunk of a document.
+type tailOutcome int
+
+const (
+	// tailNone means the final chunk was large enough or absent.
+	tailNone tailOutcome = iota
+	// tailRebalanced means the last two chunks were rebalanced.
+	tailRebalanced
+	// tailSmall means the final chunk stayed below the minimum.
+	tailSmall
+)
+
+// stats returns the Stats contribution of a single outcome.
+func (o tailOutcome) stats() Stats {
+	switch o {
+	case tailRebalanced:
+		return Stats{TailsRebalanced: 1}
+	case tailSmall:
+		return Stats{TailsBelowMinimum: 1}
+	default:
+		return Stats{}
+	}
+}
+
+// String returns the outcome name used in log messages.
+func (o tailOutcome) String() string {
+	switch o {
+	case tailRebalanced:
+		return "rebalanced"
+	case tailSmall:
+		return "below_minimum"
+	default:
+		return "none"
+	}
+}
@@ -1,7 +1,15 @@
 package chunker
 
 import (
+	"strings"
+
+	"github.com/wyvernzora/chunky/pkg/markdown"
+	"github.com/wyvernzora/chunky/pkg/section"
 	"github.com/wyvernzora/chunky/pkg/tokenizer"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	gtext "github.com/yuin/goldmark/text"
 )
 
 // chunkDocumentParams holds parameters for document chunking.
@@ -11,7 +19,17 @@ type chunkDocumentParams struct {
 	frontBlock  string
 	frontTokens int
 	bodyBudget  int
+	minTokens   int
 	root        *tokenizer.TokenizedSection
+	count       tokenizer.TokenCounter
+	split       *splitter // nil disables splitting of oversized units
+	md          goldmark.Markdown
+	links       resolvedLinks
+	plain       *plainText // nil disables the plain-text rendition
+
+	// linkCount returns the tokens links add to the header of a chunk; nil
+	// if headers do not grow with links
+	linkCount func(links []Link) (int, error)
 }
 
 // chunkDocument splits a tokenized document into chunks based on token budgets.
@@ -19,12 +37,22 @@ type chunkDocumentParams struct {
 // Algorithm:
 //  1. Traverse the tokenized tree in pre-order (parent before children)
 //  2. Accumulate content units greedily into chunks
-//  3. When a unit doesn't fit, emit current chunk and start a new one
-//  4. Units exceeding bodyBudget get their own dedicated "jumbo" chunk
-//  5. Flush any remaining content as the final chunk
+//  3. When a unit doesn't fit, emit current chunk and start a new one. The
+//     header lines listing a chunk's links count against its body budget
+//  4. Units exceeding bodyBudget are split at structural points where enabled
+//     (see splitter); what still exceeds it gets a dedicated "jumbo" chunk.
+//     Where enabled, units are rendered as plain text and, if budgets apply
+//     to it, measured by their plain-text rendition (see plainText).
+//     Units following a break directive always start a new chunk
+//  5. If the remaining content is smaller than minTokens, rebalance the last
+//     two chunks
+//  6. Flush any remaining content as the final chunk
 //
-// Returns a slice of chunks, each containing frontmatter + portion of body.
-func chunkDocument(params chunkDocumentParams) []Chunk {
+// Returns a slice of chunks, each containing frontmatter + portion of body,
+// and what happened to a small final chunk.
+// Returns an error if counting the tokens of content split by directives, by
+// the splitter, rendered as plain text or of links fails.
+func chunkDocument(params chunkDocumentParams) ([]Chunk, tailOutcome, error) {
 	builder := newChunkBuilder(
 		params.filePath,
 		params.fileTitle,
@@ -32,14 +60,56 @@ func chunkDocument(params chunkDocumentParams) []Chunk {
 		params.frontTokens,
 		params.bodyBudget,
 	)
+	builder.plain = params.plain != nil
+	builder.linkCount = params.linkCount
 
 	var chunks []Chunk
 
 	// Traverse and accumulate units
-	units := traverseUnits(params.root)
+	units, err := traverseUnits(params.root, params.count)
+	if err != nil {
+		return nil, tailNone, err
+	}
 	for _, u := range units {
-		produced := builder.appendUnit(u.text, u.tokens)
-		chunks = append(chunks, produced...)
+		// Each text is parsed once; everything derived from it shares the AST
+		doc := parseText(params.md, u.text, params.links.references)
+		if err := params.plain.render(&u, doc); err != nil {
+			return nil, tailNone, err
+		}
+		pieces := []unit{u}
+		if u.tokens > params.bodyBudget && params.split != nil {
+			if pieces, err = params.split.split(u, doc, params.bodyBudget); err != nil {
+				return nil, tailNone, err
+			}
+		}
+		for _, p := range pieces {
+			pdoc := doc
+			if p.text != u.text {
+				pdoc = parseText(params.md, p.text, params.links.references)
+				if err := params.plain.render(&p, pdoc); err != nil {
+					return nil, tailNone, err
+				}
+			}
+			p.langs = markdown.CodeLanguages(pdoc.node, pdoc.src)
+			p.links = outgoingLinks(pdoc, params.links.byDest)
+			if p.breakBefore {
+				chunks = append(chunks, builder.breakChunk()...)
+			}
+			produced, err := builder.appendUnit(p)
+			if err != nil {
+				return nil, tailNone, err
+			}
+			chunks = append(chunks, produced...)
+		}
+	}
+
+	// Keep the final chunk from being much smaller than the rest
+	outcome, prev, err := builder.balanceTail(params.minTokens)
+	if err != nil {
+		return nil, tailNone, err
+	}
+	if prev != nil {
+		chunks[len(chunks)-1] = *prev
 	}
 
 	// Flush any remaining content
@@ -47,13 +117,20 @@ func chunkDocument(params chunkDocumentParams) []Chunk {
 		chunks = append(chunks, *final)
 	}
 
-	return chunks
+	return chunks, outcome, nil
 }
 
 // unit represents a single content unit from a tokenized section tree.
 type unit struct {
-	text   string
-	tokens int
+	text        string
+	tokens      int
+	anchor      string            // anchor of the section the unit starts in
+	meta        map[string]string // inherited section metadata
+	breakBefore bool              // a break directive precedes this unit
+	keep        bool              // joined by a keep-together directive; never split
+	langs       []string          // languages of fenced code blocks in text
+	links       []Link            // links to other documents in text
+	plain       string            // plain-text rendition of text, if enabled
 }
 
 // traverseUnits performs a pre-order traversal of the tokenized section tree,
@@ -61,26 +138,87 @@ type unit struct {
 //
 // The traversal uses an explicit stack to avoid recursion and processes nodes
 // in document order (parent before children, children in left-to-right order).
-func traverseUnits(root *tokenizer.TokenizedSection) []unit {
+//
+// Content containing directive markers is split at them (see section.BreakMarker):
+// content after a break marker becomes a unit that must start a new chunk, and
+// content between keep-together and end markers is joined into a single unit,
+// even across sections. The markers themselves are dropped, and the resulting
+// pieces are measured with count.
+func traverseUnits(root *tokenizer.TokenizedSection, count tokenizer.TokenCounter) ([]unit, error) {
 	if root == nil {
-		return nil
+		return nil, nil
 	}
 
-	var units []unit
-	stack := []*tokenizer.TokenizedSection{root}
+	var (
+		units   []unit
+		pending bool  // a break marker was seen since the last unit
+		group   *unit // open keep-together unit
+		depth   int   // keep-together nesting depth
+	)
+	emit := func(u unit) {
+		if group != nil {
+			if group.tokens == 0 {
+				group.anchor = u.anchor
+			}
+			group.text += u.text
+			group.tokens += u.tokens
+			group.meta = mergeMetadata(group.meta, u.meta)
+			return
+		}
+		u.breakBefore = pending
+		pending = false
+		units = append(units, u)
+	}
 
+	stack := []*tokenizer.TokenizedSection{root}
 	for len(stack) > 0 {
 		// Pop from stack
 		n := len(stack)
 		node := stack[n-1]
 		stack = stack[:n-1]
 
-		// Yield this node's self content if it has any tokens
-		if node.GetContentTokens() > 0 {
-			units = append(units, unit{
-				text:   node.GetSection().Content(),
-				tokens: node.GetContentTokens(),
-			})
+		content := node.GetSection().Content()
+		anchor := node.GetSection().Anchor()
+		meta := node.GetSection().InheritedMetadata()
+		if !section.HasDirectiveMarkers(content) {
+			// Yield this node's self content if it has any tokens
+			if node.GetContentTokens() > 0 {
+				emit(unit{text: content, tokens: node.GetContentTokens(), anchor: anchor, meta: meta})
+			}
+		} else {
+			for _, p := range splitMarkers(content) {
+				switch p.marker {
+				case section.BreakMarker:
+					// A break inside a keep-together region is ignored
+					if group == nil {
+						pending = true
+					}
+				case section.KeepTogetherMarker:
+					if depth == 0 {
+						group = &unit{keep: true}
+					}
+					depth++
+				case section.EndMarker:
+					if depth == 0 {
+						continue
+					}
+					if depth--; depth == 0 {
+						g := *group
+						group = nil
+						if g.tokens > 0 {
+							emit(g)
+						}
+					}
+				default:
+					tokens, err := count(p.text)
+					if err != nil {
+						return nil, err
+					}
+					if tokens > 0 {
+						emit(unit{text: p.text, tokens: tokens, anchor: anchor, meta: meta})
+					}
+				}
+			}
 		}
 
 		// Push children in reverse order to maintain document order when popping
@@ -90,5 +228,63 @@ func traverseUnits(root *tokenizer.TokenizedSection) []unit {
 		}
 	}
 
-	return units
+	// An unclosed keep-together region extends to the end of the document
+	if group != nil && group.tokens > 0 {
+		g := *group
+		group = nil
+		emit(g)
+	}
+
+	return units, nil
+}
+
+// parsedText is markdown source together with its goldmark AST.
+type parsedText struct {
+	src  []byte
+	node ast.Node
+}
+
+// parseText parses text with md. Reference links in text resolve against
+// references as well as the definitions in text itself.
+func parseText(md goldmark.Markdown, text string, references []parser.Reference) parsedText {
+	src := []byte(text)
+	pc := parser.NewContext()
+	for _, ref := range references {
+		pc.AddReference(ref)
+	}
+	return parsedText{src: src, node: md.Parser().Parse(gtext.NewReader(src), parser.WithContext(pc))}
+}
+
+// piece is either text or a directive marker from section content.
+type piece struct {
+	text   string
+	marker string
+}
+
+// splitMarkers splits content into text pieces and the directive markers
+// between them. Blank lines next to a marker are dropped with it.
+func splitMarkers(content string) []piece {
+	var pieces []piece
+	var buf strings.Builder
+	flush := func() {
+		text := strings.TrimLeft(buf.String(), "\n")
+		if strings.TrimSpace(text) != "" {
+			if len(pieces) == 0 {
+				text = buf.String() // keep leading blank lines of the section content
+			}
+			pieces = append(pieces, piece{text: text})
+		}
+		buf.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if section.IsDirectiveMarker(line) {
+			flush()
+			pieces = append(pieces, piece{marker: strings.TrimSpace(line)})
+			continue
+		}
+		buf.WriteString(line)
+	}
+	flush()
+	return pieces
 }
@@ -0,0 +1,58 @@
+package context
+
+import "context"
+
+type chunkMetaKeyType struct{}
+
+var chunkMetaKey chunkMetaKeyType
+
+// WithChunkMetadata returns a child context carrying the section metadata of
+// the chunk being rendered, for use by chunk header generators.
+func WithChunkMetadata(ctx context.Context, meta map[string]string) context.Context {
+	return context.WithValue(ctx, chunkMetaKey, meta)
+}
+
+// ChunkMetadataFrom returns the chunk's section metadata if present and non-empty.
+func ChunkMetadataFrom(ctx context.Context) (map[string]string, bool) {
+	if meta, ok := ctx.Value(chunkMetaKey).(map[string]string); ok && len(meta) > 0 {
+		return meta, true
+	}
+	return nil, false
+}
+
+type chunkURLKeyType struct{}
+
+var chunkURLKey chunkURLKeyType
+
+// WithChunkURL returns a child context carrying the deep link of the chunk
+// being rendered, for use by chunk header generators.
+func WithChunkURL(ctx context.Context, url string) context.Context {
+	return context.WithValue(ctx, chunkURLKey, url)
+}
+
+// ChunkURLFrom returns the chunk's deep link if present and non-empty.
+func ChunkURLFrom(ctx context.Context) (string, bool) {
+	if url, ok := ctx.Value(chunkURLKey).(string); ok && url != "" {
+		return url, true
+	}
+	return "", false
+}
+
+type chunkLinksKeyType struct{}
+
+var chunkLinksKey chunkLinksKeyType
+
+// WithChunkLinks returns a child context carrying the targets of the chunk's
+// links to other documents, for use by chunk header generators.
+func WithChunkLinks(ctx context.Context, links []string) context.Context {
+	return context.WithValue(ctx, chunkLinksKey, links)
+}
+
+// ChunkLinksFrom returns the targets of the chunk's links to other documents
+// if present and non-empty.
+func ChunkLinksFrom(ctx context.Context) ([]string, bool) {
+	if links, ok := ctx.Value(chunkLinksKey).([]string); ok && len(links) > 0 {
+		return links, true
+	}
+	return nil, false
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"log/slog"
 	"testing"
+
+	"github.com/wyvernzora/chunky/pkg/markdown"
 )
 
 func TestWithFileInfo(t *testing.T) {
@@ -239,3 +241,65 @@ func TestFileInfo_SpecialCharacters(t *testing.T) {
 		t.Errorf("Content not preserved with special characters")
 	}
 }
+
+func TestWithChunkMetadata(t *testing.T) {
+	ctx := WithChunkMetadata(context.Background(), map[string]string{"audience": "admins"})
+
+	meta, ok := ChunkMetadataFrom(ctx)
+	if !ok {
+		t.Fatal("expected chunk metadata in context")
+	}
+	if meta["audience"] != "admins" {
+		t.Errorf("audience = %q, want %q", meta["audience"], "admins")
+	}
+
+	if _, ok := ChunkMetadataFrom(WithChunkMetadata(ctx, nil)); ok {
+		t.Error("expected empty chunk metadata to be reported as missing")
+	}
+	if _, ok := ChunkMetadataFrom(context.Background()); ok {
+		t.Error("expected no chunk metadata in empty context")
+	}
+}
+
+func TestWithChunkURL(t *testing.T) {
+	ctx := WithChunkURL(context.Background(), "https://docs.example.com/guide#install")
+
+	url, ok := ChunkURLFrom(ctx)
+	if !ok || url != "https://docs.example.com/guide#install" {
+		t.Errorf("ChunkURLFrom = %q, %v", url, ok)
+	}
+	if _, ok := ChunkURLFrom(WithChunkURL(ctx, "")); ok {
+		t.Error("expected empty chunk URL to be reported as missing")
+	}
+}
+
+func TestWithChunkLinks(t *testing.T) {
+	ctx := WithChunkLinks(context.Background(), []string{"docs/install.md#linux"})
+
+	links, ok := ChunkLinksFrom(ctx)
+	if !ok || len(links) != 1 || links[0] != "docs/install.md#linux" {
+		t.Errorf("ChunkLinksFrom = %q, %v", links, ok)
+	}
+	if _, ok := ChunkLinksFrom(WithChunkLinks(ctx, nil)); ok {
+		t.Error("expected empty chunk links to be reported as missing")
+	}
+}
+
+func TestMarkdown_Default(t *testing.T) {
+	if Markdown(context.Background()) != markdown.Default() {
+		t.Error("expected markdown.Default() without a configured instance")
+	}
+	if WithMarkdown(context.Background(), nil) != context.Background() {
+		t.Error("expected nil configuration to leave context unchanged")
+	}
+}
+
+func TestMarkdown_WithMarkdown(t *testing.T) {
+	md, err := markdown.New()
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if Markdown(WithMarkdown(context.Background(), md)) != md {
+		t.Error("expected configured instance")
+	}
+}
@@ -25,6 +25,27 @@
 //	    fmt.Println(info.Path)
 //	}
 //
+// # Chunk Metadata
+//
+// The chunker stores the merged section metadata of each chunk in the context
+// passed to the chunk header generator:
+//
+//	if meta, ok := context.ChunkMetadataFrom(ctx); ok {
+//	    fmt.Println(meta["audience"])
+//	}
+//
+// Its deep link and the targets of its links to other documents are available
+// the same way through ChunkURLFrom and ChunkLinksFrom.
+//
+// # Markdown
+//
+// The goldmark configuration shared by the parser and transforms travels in
+// the context, so that every stage agrees on the document's block structure:
+//
+//	doc := context.Markdown(ctx).Parser().Parse(text.NewReader(src))
+//
+// If none is configured, markdown.Default() is returned.
+//
 // # Logging
 //
 // The package provides access to structured logging via slog:
@@ -44,7 +65,7 @@
 //  2. Parser receives context for logging
 //  3. Transforms access FileInfo and Logger
 //  4. Tokenizer uses context for cancellation
-//  5. Header generator reads FileInfo for metadata
+//  5. Header generator reads FileInfo and chunk metadata
 //
 // This allows transforms to access document metadata without
 // explicit parameter passing:
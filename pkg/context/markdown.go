@@ -0,0 +1,32 @@
+package context
+
+import (
+	"context"
+
+	"github.com/wyvernzora/chunky/pkg/markdown"
+	"github.com/yuin/goldmark"
+)
+
+type markdownKeyType struct{}
+
+var markdownKey markdownKeyType
+
+// WithMarkdown returns a child context carrying the goldmark configuration
+// used to parse Markdown throughout the pipeline.
+func WithMarkdown(ctx context.Context, md goldmark.Markdown) context.Context {
+	if md == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, markdownKey, md)
+}
+
+// Markdown retrieves the goldmark configuration from context, falling back to
+// markdown.Default().
+func Markdown(ctx context.Context) goldmark.Markdown {
+	if ctx != nil {
+		if md, ok := ctx.Value(markdownKey).(goldmark.Markdown); ok && md != nil {
+			return md
+		}
+	}
+	return markdown.Default()
+}
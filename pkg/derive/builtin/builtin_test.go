@@ -0,0 +1,87 @@
+package builtin
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	pbuiltin "github.com/wyvernzora/chunky/pkg/parser/builtin"
+	"github.com/wyvernzora/chunky/pkg/section"
+)
+
+// parse runs the default parser over a test document.
+func parse(t *testing.T, markdown string) (*section.Section, fm.FrontMatter) {
+	t.Helper()
+	root, frontmatter, err := pbuiltin.DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	return root, frontmatter
+}
+
+const guide = `---
+tags: [docs]
+---
+Some **intro** text that is
+wrapped over [two lines](http://example.com).
+
+# Getting Started with Chunky
+
+Install the ` + "`chunky`" + ` binary.
+
+` + "```sh\ngo install example.com/chunky@latest\n```" + `
+
+## Configuration
+
+- one item
+- two items
+`
+
+func TestWalkSections_Order(t *testing.T) {
+	root, _ := parse(t, "# A\n## B\n# C\n")
+	var titles []string
+	walkSections(root, func(s *section.Section) bool {
+		titles = append(titles, s.Title())
+		return s.Title() != "B"
+	})
+	if want := []string{"Untitled", "A", "B"}; !slices.Equal(titles, want) {
+		t.Errorf("expected %v, got %v", want, titles)
+	}
+}
+
+func TestProseText_SkipsCode(t *testing.T) {
+	root, _ := parse(t, guide)
+	text := proseText(context.Background(), root)
+	want := "Some intro text that is wrapped over two lines.\nGetting Started with Chunky\nInstall the chunky binary.\nConfiguration\none item\ntwo items"
+	if text != want {
+		t.Errorf("expected %q, got %q", want, text)
+	}
+}
+
+func TestProseText_TablesAndDefinitions(t *testing.T) {
+	root, _ := parse(t, "| Key | Value |\n| --- | --- |\n| a | one |\n\nTerm\n: Meaning\n")
+	text := proseText(context.Background(), root)
+	want := "Key\nValue\na\none\nTerm\nMeaning"
+	if text != want {
+		t.Errorf("expected %q, got %q", want, text)
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"one two  three", 3},
+		{"well-known -- e.g. v1.2", 3},
+		{"日本語です", 5},
+		{"go 言語", 3},
+	}
+	for _, tt := range tests {
+		if got := countWords(tt.text); got != tt.want {
+			t.Errorf("countWords(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
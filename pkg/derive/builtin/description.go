@@ -0,0 +1,57 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	"github.com/wyvernzora/chunky/pkg/derive"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Description returns a transform that sets the front matter field at key to
+// the plain text of the document's first paragraph, with Markdown formatting
+// and line breaks removed. Paragraphs inside lists, quotes and tables are not
+// considered.
+//
+// The field is left unchanged if it already exists or the document has no paragraph.
+//
+// Parameters:
+//   - key: The frontmatter field path for the description. If empty, defaults to "description".
+func Description(key string) derive.Transform {
+	if key == "" {
+		key = "description"
+	}
+	return func(ctx context.Context, frontmatter fm.FrontMatter, root *section.Section) error {
+		logger := cctx.Logger(ctx)
+
+		var desc string
+		walkSections(root, func(s *section.Section) bool {
+			src := []byte(s.Content())
+			doc := parseMarkdown(ctx, src)
+			for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+				if n.Kind() != ast.KindParagraph {
+					continue
+				}
+				if desc = inlineText(n, src); desc != "" {
+					return false
+				}
+			}
+			return true
+		})
+		if desc == "" {
+			logger.Debug("derive: no paragraph found for description")
+			return nil
+		}
+
+		set, err := setIfMissing(frontmatter, key, desc)
+		if err != nil {
+			return fmt.Errorf("Description: %w", err)
+		}
+		logger.Debug("derive: description", slog.Bool("set", set), slog.Int("length", len(desc)))
+		return nil
+	}
+}
@@ -0,0 +1,31 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     any
+	}{
+		{"first paragraph", guide, "Some intro text that is wrapped over two lines."},
+		{"nested section", "# Title\n\n```\ncode\n```\n\n## Sub\n\n> quoted\n\nFirst <b>real</b> paragraph.\n", "First real paragraph."},
+		{"existing", "---\ndescription: Mine\n---\nText.\n", "Mine"},
+		{"none", "# Title\n\n- list only\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, frontmatter := parse(t, tt.markdown)
+			if err := Description("")(context.Background(), frontmatter, root); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := frontmatter["description"]; got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
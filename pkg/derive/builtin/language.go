@@ -0,0 +1,165 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	"github.com/wyvernzora/chunky/pkg/derive"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+)
+
+// Language returns a transform that sets the front matter field at key to the
+// ISO 639-1 code of the document's natural language, detected from its prose
+// (see WordCount for what counts as prose).
+//
+// Detection is heuristic. Documents written mostly in a non-Latin script are
+// identified by script: ja, ko, zh, ru, uk, el, he, ar, hi and th. Latin-script
+// documents are identified by common function words: en, de, fr, es, pt, it
+// and nl. If no language is clearly dominant, the field is not set.
+//
+// The field is left unchanged if it already exists.
+//
+// Parameters:
+//   - key: The frontmatter field path for the language. If empty, defaults to "language".
+func Language(key string) derive.Transform {
+	if key == "" {
+		key = "language"
+	}
+	return func(ctx context.Context, frontmatter fm.FrontMatter, root *section.Section) error {
+		logger := cctx.Logger(ctx)
+
+		lang := detectLanguage(proseText(ctx, root))
+		if lang == "" {
+			logger.Debug("derive: language not detected")
+			return nil
+		}
+
+		set, err := setIfMissing(frontmatter, key, lang)
+		if err != nil {
+			return fmt.Errorf("Language: %w", err)
+		}
+		logger.Debug("derive: language", slog.String("language", lang), slog.Bool("set", set))
+		return nil
+	}
+}
+
+// scriptLanguages maps scripts to the language assumed for them, checked in order.
+// Kana comes before Han so that Japanese text with kanji is not taken for Chinese.
+var scriptLanguages = []struct {
+	lang   string
+	tables []*unicode.RangeTable
+}{
+	{"ja", []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana}},
+	{"ko", []*unicode.RangeTable{unicode.Hangul}},
+	{"zh", []*unicode.RangeTable{unicode.Han}},
+	{"ru", []*unicode.RangeTable{unicode.Cyrillic}},
+	{"el", []*unicode.RangeTable{unicode.Greek}},
+	{"he", []*unicode.RangeTable{unicode.Hebrew}},
+	{"ar", []*unicode.RangeTable{unicode.Arabic}},
+	{"hi", []*unicode.RangeTable{unicode.Devanagari}},
+	{"th", []*unicode.RangeTable{unicode.Thai}},
+}
+
+// stopwords are frequent function words used to tell Latin-script languages apart.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "it", "for", "with", "you", "this", "are", "be", "on", "or", "as", "can", "your", "from"},
+	"de": {"der", "die", "und", "das", "ist", "nicht", "mit", "ein", "eine", "den", "sie", "zu", "auf", "für", "werden", "wird", "auch", "sich", "dem", "oder"},
+	"fr": {"le", "la", "les", "et", "des", "est", "une", "un", "du", "dans", "pour", "que", "qui", "pas", "sur", "vous", "avec", "ce", "au", "sont"},
+	"es": {"el", "la", "los", "las", "y", "es", "del", "que", "en", "una", "por", "para", "con", "se", "su", "como", "al", "lo", "puede", "más"},
+	"pt": {"o", "os", "as", "e", "do", "da", "dos", "das", "não", "uma", "um", "para", "com", "que", "em", "é", "por", "como", "mais", "seu"},
+	"it": {"il", "lo", "gli", "e", "di", "che", "è", "una", "un", "per", "non", "con", "del", "della", "sono", "nel", "alla", "come", "anche", "si"},
+	"nl": {"de", "het", "een", "en", "van", "is", "niet", "dat", "op", "te", "voor", "met", "zijn", "wordt", "ook", "je", "naar", "kan", "bij", "dit"},
+}
+
+// stopwordLangs fixes the scoring order so ties resolve deterministically.
+var stopwordLangs = []string{"en", "de", "fr", "es", "pt", "it", "nl"}
+
+// detectLanguage returns the ISO 639-1 code of the text's language, or "" if undetermined.
+func detectLanguage(text string) string {
+	// Non-Latin scripts are identified by the share of letters they account for
+	letters, latin := 0, 0
+	scripts := make([]int, len(scriptLanguages))
+	cyrillicUkrainian := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for i, s := range scriptLanguages {
+			if unicode.In(r, s.tables...) {
+				scripts[i]++
+				break
+			}
+		}
+		switch r {
+		case 'і', 'ї', 'є', 'ґ', 'І', 'Ї', 'Є', 'Ґ':
+			cyrillicUkrainian++
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+
+	// Kana marks Japanese even when most characters are kanji
+	if scripts[0] > 0 && scripts[0]*10 >= letters {
+		return "ja"
+	}
+	best := -1
+	for i, n := range scripts {
+		if best < 0 || n > scripts[best] {
+			best = i
+		}
+	}
+	if scripts[best]*2 > letters {
+		lang := scriptLanguages[best].lang
+		if lang == "ru" && cyrillicUkrainian > 0 {
+			return "uk"
+		}
+		return lang
+	}
+	if latin*2 <= letters {
+		return ""
+	}
+
+	// Latin-script languages are identified by function words
+	counts := make(map[string]int)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		for _, lang := range stopwordLangs {
+			if slices.Contains(stopwords[lang], w) {
+				counts[lang]++
+			}
+		}
+	}
+
+	first, second := "", ""
+	for _, lang := range stopwordLangs {
+		switch {
+		case first == "" || counts[lang] > counts[first]:
+			first, second = lang, first
+		case second == "" || counts[lang] > counts[second]:
+			second = lang
+		}
+	}
+	// Require a few hits and a clear lead over the runner-up
+	if counts[first] < 3 || counts[first]*2 < counts[second]*3 {
+		return ""
+	}
+	return first
+}
+
+// isWordRune reports whether r can be part of a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,45 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "This is the guide for the tool and how to use it with your project.", "en"},
+		{"german", "Das ist die Anleitung für das Werkzeug und wie man es mit dem Projekt verwendet.", "de"},
+		{"french", "Voici le guide pour les utilisateurs et la configuration des projets dans un dossier.", "fr"},
+		{"spanish", "Esta es la guía para los usuarios y la configuración de los proyectos con el programa.", "es"},
+		{"japanese", "これはチャンキーの使い方を説明する文書です。設定方法も紹介します。", "ja"},
+		{"chinese", "这是一个用于说明如何使用工具的文档。", "zh"},
+		{"korean", "이 문서는 도구 사용 방법을 설명합니다.", "ko"},
+		{"russian", "Это руководство по использованию инструмента.", "ru"},
+		{"ukrainian", "Це інструкція з використання інструменту.", "uk"},
+		{"too short", "Install chunky.", ""},
+		{"no letters", "1234 -- 5678", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectLanguage(tt.text); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	root, frontmatter := parse(t, "# Anleitung\n\nDas ist die Anleitung für das Werkzeug und wie man es mit dem Projekt verwendet.\n\n```\nthe and of to is in that it for with\n```\n")
+
+	if err := Language("")(context.Background(), frontmatter, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frontmatter["language"] != "de" {
+		t.Errorf("expected de (code ignored), got %v", frontmatter["language"])
+	}
+}
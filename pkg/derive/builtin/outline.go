@@ -0,0 +1,67 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	"github.com/wyvernzora/chunky/pkg/derive"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Outline returns a transform that sets the front matter field at key to the
+// document's headings in document order, including headings kept in section
+// content such as an H1 promoted to the root. Each entry is a map with "level"
+// (1-6) and "title" keys:
+//
+//	outline:
+//	  - level: 1
+//	    title: Getting Started
+//	  - level: 2
+//	    title: Installation
+//
+// Headings deeper than maxLevel are omitted; a maxLevel <= 0 includes all headings.
+// The field is left unchanged if it already exists or the document has no headings.
+//
+// Parameters:
+//   - key: The frontmatter field path for the outline. If empty, defaults to "outline".
+//   - maxLevel: Deepest heading level to include.
+func Outline(key string, maxLevel int) derive.Transform {
+	if key == "" {
+		key = "outline"
+	}
+	return func(ctx context.Context, frontmatter fm.FrontMatter, root *section.Section) error {
+		outline := []any{}
+		add := func(level int, title string) {
+			if maxLevel <= 0 || level <= maxLevel {
+				outline = append(outline, map[string]any{"level": level, "title": title})
+			}
+		}
+		walkSections(root, func(s *section.Section) bool {
+			if !s.IsRoot() {
+				add(s.Level(), s.Title())
+			}
+			// Headings kept in content, such as an H1 promoted to the root
+			src := []byte(s.Content())
+			for n := parseMarkdown(ctx, src).FirstChild(); n != nil; n = n.NextSibling() {
+				if h, ok := n.(*ast.Heading); ok {
+					add(h.Level, inlineText(h, src))
+				}
+			}
+			return true
+		})
+		if len(outline) == 0 {
+			return nil
+		}
+
+		set, err := setIfMissing(frontmatter, key, outline)
+		if err != nil {
+			return fmt.Errorf("Outline: %w", err)
+		}
+		cctx.Logger(ctx).Debug("derive: outline", slog.Int("headings", len(outline)), slog.Bool("set", set))
+		return nil
+	}
+}
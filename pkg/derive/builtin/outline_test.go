@@ -0,0 +1,54 @@
+package builtin
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pbuiltin "github.com/wyvernzora/chunky/pkg/parser/builtin"
+)
+
+func TestOutline(t *testing.T) {
+	root, frontmatter := parse(t, "# A\n## B\n### C\n## D\n")
+
+	if err := Outline("", 2)(context.Background(), frontmatter, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []any{
+		map[string]any{"level": 1, "title": "A"},
+		map[string]any{"level": 2, "title": "B"},
+		map[string]any{"level": 2, "title": "D"},
+	}
+	if !reflect.DeepEqual(frontmatter["outline"], want) {
+		t.Errorf("expected %v, got %v", want, frontmatter["outline"])
+	}
+}
+
+func TestOutline_NoHeadings(t *testing.T) {
+	root, frontmatter := parse(t, "Just text.\n")
+
+	if err := Outline("toc", 0)(context.Background(), frontmatter, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := frontmatter["toc"]; ok {
+		t.Errorf("expected no outline, got %v", frontmatter["toc"])
+	}
+}
+
+func TestOutline_PromotedH1(t *testing.T) {
+	root, frontmatter, err := pbuiltin.NewParser(pbuiltin.WithPromotedTitle())(context.Background(), []byte("# A\n\n## B\n"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	if err := Outline("", 0)(context.Background(), frontmatter, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []any{
+		map[string]any{"level": 1, "title": "A"},
+		map[string]any{"level": 2, "title": "B"},
+	}
+	if !reflect.DeepEqual(frontmatter["outline"], want) {
+		t.Errorf("expected %v, got %v", want, frontmatter["outline"])
+	}
+}
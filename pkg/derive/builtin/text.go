@@ -0,0 +1,117 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"html"
+	"strings"
+
+	"github.com/wyvernzora/chunky/internal/cjk"
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// walkSections visits the section tree in document order (pre-order).
+// Returning false from fn stops the walk.
+func walkSections(s *section.Section, fn func(*section.Section) bool) bool {
+	if !fn(s) {
+		return false
+	}
+	for _, c := range s.Children() {
+		if !walkSections(c, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMarkdown parses one section's Markdown body into a goldmark AST, using
+// the configuration from the context.
+func parseMarkdown(ctx context.Context, src []byte) ast.Node {
+	return cctx.Markdown(ctx).Parser().Parse(text.NewReader(src))
+}
+
+// inlineText returns the plain text of a node's inline children, with
+// formatting, link targets and raw HTML stripped.
+func inlineText(n ast.Node, src []byte) string {
+	var buf bytes.Buffer
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := c.(type) {
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			buf.Write(t.Segment.Value(src))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.WriteString(html.UnescapeString(string(t.Value)))
+		case *ast.AutoLink:
+			buf.Write(t.Label(src))
+		case *ast.CodeSpan:
+			for cc := t.FirstChild(); cc != nil; cc = cc.NextSibling() {
+				if s, ok := cc.(*ast.Text); ok {
+					buf.Write(s.Segment.Value(src))
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// proseText returns the plain text of a section tree: heading titles and the
+// text of paragraphs, lists, quotes and tables. Code blocks and raw HTML are
+// skipped.
+func proseText(ctx context.Context, root *section.Section) string {
+	var parts []string
+	walkSections(root, func(s *section.Section) bool {
+		if !s.IsRoot() {
+			parts = append(parts, s.Title())
+		}
+		src := []byte(s.Content())
+		doc := parseMarkdown(ctx, src)
+		_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			if !entering {
+				return ast.WalkContinue, nil
+			}
+			switch n.Kind() {
+			case ast.KindFencedCodeBlock, ast.KindCodeBlock, ast.KindHTMLBlock:
+				return ast.WalkSkipChildren, nil
+			case ast.KindParagraph, ast.KindTextBlock, ast.KindHeading, east.KindTableCell, east.KindDefinitionTerm:
+				parts = append(parts, inlineText(n, src))
+				return ast.WalkSkipChildren, nil
+			}
+			return ast.WalkContinue, nil
+		})
+		return true
+	})
+	return strings.Join(parts, "\n")
+}
+
+// countWords counts words that contain a letter or digit, so runs of bare
+// punctuation such as "--" are skipped. Han ideographs and kana count one word
+// per character.
+func countWords(s string) int {
+	return cjk.CountWords(s, isWordRune)
+}
+
+// setIfMissing assigns a derived value unless the front matter already has one.
+// Reports whether the value was set.
+func setIfMissing(frontmatter fm.FrontMatter, key string, value any) (bool, error) {
+	if _, exists := frontmatter.GetPath(key); exists {
+		return false, nil
+	}
+	if err := frontmatter.SetPath(key, value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
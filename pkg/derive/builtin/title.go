@@ -0,0 +1,84 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	"github.com/wyvernzora/chunky/pkg/derive"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Title returns a transform that resolves the document title and uses it as
+// the title of the root section, which chunks report as their FileTitle and
+// heading path comments start from.
+//
+// The title is the front matter string at key if it is non-empty, otherwise
+// the first level-1 heading in the document, including an H1 kept in a
+// section's content, such as one promoted to the root by the parser. A title
+// taken from a heading is also written to the front matter at key. If neither
+// exists, the root keeps the title it was parsed with (usually the file name).
+//
+// Parameters:
+//   - key: The frontmatter field path for the title. If empty, defaults to "title".
+//
+// Example:
+//
+//	transform := Title("title")
+func Title(key string) derive.Transform {
+	if key == "" {
+		key = "title"
+	}
+	return func(ctx context.Context, frontmatter fm.FrontMatter, root *section.Section) error {
+		logger := cctx.Logger(ctx)
+
+		if v, ok := frontmatter.GetPath(key); ok {
+			if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+				root.SetTitle(strings.TrimSpace(s))
+				logger.Debug("derive: title from front matter", slog.String("title", root.Title()))
+				return nil
+			}
+		}
+
+		title := firstH1(ctx, root)
+		if title == "" {
+			logger.Debug("derive: no title found, keeping root title", slog.String("title", root.Title()))
+			return nil
+		}
+
+		if _, err := setIfMissing(frontmatter, key, title); err != nil {
+			return fmt.Errorf("Title: %w", err)
+		}
+		root.SetTitle(title)
+		logger.Debug("derive: title from first H1", slog.String("title", title))
+		return nil
+	}
+}
+
+// firstH1 returns the title of the first level-1 heading in document order,
+// whether it starts a section or appears within section content.
+func firstH1(ctx context.Context, root *section.Section) string {
+	var title string
+	walkSections(root, func(s *section.Section) bool {
+		if s.Level() == 1 {
+			title = strings.TrimSpace(s.Title())
+			if title != "" {
+				return false
+			}
+		}
+		src := []byte(s.Content())
+		for n := parseMarkdown(ctx, src).FirstChild(); n != nil; n = n.NextSibling() {
+			if h, ok := n.(*ast.Heading); ok && h.Level == 1 {
+				if title = inlineText(h, src); title != "" {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return title
+}
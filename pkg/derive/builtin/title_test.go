@@ -0,0 +1,64 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	pbuiltin "github.com/wyvernzora/chunky/pkg/parser/builtin"
+)
+
+func TestTitle_FromFirstH1(t *testing.T) {
+	root, frontmatter := parse(t, guide)
+
+	if err := Title("")(context.Background(), frontmatter, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if root.Title() != "Getting Started with Chunky" {
+		t.Errorf("expected root title from H1, got %q", root.Title())
+	}
+	if frontmatter["title"] != "Getting Started with Chunky" {
+		t.Errorf("expected title in front matter, got %v", frontmatter["title"])
+	}
+}
+
+func TestTitle_FrontMatterWins(t *testing.T) {
+	root, frontmatter := parse(t, "---\nmeta:\n  title: ' Custom '\n---\n# Heading\n")
+
+	if err := Title("meta.title")(context.Background(), frontmatter, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if root.Title() != "Custom" {
+		t.Errorf("expected root title from front matter, got %q", root.Title())
+	}
+	if v, _ := frontmatter.GetPath("meta.title"); v != " Custom " {
+		t.Errorf("expected front matter title to be unchanged, got %q", v)
+	}
+}
+
+func TestTitle_NoTitle(t *testing.T) {
+	root, frontmatter := parse(t, "---\ntitle: ''\n---\nJust text.\n\n## Not an H1\n")
+
+	if err := Title("title")(context.Background(), frontmatter, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if root.Title() != "Untitled" {
+		t.Errorf("expected root title to be kept, got %q", root.Title())
+	}
+	if frontmatter["title"] != "" {
+		t.Errorf("expected empty title to be left alone, got %v", frontmatter["title"])
+	}
+}
+
+func TestTitle_PromotedH1(t *testing.T) {
+	root, frontmatter, err := pbuiltin.NewParser(pbuiltin.WithPromotedTitle())(context.Background(), []byte("# Promoted\n\n## Sub\n"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	if err := Title("title")(context.Background(), frontmatter, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frontmatter["title"] != "Promoted" {
+		t.Errorf("expected promoted H1 in front matter, got %v", frontmatter["title"])
+	}
+}
@@ -0,0 +1,71 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	"github.com/wyvernzora/chunky/pkg/derive"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+)
+
+// DefaultWordsPerMinute is the reading speed used by ReadingTime when none is given.
+const DefaultWordsPerMinute = 200
+
+// WordCount returns a transform that sets the front matter field at key to
+// the number of prose words in the document: heading titles and the text of
+// paragraphs, lists, quotes and tables. Code blocks and raw HTML are not
+// counted. Han ideographs and kana count as a word each; Korean is counted by spaces.
+//
+// The field is left unchanged if it already exists.
+//
+// Parameters:
+//   - key: The frontmatter field path for the count. If empty, defaults to "word_count".
+func WordCount(key string) derive.Transform {
+	if key == "" {
+		key = "word_count"
+	}
+	return func(ctx context.Context, frontmatter fm.FrontMatter, root *section.Section) error {
+		words := countWords(proseText(ctx, root))
+		set, err := setIfMissing(frontmatter, key, words)
+		if err != nil {
+			return fmt.Errorf("WordCount: %w", err)
+		}
+		cctx.Logger(ctx).Debug("derive: word count", slog.Int("words", words), slog.Bool("set", set))
+		return nil
+	}
+}
+
+// ReadingTime returns a transform that sets the front matter field at key to
+// the estimated reading time in whole minutes, rounded up, based on the same
+// word count as WordCount. Documents with any prose take at least one minute.
+//
+// The field is left unchanged if it already exists.
+//
+// Parameters:
+//   - key: The frontmatter field path for the estimate. If empty, defaults to "reading_time".
+//   - wordsPerMinute: Reading speed. If <= 0, defaults to DefaultWordsPerMinute.
+func ReadingTime(key string, wordsPerMinute int) derive.Transform {
+	if key == "" {
+		key = "reading_time"
+	}
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = DefaultWordsPerMinute
+	}
+	return func(ctx context.Context, frontmatter fm.FrontMatter, root *section.Section) error {
+		words := countWords(proseText(ctx, root))
+		minutes := (words + wordsPerMinute - 1) / wordsPerMinute
+
+		set, err := setIfMissing(frontmatter, key, minutes)
+		if err != nil {
+			return fmt.Errorf("ReadingTime: %w", err)
+		}
+		cctx.Logger(ctx).Debug("derive: reading time",
+			slog.Int("words", words),
+			slog.Int("minutes", minutes),
+			slog.Bool("set", set))
+		return nil
+	}
+}
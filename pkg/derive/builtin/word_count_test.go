@@ -0,0 +1,56 @@
+package builtin
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWordCount(t *testing.T) {
+	root, frontmatter := parse(t, guide)
+
+	if err := WordCount("stats.words")(context.Background(), frontmatter, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Code blocks are excluded; headings and list items are included
+	if v, _ := frontmatter.GetPath("stats.words"); v != 22 {
+		t.Errorf("expected 22 words, got %v", v)
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		words int
+		wpm   int
+		want  int
+	}{
+		{"empty", 0, 0, 0},
+		{"short", 10, 0, 1},
+		{"exact", 400, 200, 2},
+		{"rounds up", 401, 200, 3},
+		{"custom speed", 300, 100, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, frontmatter := parse(t, strings.Repeat("word ", tt.words))
+			if err := ReadingTime("", tt.wpm)(context.Background(), frontmatter, root); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := frontmatter["reading_time"]; got != tt.want {
+				t.Errorf("expected %d minutes, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWordCount_KeepsExisting(t *testing.T) {
+	root, frontmatter := parse(t, "---\nword_count: 7\n---\none two\n")
+	if err := WordCount("")(context.Background(), frontmatter, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frontmatter["word_count"] != 7 {
+		t.Errorf("expected existing value to be kept, got %v", frontmatter["word_count"])
+	}
+}
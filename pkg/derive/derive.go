@@ -0,0 +1,32 @@
+package derive
+
+import (
+	"context"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+)
+
+// Transform derives front matter fields from a parsed document.
+// It runs right after parsing, before frontmatter and section transforms,
+// and receives the mutable frontmatter together with the document's section tree.
+//
+// Transforms may add or modify frontmatter keys and may rename the root
+// section, which becomes the document title. They should not otherwise
+// modify the section tree.
+type Transform func(ctx context.Context, fm fm.FrontMatter, root *section.Section) error
+
+// ApplyTransform applies the given transforms to the document in order.
+// Stops and returns on the first error.
+//
+// Example:
+//
+//	err := derive.ApplyTransform(ctx, frontmatter, root, builtin.Title("title"))
+func ApplyTransform(ctx context.Context, fm fm.FrontMatter, root *section.Section, ts ...Transform) error {
+	for _, t := range ts {
+		if err := t(ctx, fm, root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
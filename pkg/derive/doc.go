@@ -0,0 +1,38 @@
+// Package derive provides transforms that compute frontmatter fields from
+// the parsed document.
+//
+// Frontmatter transforms only see the frontmatter map and section transforms
+// only see one section at a time. Derive transforms run once per document
+// right after parsing and see both, so they can fill in metadata that is
+// implied by the document body:
+//
+//	type Transform func(ctx context.Context, fm frontmatter.FrontMatter, root *section.Section) error
+//
+// Because they run before frontmatter transforms, derived fields can be
+// validated by EnforceFields or ValidateSchema and rendered in chunk headers
+// like any other field.
+//
+// # Built-in Transforms
+//
+// The builtin subpackage provides:
+//
+//  1. Title: Front matter title, or the first H1; also renames the root section
+//  2. Description: Plain text of the first paragraph
+//  3. WordCount: Number of prose words, excluding code
+//  4. ReadingTime: Estimated reading time in minutes
+//  5. Outline: Heading titles and levels in document order
+//  6. Language: ISO 639-1 code of the document's natural language
+//
+// Every builtin leaves an existing field untouched, so authors can always
+// override a derived value in the document's front matter.
+//
+// # Usage Example
+//
+//	chunker, err := chunker.New(
+//	    chunker.WithChunkTokenBudget(1000),
+//	    chunker.WithDeriveTransforms(
+//	        builtin.Title("title"),
+//	        builtin.ReadingTime("reading_time", 200),
+//	    ),
+//	)
+package derive
@@ -0,0 +1,164 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+)
+
+// DefaultMetaFileName is the file InheritDirectoryMeta looks for in each directory.
+const DefaultMetaFileName = "_meta.yaml"
+
+// DirectoryMetaOption configures InheritDirectoryMeta.
+type DirectoryMetaOption func(*directoryMeta)
+
+// WithMetaFileName sets the name of the per-directory metadata file.
+// Defaults to DefaultMetaFileName.
+func WithMetaFileName(name string) DirectoryMetaOption {
+	return func(d *directoryMeta) {
+		if name != "" {
+			d.fileName = name
+		}
+	}
+}
+
+// InheritDirectoryMeta returns a transform that merges front matter defaults from
+// metadata files in every ancestor directory of the document.
+//
+// The document path is taken from the FileInfo in the context and must be relative
+// to the root of fsys. Starting at the root and walking down to the document's own
+// directory, each directory may contain a metadata file (see WithMetaFileName)
+// holding a YAML mapping. Keys from nearer directories replace keys from farther
+// ones, and keys already present in the document's front matter are never
+// overwritten. Merging happens on top-level keys only.
+//
+// Metadata files are read once per directory and cached for the lifetime of the
+// transform. Missing files are skipped; unreadable or malformed files fail the document.
+//
+// Example:
+//
+//	// docs/api/_meta.yaml:
+//	//   product: api
+//	//   audience: developer
+//	transform := InheritDirectoryMeta(os.DirFS(projectRoot))
+//
+//	// docs/api/auth.md now gets product and audience unless it sets them itself
+func InheritDirectoryMeta(fsys fs.FS, opts ...DirectoryMetaOption) fm.Transform {
+	d := &directoryMeta{
+		fsys:     fsys,
+		fileName: DefaultMetaFileName,
+		cache:    make(map[string]fm.FrontMatter),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return func(ctx context.Context, frontmatter fm.FrontMatter) error {
+		logger := cctx.Logger(ctx)
+
+		if frontmatter == nil {
+			return fmt.Errorf("InheritDirectoryMeta: frontmatter cannot be nil")
+		}
+
+		fi, ok := cctx.FileInfoFrom(ctx)
+		if !ok || fi.Path == "" {
+			return fmt.Errorf("InheritDirectoryMeta: file path not found in context")
+		}
+
+		docPath := path.Clean(filepath.ToSlash(fi.Path))
+		if !fs.ValidPath(docPath) {
+			return fmt.Errorf("InheritDirectoryMeta: path %q is not relative to the metadata root", fi.Path)
+		}
+
+		inherited := make(fm.FrontMatter)
+		for _, dir := range ancestorDirs(docPath) {
+			meta, err := d.load(dir)
+			if err != nil {
+				return fmt.Errorf("InheritDirectoryMeta: %w", err)
+			}
+			for key, value := range meta {
+				inherited[key] = value
+			}
+		}
+		// Cached metadata is shared by every document below its directory, so
+		// each document gets its own copy of nested maps and lists
+		inherited = inherited.Clone()
+
+		merged := 0
+		for key, value := range inherited {
+			if _, exists := frontmatter[key]; exists {
+				continue
+			}
+			frontmatter[key] = value
+			merged++
+		}
+
+		logger.Debug("inherit directory meta: completed",
+			slog.String("path", docPath),
+			slog.Int("merged", merged),
+			slog.Int("skipped", len(inherited)-merged))
+
+		return nil
+	}
+}
+
+type directoryMeta struct {
+	fsys     fs.FS
+	fileName string
+
+	mu    sync.Mutex
+	cache map[string]fm.FrontMatter
+}
+
+// load returns the parsed metadata file in dir, or nil if there is none.
+func (d *directoryMeta) load(dir string) (fm.FrontMatter, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if meta, ok := d.cache[dir]; ok {
+		return meta, nil
+	}
+
+	name := path.Join(dir, d.fileName)
+	data, err := fs.ReadFile(d.fsys, name)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		d.cache[dir] = nil
+		return nil, nil
+	}
+
+	var meta fm.FrontMatter
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	d.cache[dir] = meta
+	return meta, nil
+}
+
+// ancestorDirs returns the directories containing a slash-separated file path,
+// from the root (".") down to the file's own directory.
+func ancestorDirs(file string) []string {
+	var dirs []string
+	for dir := path.Dir(file); ; dir = path.Dir(dir) {
+		dirs = append(dirs, dir)
+		if dir == "." {
+			break
+		}
+	}
+	for i, j := 0, len(dirs)-1; i < j; i, j = i+1, j-1 {
+		dirs[i], dirs[j] = dirs[j], dirs[i]
+	}
+	return dirs
+}
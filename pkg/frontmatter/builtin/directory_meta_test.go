@@ -0,0 +1,145 @@
+package builtin
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+)
+
+func metaContext(path string) context.Context {
+	return cctx.WithFileInfo(context.Background(), cctx.FileInfo{Path: path, Title: "Test"})
+}
+
+func TestInheritDirectoryMeta_NearestWins(t *testing.T) {
+	fsys := fstest.MapFS{
+		"_meta.yaml":             {Data: []byte("product: docs\nlicense: MIT\n")},
+		"docs/api/_meta.yaml":    {Data: []byte("product: api\naudience: developer\n")},
+		"docs/api/auth/login.md": {Data: []byte("# Login")},
+	}
+
+	frontmatter := fm.FrontMatter{"title": "Login"}
+	err := InheritDirectoryMeta(fsys)(metaContext("docs/api/auth/login.md"), frontmatter)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := map[string]any{
+		"title":    "Login",
+		"product":  "api",
+		"audience": "developer",
+		"license":  "MIT",
+	}
+	for k, v := range want {
+		if frontmatter[k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, frontmatter[k])
+		}
+	}
+}
+
+func TestInheritDirectoryMeta_DocumentWins(t *testing.T) {
+	fsys := fstest.MapFS{
+		"docs/_meta.yaml": {Data: []byte("product: docs\naudience: everyone\n")},
+	}
+
+	frontmatter := fm.FrontMatter{"audience": "admin"}
+	err := InheritDirectoryMeta(fsys)(metaContext("docs/guide.md"), frontmatter)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if frontmatter["audience"] != "admin" {
+		t.Errorf("expected document value to be preserved, got %v", frontmatter["audience"])
+	}
+	if frontmatter["product"] != "docs" {
+		t.Errorf("expected inherited product, got %v", frontmatter["product"])
+	}
+}
+
+func TestInheritDirectoryMeta_CustomFileName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"docs/_meta.yaml":  {Data: []byte("product: ignored\n")},
+		"docs/.defaults.y": {Data: []byte("product: custom\n")},
+	}
+
+	frontmatter := fm.FrontMatter{}
+	err := InheritDirectoryMeta(fsys, WithMetaFileName(".defaults.y"))(metaContext("docs/guide.md"), frontmatter)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if frontmatter["product"] != "custom" {
+		t.Errorf("expected product from custom meta file, got %v", frontmatter["product"])
+	}
+}
+
+func TestInheritDirectoryMeta_NoMetaFiles(t *testing.T) {
+	frontmatter := fm.FrontMatter{"title": "Test"}
+	err := InheritDirectoryMeta(fstest.MapFS{})(metaContext("a/b/c.md"), frontmatter)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(frontmatter) != 1 {
+		t.Errorf("expected frontmatter unchanged, got %v", frontmatter)
+	}
+}
+
+func TestInheritDirectoryMeta_Errors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"bad/_meta.yaml": {Data: []byte("product: [unclosed\n")},
+	}
+	transform := InheritDirectoryMeta(fsys)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		fm      fm.FrontMatter
+		wantErr string
+	}{
+		{"malformed meta", metaContext("bad/doc.md"), fm.FrontMatter{}, "bad/_meta.yaml"},
+		{"outside root", metaContext("../escape.md"), fm.FrontMatter{}, "not relative"},
+		{"no file info", context.Background(), fm.FrontMatter{}, "file path not found"},
+		{"nil frontmatter", metaContext("doc.md"), nil, "cannot be nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := transform(tt.ctx, tt.fm)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestInheritDirectoryMeta_DocumentsDoNotShareValues(t *testing.T) {
+	fsys := fstest.MapFS{
+		"docs/_meta.yaml": {Data: []byte("owner:\n  team: docs\ntags: [guide]\n")},
+	}
+	transform := InheritDirectoryMeta(fsys)
+
+	first := fm.FrontMatter{}
+	if err := transform(metaContext("docs/a.md"), first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := first.SetPath("owner.team", "MUTATED"); err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	if err := first.SetPath("tags[0]", "MUTATED"); err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+
+	second := fm.FrontMatter{}
+	if err := transform(metaContext("docs/b.md"), second); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if team, _ := second.GetPath("owner.team"); team != "docs" {
+		t.Errorf("expected owner.team=docs for second document, got %v", team)
+	}
+	if tag, _ := second.GetPath("tags[0]"); tag != "guide" {
+		t.Errorf("expected tags[0]=guide for second document, got %v", tag)
+	}
+}
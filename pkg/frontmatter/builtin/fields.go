@@ -0,0 +1,329 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+)
+
+// FieldType is the expected type of a front matter field.
+type FieldType string
+
+const (
+	FieldAny     FieldType = ""        // any value
+	FieldString  FieldType = "string"  // string
+	FieldNumber  FieldType = "number"  // any integer or floating point number
+	FieldInteger FieldType = "integer" // integer, or a float without fractional part
+	FieldBool    FieldType = "bool"    // boolean
+	FieldList    FieldType = "list"    // YAML sequence
+	FieldMap     FieldType = "map"     // YAML mapping
+	FieldDate    FieldType = "date"    // date string (YYYY-MM-DD or RFC 3339) or time.Time
+)
+
+// Coercion is a conversion applied to a front matter field before its type is checked.
+type Coercion string
+
+const (
+	// CoerceSplit turns a comma-separated string into a list of trimmed,
+	// non-empty strings: "a, b" becomes ["a", "b"].
+	CoerceSplit Coercion = "split"
+
+	// CoerceRFC3339 turns a date or date-time string (or time.Time) into an
+	// RFC 3339 string: "2024-03-01" becomes "2024-03-01T00:00:00Z".
+	CoerceRFC3339 Coercion = "rfc3339"
+
+	// CoerceLowercase lowercases a string, or every string in a list.
+	CoerceLowercase Coercion = "lowercase"
+)
+
+// ParseFieldType converts a type name to a FieldType.
+func ParseFieldType(s string) (FieldType, error) {
+	switch t := FieldType(s); t {
+	case FieldAny, FieldString, FieldNumber, FieldInteger, FieldBool, FieldList, FieldMap, FieldDate:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown field type %q", s)
+	}
+}
+
+// ParseCoercion converts a coercion name to a Coercion.
+func ParseCoercion(s string) (Coercion, error) {
+	switch c := Coercion(s); c {
+	case CoerceSplit, CoerceRFC3339, CoerceLowercase:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unknown coercion %q", s)
+	}
+}
+
+// FieldRule declares the expectations for one front matter field.
+type FieldRule struct {
+	// Path is the field path, e.g. "summary", "metadata.owner" or "authors[0].name".
+	// See frontmatter.FrontMatter.GetPath for the syntax.
+	Path string
+
+	// Type is the expected type after coercion. FieldAny accepts every type.
+	Type FieldType
+
+	// Required fails documents where the field is missing or empty
+	// (nil, a blank string, or an empty list) after defaults are applied.
+	Required bool
+
+	// Default is set when the field is missing. Nil means no default.
+	Default any
+
+	// Coerce lists conversions applied in order before the type check.
+	Coerce []Coercion
+}
+
+// EnforceFields returns a transform that applies defaults and coercions to
+// front matter fields and then checks their presence and types.
+//
+// For each rule, in order:
+//  1. If the field is missing and a default is set, the default is assigned.
+//  2. Coercions are applied to the field's value, in the order listed.
+//     A coercion that does not apply to the value's type leaves it unchanged.
+//  3. Required fields must be present and non-empty.
+//  4. Present fields must match the rule's type.
+//
+// All failing rules are reported together in a single error.
+//
+// Example:
+//
+//	transform := EnforceFields(
+//	    FieldRule{Path: "summary", Type: FieldString, Required: true},
+//	    FieldRule{Path: "tags", Type: FieldList, Default: []any{},
+//	        Coerce: []Coercion{CoerceSplit, CoerceLowercase}},
+//	    FieldRule{Path: "date", Type: FieldDate, Coerce: []Coercion{CoerceRFC3339}},
+//	)
+func EnforceFields(rules ...FieldRule) fm.Transform {
+	rules = slices.Clone(rules)
+
+	return func(ctx context.Context, frontmatter fm.FrontMatter) error {
+		logger := cctx.Logger(ctx)
+
+		if frontmatter == nil {
+			logger.Error("frontmatter is nil")
+			return fmt.Errorf("EnforceFields: frontmatter cannot be nil")
+		}
+
+		var problems []string
+		for _, rule := range rules {
+			if err := enforceField(frontmatter, rule); err != nil {
+				logger.Error("front matter field rule failed",
+					slog.String("field", rule.Path),
+					slog.String("error", err.Error()))
+				problems = append(problems, fmt.Sprintf("%s: %v", rule.Path, err))
+			}
+		}
+
+		if len(problems) > 0 {
+			return fmt.Errorf("EnforceFields: %s", strings.Join(problems, "; "))
+		}
+
+		logger.Debug("front matter fields enforced", slog.Int("rules", len(rules)))
+		return nil
+	}
+}
+
+// enforceField applies a single rule to the front matter.
+func enforceField(frontmatter fm.FrontMatter, rule FieldRule) error {
+	value, ok := frontmatter.GetPath(rule.Path)
+	if !ok && rule.Default != nil {
+		value, ok = fm.DeepCopy(rule.Default), true
+		if err := frontmatter.SetPath(rule.Path, value); err != nil {
+			return err
+		}
+	}
+
+	if ok && len(rule.Coerce) > 0 {
+		coerced := value
+		for _, c := range rule.Coerce {
+			var err error
+			if coerced, err = coerce(coerced, c); err != nil {
+				return err
+			}
+		}
+		if err := frontmatter.SetPath(rule.Path, coerced); err != nil {
+			return err
+		}
+		value = coerced
+	}
+
+	if !ok || isEmptyValue(value) {
+		if rule.Required {
+			return fmt.Errorf("required field is missing or empty")
+		}
+		return nil
+	}
+
+	if !matchesFieldType(value, rule.Type) {
+		return fmt.Errorf("expected %s, got %T", rule.Type, value)
+	}
+	return nil
+}
+
+// coerce applies one coercion to a value.
+func coerce(value any, c Coercion) (any, error) {
+	switch c {
+	case CoerceSplit:
+		s, ok := value.(string)
+		if !ok {
+			return value, nil
+		}
+		out := []any{}
+		for _, part := range strings.Split(s, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+		return out, nil
+
+	case CoerceRFC3339:
+		switch v := value.(type) {
+		case time.Time:
+			return v.Format(time.RFC3339), nil
+		case string:
+			t, err := parseDate(v)
+			if err != nil {
+				return nil, err
+			}
+			return t.Format(time.RFC3339), nil
+		default:
+			return value, nil
+		}
+
+	case CoerceLowercase:
+		switch v := value.(type) {
+		case string:
+			return strings.ToLower(v), nil
+		case []any:
+			out := make([]any, len(v))
+			for i, item := range v {
+				if s, ok := item.(string); ok {
+					item = strings.ToLower(s)
+				}
+				out[i] = item
+			}
+			return out, nil
+		default:
+			return value, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown coercion %q", c)
+	}
+}
+
+// dateLayouts are the formats accepted for date strings, most specific first.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// parseDate parses a date string in any of dateLayouts; times without a zone are UTC.
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a date", s)
+}
+
+// matchesFieldType reports whether a non-empty value has the expected type.
+func matchesFieldType(value any, t FieldType) bool {
+	switch t {
+	case FieldAny:
+		return true
+	case FieldString:
+		_, ok := value.(string)
+		return ok
+	case FieldBool:
+		_, ok := value.(bool)
+		return ok
+	case FieldNumber:
+		_, ok := toFloat(value)
+		return ok
+	case FieldInteger:
+		f, ok := toFloat(value)
+		return ok && f == math.Trunc(f)
+	case FieldList:
+		_, ok := value.([]any)
+		return ok
+	case FieldMap:
+		switch value.(type) {
+		case map[string]any, map[any]any:
+			return true
+		}
+		return false
+	case FieldDate:
+		switch v := value.(type) {
+		case time.Time:
+			return true
+		case string:
+			_, err := parseDate(v)
+			return err == nil
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// isEmptyValue reports whether a value counts as missing for required fields.
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
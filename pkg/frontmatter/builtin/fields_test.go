@@ -0,0 +1,179 @@
+package builtin
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+)
+
+func TestEnforceFields_Required(t *testing.T) {
+	transform := EnforceFields(
+		FieldRule{Path: "summary", Type: FieldString, Required: true},
+		FieldRule{Path: "owner.team", Type: FieldString, Required: true},
+	)
+
+	tests := []struct {
+		name    string
+		fm      fm.FrontMatter
+		wantErr []string
+	}{
+		{"present", fm.FrontMatter{"summary": "ok", "owner": map[any]any{"team": "docs"}}, nil},
+		{"missing", fm.FrontMatter{}, []string{"summary: required", "owner.team: required"}},
+		{"blank", fm.FrontMatter{"summary": "  ", "owner": map[string]any{"team": "docs"}}, []string{"summary: required"}},
+		{"wrong type", fm.FrontMatter{"summary": 42, "owner": map[string]any{"team": "docs"}}, []string{"summary: expected string, got int"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := transform(context.Background(), tt.fm)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error containing %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestEnforceFields_Defaults(t *testing.T) {
+	transform := EnforceFields(
+		FieldRule{Path: "status", Type: FieldString, Default: "draft"},
+		FieldRule{Path: "meta.audience", Default: "internal"},
+		FieldRule{Path: "tags", Type: FieldList, Default: []any{"docs"}},
+		FieldRule{Path: "owners", Type: FieldList, Default: []any{map[string]any{"team": "docs"}}},
+	)
+
+	first := fm.FrontMatter{"status": "published"}
+	if err := transform(context.Background(), first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first["status"] != "published" {
+		t.Errorf("expected existing status to be kept, got %v", first["status"])
+	}
+	if meta, _ := first["meta"].(map[string]any); meta["audience"] != "internal" {
+		t.Errorf("expected nested default, got %v", first["meta"])
+	}
+
+	// Defaults must not be shared between documents
+	first["tags"] = append(first["tags"].([]any), "mutated")
+	second := fm.FrontMatter{}
+	if err := transform(context.Background(), second); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(second["tags"], []any{"docs"}) {
+		t.Errorf("expected fresh default list, got %v", second["tags"])
+	}
+
+	// Nor may values nested inside a default list
+	second["owners"].([]any)[0].(map[string]any)["team"] = "mutated"
+	third := fm.FrontMatter{}
+	if err := transform(context.Background(), third); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if owner := third["owners"].([]any)[0].(map[string]any); owner["team"] != "docs" {
+		t.Errorf("expected fresh nested default, got %v", third["owners"])
+	}
+}
+
+func TestEnforceFields_Coercions(t *testing.T) {
+	transform := EnforceFields(
+		FieldRule{Path: "tags", Type: FieldList, Coerce: []Coercion{CoerceSplit, CoerceLowercase}},
+		FieldRule{Path: "date", Type: FieldDate, Coerce: []Coercion{CoerceRFC3339}},
+		FieldRule{Path: "updated", Type: FieldDate, Coerce: []Coercion{CoerceRFC3339}},
+		FieldRule{Path: "aliases", Type: FieldList, Coerce: []Coercion{CoerceSplit, CoerceLowercase}},
+	)
+
+	frontmatter := fm.FrontMatter{
+		"tags":    "API, How-To, ,Reference",
+		"date":    "2024-03-01",
+		"updated": time.Date(2024, 3, 2, 10, 30, 0, 0, time.UTC),
+		"aliases": []any{"Foo", 3},
+	}
+	if err := transform(context.Background(), frontmatter); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if want := []any{"api", "how-to", "reference"}; !reflect.DeepEqual(frontmatter["tags"], want) {
+		t.Errorf("expected tags %v, got %v", want, frontmatter["tags"])
+	}
+	if frontmatter["date"] != "2024-03-01T00:00:00Z" {
+		t.Errorf("expected RFC3339 date, got %v", frontmatter["date"])
+	}
+	if frontmatter["updated"] != "2024-03-02T10:30:00Z" {
+		t.Errorf("expected RFC3339 time, got %v", frontmatter["updated"])
+	}
+	if want := []any{"foo", 3}; !reflect.DeepEqual(frontmatter["aliases"], want) {
+		t.Errorf("expected lists to be lowercased in place, got %v", frontmatter["aliases"])
+	}
+}
+
+func TestEnforceFields_BadDate(t *testing.T) {
+	transform := EnforceFields(FieldRule{Path: "date", Coerce: []Coercion{CoerceRFC3339}})
+
+	err := transform(context.Background(), fm.FrontMatter{"date": "last tuesday"})
+	if err == nil || !strings.Contains(err.Error(), `cannot parse "last tuesday"`) {
+		t.Errorf("expected date parse error, got %v", err)
+	}
+}
+
+func TestEnforceFields_Types(t *testing.T) {
+	tests := []struct {
+		typ   FieldType
+		good  any
+		bad   any
+		label string
+	}{
+		{FieldNumber, 1.5, "1.5", "number"},
+		{FieldInteger, 3, 3.5, "integer"},
+		{FieldBool, true, "yes", "bool"},
+		{FieldList, []any{"a"}, "a", "list"},
+		{FieldMap, map[any]any{"a": 1}, []any{"a"}, "map"},
+		{FieldDate, "2024-01-01", "soon", "date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			transform := EnforceFields(FieldRule{Path: "v", Type: tt.typ})
+			if err := transform(context.Background(), fm.FrontMatter{"v": tt.good}); err != nil {
+				t.Errorf("expected %v to be a valid %s, got %v", tt.good, tt.typ, err)
+			}
+			if err := transform(context.Background(), fm.FrontMatter{"v": tt.bad}); err == nil {
+				t.Errorf("expected %v to be an invalid %s", tt.bad, tt.typ)
+			}
+		})
+	}
+}
+
+func TestEnforceFields_NilFrontMatter(t *testing.T) {
+	if err := EnforceFields()(context.Background(), nil); err == nil {
+		t.Fatal("expected error for nil frontmatter")
+	}
+}
+
+func TestParseFieldTypeAndCoercion(t *testing.T) {
+	if _, err := ParseFieldType("list"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := ParseFieldType("array"); err == nil {
+		t.Error("expected error for unknown type")
+	}
+	if _, err := ParseCoercion("rfc3339"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := ParseCoercion("uppercase"); err == nil {
+		t.Error("expected error for unknown coercion")
+	}
+}
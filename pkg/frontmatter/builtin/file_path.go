@@ -10,14 +10,15 @@ import (
 )
 
 // InjectFilePath returns a transform that injects the file path from context
-// into the frontmatter at the specified key. If the key already exists in the
+// into the frontmatter at the specified field path. If the path already exists in the
 // frontmatter, the transform skips injection and logs a debug message.
 //
 // The file path is retrieved from the FileInfo in the context. If no FileInfo
 // exists in the context or the path is empty, an error is returned.
 //
 // Parameters:
-//   - key: The frontmatter key to use for the file path. If empty, defaults to "file_path".
+//   - key: The frontmatter field path to use for the file path, e.g. "source.path".
+//     If empty, defaults to "file_path".
 //
 // Returns:
 //   - A Transform that injects the file path into frontmatter
@@ -38,7 +39,7 @@ func InjectFilePath(key string) fm.Transform {
 			return fmt.Errorf("InjectFilePath: frontmatter cannot be nil")
 		}
 
-		if _, exists := frontmatter[key]; exists {
+		if _, exists := frontmatter.GetPath(key); exists {
 			logger.Debug("file path key already exists in frontmatter, skipping injection",
 				slog.String("key", key))
 			return nil
@@ -50,7 +51,10 @@ func InjectFilePath(key string) fm.Transform {
 			return fmt.Errorf("InjectFilePath: file path not found in context")
 		}
 
-		frontmatter[key] = fi.Path
+		if err := frontmatter.SetPath(key, fi.Path); err != nil {
+			logger.Error("failed to inject file path", slog.String("key", key), slog.String("error", err.Error()))
+			return fmt.Errorf("InjectFilePath: %w", err)
+		}
 		logger.Debug("injected file path into frontmatter",
 			slog.String("key", key),
 			slog.String("path", fi.Path))
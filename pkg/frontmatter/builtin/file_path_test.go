@@ -55,6 +55,24 @@ func TestInjectFilePath_CustomKey(t *testing.T) {
 	}
 }
 
+func TestInjectFilePath_NestedKey(t *testing.T) {
+	frontmatter := fm.FrontMatter{
+		"source": map[any]any{"repo": "docs"},
+	}
+
+	ctx := cctx.WithFileInfo(context.Background(), cctx.FileInfo{Path: "guide.md"})
+
+	if err := InjectFilePath("source.path")(ctx, frontmatter); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if path, _ := frontmatter.GetPath("source.path"); path != "guide.md" {
+		t.Errorf("expected source.path 'guide.md', got %v", path)
+	}
+	if repo, _ := frontmatter.GetPath("source.repo"); repo != "docs" {
+		t.Errorf("expected sibling keys to be preserved, got %v", frontmatter["source"])
+	}
+}
+
 func TestInjectFilePath_AlreadyExists(t *testing.T) {
 	frontmatter := fm.FrontMatter{
 		"file_path": "/existing/path.md",
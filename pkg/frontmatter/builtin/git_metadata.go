@@ -0,0 +1,288 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+)
+
+// GitMetadataOption configures GitMetadata.
+type GitMetadataOption func(*gitMetadata)
+
+// WithGitKeyPrefix sets the prefix for the injected front matter keys.
+// Defaults to "git_".
+func WithGitKeyPrefix(prefix string) GitMetadataOption {
+	return func(g *gitMetadata) {
+		g.prefix = prefix
+	}
+}
+
+// WithGitCreatedDate also injects the date of the first commit that added the
+// file, following renames. Rename detection makes reading the history slower.
+func WithGitCreatedDate() GitMetadataOption {
+	return func(g *gitMetadata) {
+		g.created = true
+	}
+}
+
+// GitMetadata returns a transform that injects version control metadata for the
+// document from the git repository containing dir.
+//
+// The document path is taken from the FileInfo in the context and is resolved
+// relative to dir. The following keys are injected (shown with the default prefix):
+//   - git_blob: blob hash of the file's current contents on disk
+//   - git_commit: SHA of the last commit touching the file
+//   - git_author: name of that commit's author
+//   - git_author_email: email of that commit's author
+//   - git_modified: commit date of that commit (RFC 3339)
+//   - git_created: commit date of the first commit adding the file (RFC 3339),
+//     only with WithGitCreatedDate
+//
+// Commit keys are omitted for files that have never been committed. Keys already
+// present in the front matter are not overwritten. The history of the whole
+// repository is read with a single git log on first use and blob hashes are
+// taken from the index, so reuse one transform for the whole run; only files
+// with local changes are hashed separately.
+//
+// Returns an error if git is not installed or dir is not inside a git repository.
+//
+// Example:
+//
+//	transform := GitMetadata(projectRoot, WithGitCreatedDate())
+func GitMetadata(dir string, opts ...GitMetadataOption) fm.Transform {
+	g := &gitMetadata{
+		dir:    dir,
+		prefix: "git_",
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return func(ctx context.Context, frontmatter fm.FrontMatter) error {
+		logger := cctx.Logger(ctx)
+
+		if frontmatter == nil {
+			return fmt.Errorf("GitMetadata: frontmatter cannot be nil")
+		}
+
+		fi, ok := cctx.FileInfoFrom(ctx)
+		if !ok || fi.Path == "" {
+			return fmt.Errorf("GitMetadata: file path not found in context")
+		}
+
+		meta, err := g.lookup(ctx, fi.Path)
+		if err != nil {
+			return fmt.Errorf("GitMetadata: %w", err)
+		}
+
+		for key, value := range meta {
+			if _, exists := frontmatter[key]; exists {
+				logger.Debug("git metadata: skipped existing key", slog.String("key", key))
+				continue
+			}
+			frontmatter[key] = value
+		}
+
+		logger.Debug("git metadata: completed",
+			slog.String("path", fi.Path),
+			slog.Int("keys", len(meta)))
+
+		return nil
+	}
+}
+
+type gitMetadata struct {
+	dir     string
+	prefix  string
+	created bool
+
+	mu    sync.Mutex
+	index *gitIndex // read on first use
+}
+
+// gitIndex is what one pass over the repository reveals about its files,
+// keyed by slash-separated path relative to the transform's directory.
+type gitIndex struct {
+	blobs   map[string]string    // blob hashes of files in the git index
+	changed map[string]bool      // files whose contents differ from the git index
+	commits map[string]gitCommit // last commit touching each file
+	created map[string]string    // date of the first commit adding each file
+}
+
+// gitCommit is a commit as reported in the front matter.
+type gitCommit struct {
+	hash, author, email, date string
+}
+
+// lookup returns the git metadata for a file.
+func (g *gitMetadata) lookup(ctx context.Context, p string) (fm.FrontMatter, error) {
+	p = path.Clean(filepath.ToSlash(p))
+
+	idx, err := g.repository(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := make(fm.FrontMatter)
+	blob, ok := idx.blobs[p]
+	if !ok || idx.changed[p] {
+		if blob, err = g.git(ctx, "hash-object", "--", p); err != nil {
+			return nil, err
+		}
+	}
+	meta[g.prefix+"blob"] = blob
+
+	commit, ok := idx.commits[p]
+	if !ok {
+		cctx.Logger(ctx).Debug("git metadata: file has no commits", slog.String("path", p))
+		return meta, nil
+	}
+	meta[g.prefix+"commit"] = commit.hash
+	meta[g.prefix+"author"] = commit.author
+	meta[g.prefix+"author_email"] = commit.email
+	meta[g.prefix+"modified"] = commit.date
+	if g.created {
+		meta[g.prefix+"created"] = idx.created[p]
+	}
+	return meta, nil
+}
+
+// repository returns the repository index, reading it on first use.
+func (g *gitMetadata) repository(ctx context.Context) (*gitIndex, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.index == nil {
+		idx, err := g.readIndex(ctx)
+		if err != nil {
+			return nil, err
+		}
+		g.index = idx
+	}
+	return g.index, nil
+}
+
+// readIndex reads the blob hashes, local changes and history of every file
+// under the transform's directory.
+func (g *gitMetadata) readIndex(ctx context.Context) (*gitIndex, error) {
+	idx := &gitIndex{
+		blobs:   make(map[string]string),
+		changed: make(map[string]bool),
+		commits: make(map[string]gitCommit),
+		created: make(map[string]string),
+	}
+
+	// Entries read "<mode> <blob> <stage>\t<path>"
+	staged, err := g.git(ctx, "ls-files", "--stage", "-z")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range strings.Split(staged, "\x00") {
+		info, p, ok := strings.Cut(entry, "\t")
+		if fields := strings.Fields(info); ok && len(fields) == 3 {
+			idx.blobs[p] = fields[1]
+		}
+	}
+
+	changed, err := g.git(ctx, "diff", "--name-only", "--relative", "-z")
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range strings.Split(changed, "\x00") {
+		idx.changed[p] = true
+	}
+
+	if _, err := g.git(ctx, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return idx, nil // No commits yet
+	}
+
+	// Renames are only followed for creation dates
+	renames := "--no-renames"
+	if g.created {
+		renames = "-M"
+	}
+	history, err := g.git(ctx, "log", renames, "--name-status", "--relative", "-z",
+		"--format=%x1e%H%x1f%an%x1f%ae%x1f%cI")
+	if err != nil {
+		return nil, err
+	}
+	readHistory(idx, history)
+	return idx, nil
+}
+
+// readHistory records the last and first commit of every file from the output
+// of git log, newest commit first. Each commit is a record separator followed
+// by its header, a NUL and its NUL-separated changes: a status, then the path,
+// or the old and new path of renames.
+func readHistory(idx *gitIndex, history string) {
+	current := make(map[string]string) // earlier path of a renamed file -> path now
+	records := strings.Split(history, "\x1e")
+	for _, record := range records[1:] {
+		header, changes, _ := strings.Cut(record, "\x00")
+		fields := strings.Split(header, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		commit := gitCommit{hash: fields[0], author: fields[1], email: fields[2], date: normalizeGitDate(fields[3])}
+
+		tokens := strings.Split(strings.TrimLeft(changes, "\n"), "\x00")
+		for i := 0; i+1 < len(tokens); i += 2 {
+			status, p := tokens[i], tokens[i+1]
+			if status == "" {
+				break
+			}
+			old := p
+			if (status[0] == 'R' || status[0] == 'C') && i+2 < len(tokens) {
+				p = tokens[i+2]
+				i++
+			}
+			if now, ok := current[p]; ok {
+				p = now
+			}
+			if _, ok := idx.commits[p]; !ok {
+				idx.commits[p] = commit
+			}
+			idx.created[p] = commit.date
+			if status[0] == 'R' {
+				current[old] = p
+			}
+		}
+	}
+}
+
+// normalizeGitDate reformats git's strict ISO 8601 dates as RFC 3339,
+// e.g. "2024-01-01T00:00:00+00:00" becomes "2024-01-01T00:00:00Z".
+func normalizeGitDate(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return t.Format(time.RFC3339)
+}
+
+// git runs a git command in the configured directory and returns its trimmed output.
+func (g *gitMetadata) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", g.dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("git %s failed: %w", args[0], err)
+		}
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, msg)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
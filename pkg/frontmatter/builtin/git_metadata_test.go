@@ -0,0 +1,201 @@
+package builtin
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+)
+
+// newGitRepo creates a repository with two commits touching docs/guide.md,
+// the second of which renames docs/intro.md to docs/start.md.
+func newGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	gitCmd := func(date string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com",
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com",
+			"GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date,
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitCmd("2024-01-01T00:00:00Z", "init", "-q")
+	write("docs/guide.md", "# Guide\n")
+	write("docs/intro.md", "# Introduction\n\nWelcome to the project.\n")
+	gitCmd("2024-01-01T00:00:00Z", "add", ".")
+	gitCmd("2024-01-01T00:00:00Z", "commit", "-q", "-m", "add guide")
+	write("docs/guide.md", "# Guide\n\nMore text.\n")
+	gitCmd("2024-06-01T12:00:00Z", "mv", "docs/intro.md", "docs/start.md")
+	gitCmd("2024-06-01T12:00:00Z", "commit", "-q", "-am", "update guide")
+	write("docs/draft.md", "# Draft\n")
+	return dir
+}
+
+func gitContext(path string) context.Context {
+	return cctx.WithFileInfo(context.Background(), cctx.FileInfo{Path: path, Title: "Test"})
+}
+
+func TestGitMetadata_CommittedFile(t *testing.T) {
+	dir := newGitRepo(t)
+
+	frontmatter := fm.FrontMatter{}
+	err := GitMetadata(dir, WithGitCreatedDate())(gitContext("docs/guide.md"), frontmatter)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if frontmatter["git_author"] != "Ada" || frontmatter["git_author_email"] != "ada@example.com" {
+		t.Errorf("unexpected author: %v <%v>", frontmatter["git_author"], frontmatter["git_author_email"])
+	}
+	if frontmatter["git_modified"] != "2024-06-01T12:00:00Z" {
+		t.Errorf("expected last commit date, got %v", frontmatter["git_modified"])
+	}
+	if frontmatter["git_created"] != "2024-01-01T00:00:00Z" {
+		t.Errorf("expected first commit date, got %v", frontmatter["git_created"])
+	}
+	if sha, _ := frontmatter["git_commit"].(string); len(sha) != 40 {
+		t.Errorf("expected commit SHA, got %v", frontmatter["git_commit"])
+	}
+	if blob, _ := frontmatter["git_blob"].(string); len(blob) != 40 {
+		t.Errorf("expected blob hash, got %v", frontmatter["git_blob"])
+	}
+}
+
+func TestGitMetadata_UncommittedFile(t *testing.T) {
+	dir := newGitRepo(t)
+
+	frontmatter := fm.FrontMatter{}
+	err := GitMetadata(dir, WithGitCreatedDate())(gitContext("docs/draft.md"), frontmatter)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, ok := frontmatter["git_blob"]; !ok {
+		t.Error("expected blob hash for uncommitted file")
+	}
+	for _, key := range []string{"git_commit", "git_author", "git_modified", "git_created"} {
+		if _, ok := frontmatter[key]; ok {
+			t.Errorf("expected %s to be omitted for uncommitted file", key)
+		}
+	}
+}
+
+func TestGitMetadata_PrefixAndExistingKeys(t *testing.T) {
+	dir := newGitRepo(t)
+
+	frontmatter := fm.FrontMatter{"vcs_author": "Someone Else"}
+	err := GitMetadata(dir, WithGitKeyPrefix("vcs_"))(gitContext("docs/guide.md"), frontmatter)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if frontmatter["vcs_author"] != "Someone Else" {
+		t.Errorf("expected existing key to be preserved, got %v", frontmatter["vcs_author"])
+	}
+	if _, ok := frontmatter["vcs_commit"]; !ok {
+		t.Error("expected prefixed commit key")
+	}
+	if _, ok := frontmatter["vcs_created"]; ok {
+		t.Error("expected created date to be off by default")
+	}
+}
+
+func TestGitMetadata_Renamed(t *testing.T) {
+	dir := newGitRepo(t)
+
+	frontmatter := fm.FrontMatter{}
+	if err := GitMetadata(dir, WithGitCreatedDate())(gitContext("docs/start.md"), frontmatter); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if frontmatter["git_modified"] != "2024-06-01T12:00:00Z" {
+		t.Errorf("expected the rename as last commit, got %v", frontmatter["git_modified"])
+	}
+	if frontmatter["git_created"] != "2024-01-01T00:00:00Z" {
+		t.Errorf("expected the date the file was added under its old name, got %v", frontmatter["git_created"])
+	}
+}
+
+func TestGitMetadata_LocalChanges(t *testing.T) {
+	dir := newGitRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "docs/guide.md"), []byte("# Changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	frontmatter := fm.FrontMatter{}
+	if err := GitMetadata(dir)(gitContext("docs/guide.md"), frontmatter); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out, err := exec.Command("git", "-C", dir, "hash-object", "docs/guide.md").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.TrimSpace(string(out)); frontmatter["git_blob"] != want {
+		t.Errorf("expected blob of the file on disk %s, got %v", want, frontmatter["git_blob"])
+	}
+}
+
+func TestGitMetadata_ReadOnce(t *testing.T) {
+	dir := newGitRepo(t)
+	transform := GitMetadata(dir, WithGitCreatedDate())
+
+	first := fm.FrontMatter{}
+	if err := transform(gitContext("docs/guide.md"), first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Remove the repository; other committed files must be answered without git
+	if err := os.RemoveAll(filepath.Join(dir, ".git")); err != nil {
+		t.Fatal(err)
+	}
+
+	second := fm.FrontMatter{}
+	if err := transform(gitContext("docs/start.md"), second); err != nil {
+		t.Fatalf("expected a result from the first read, got %v", err)
+	}
+	if first["git_commit"] != second["git_commit"] || second["git_created"] != "2024-01-01T00:00:00Z" {
+		t.Errorf("expected the history read on first use, got %v", second)
+	}
+	if blob, _ := second["git_blob"].(string); len(blob) != 40 {
+		t.Errorf("expected blob hash from the index, got %v", second["git_blob"])
+	}
+}
+
+func TestGitMetadata_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "doc.md"), []byte("# Doc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+	err := GitMetadata(dir)(gitContext("doc.md"), fm.FrontMatter{})
+	if err == nil || !strings.Contains(err.Error(), "GitMetadata") {
+		t.Errorf("expected GitMetadata error, got %v", err)
+	}
+}
@@ -32,6 +32,10 @@ import (
 //	if err != nil {
 //	    // Handle missing or invalid summary
 //	}
+//
+// Deprecated: Use EnforceFields with
+// FieldRule{Path: "summary", Type: FieldString, Required: true}, which also
+// supports nested paths, defaults and coercions.
 func RequireSummary() fm.Transform {
 	return func(ctx context.Context, frontmatter fm.FrontMatter) error {
 		logger := cctx.Logger(ctx)
@@ -0,0 +1,82 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/frontmatter/schema"
+)
+
+// SchemaMode controls what ValidateSchema does with violations.
+type SchemaMode string
+
+const (
+	// SchemaModeFail fails the document, reporting every violation in the error.
+	SchemaModeFail SchemaMode = "fail"
+
+	// SchemaModeWarn logs each violation as a warning and lets the document through.
+	SchemaModeWarn SchemaMode = "warn"
+)
+
+// ParseSchemaMode converts a mode name to a SchemaMode.
+// Returns an error for anything other than "fail" or "warn".
+func ParseSchemaMode(s string) (SchemaMode, error) {
+	switch mode := SchemaMode(s); mode {
+	case SchemaModeFail, SchemaModeWarn:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid schema mode %q, must be %q or %q", s, SchemaModeFail, SchemaModeWarn)
+	}
+}
+
+// ValidateSchema returns a transform that validates front matter against a
+// compiled JSON Schema (see package schema).
+//
+// Every violation is reported with its JSON path, not only the first. In
+// SchemaModeFail, the transform returns a wrapped *schema.ValidationError; in
+// SchemaModeWarn, violations are logged at warn level and the document is
+// processed normally.
+//
+// Register it after transforms that add metadata, so the final front matter is validated.
+//
+// Example:
+//
+//	s, err := schema.Compile(schemaJSON)
+//	if err != nil {
+//	    return err
+//	}
+//	transform := ValidateSchema(s, SchemaModeFail)
+func ValidateSchema(s *schema.Schema, mode SchemaMode) fm.Transform {
+	return func(ctx context.Context, frontmatter fm.FrontMatter) error {
+		logger := cctx.Logger(ctx)
+
+		violations := s.Validate(map[string]any(frontmatter))
+		if len(violations) == 0 {
+			logger.Debug("validate schema: front matter is valid")
+			return nil
+		}
+
+		path := "document"
+		if fi, ok := cctx.FileInfoFrom(ctx); ok && fi.Path != "" {
+			path = fi.Path
+		}
+
+		if mode == SchemaModeWarn {
+			for _, v := range violations {
+				logger.Warn("front matter schema violation",
+					slog.String("file", path),
+					slog.String("path", v.Path),
+					slog.String("message", v.Message))
+			}
+			return nil
+		}
+
+		logger.Error("front matter failed schema validation",
+			slog.String("file", path),
+			slog.Int("violations", len(violations)))
+		return fmt.Errorf("ValidateSchema: %w", &schema.ValidationError{Violations: violations})
+	}
+}
@@ -0,0 +1,79 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/frontmatter/schema"
+)
+
+var ownerSchema = schema.MustCompile([]byte(`{
+	"type": "object",
+	"required": ["owner", "status"],
+	"properties": {"status": {"enum": ["draft", "published"]}}
+}`))
+
+func TestValidateSchema_Valid(t *testing.T) {
+	frontmatter := fm.FrontMatter{"owner": "docs", "status": "draft"}
+	if err := ValidateSchema(ownerSchema, SchemaModeFail)(context.Background(), frontmatter); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestValidateSchema_FailMode(t *testing.T) {
+	ctx := cctx.WithFileInfo(context.Background(), cctx.FileInfo{Path: "docs/a.md", Title: "A"})
+
+	err := ValidateSchema(ownerSchema, SchemaModeFail)(ctx, fm.FrontMatter{"status": "wip"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var verr *schema.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %T", err)
+	}
+	if len(verr.Violations) != 2 {
+		t.Errorf("expected 2 violations, got %v", verr.Violations)
+	}
+	for _, want := range []string{"$: missing property 'owner'", "$.status"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %v", want, err)
+		}
+	}
+}
+
+func TestValidateSchema_WarnMode(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	ctx := cctx.WithLogger(context.Background(), logger)
+
+	err := ValidateSchema(ownerSchema, SchemaModeWarn)(ctx, fm.FrontMatter{"status": "wip"})
+	if err != nil {
+		t.Fatalf("expected no error in warn mode, got %v", err)
+	}
+
+	logs := buf.String()
+	if strings.Count(logs, "level=WARN") != 2 {
+		t.Errorf("expected 2 warnings, got:\n%s", logs)
+	}
+	if !strings.Contains(logs, "path=$.status") {
+		t.Errorf("expected warning for $.status, got:\n%s", logs)
+	}
+}
+
+func TestParseSchemaMode(t *testing.T) {
+	for _, s := range []string{"fail", "warn"} {
+		if mode, err := ParseSchemaMode(s); err != nil || string(mode) != s {
+			t.Errorf("ParseSchemaMode(%q) = %q, %v", s, mode, err)
+		}
+	}
+	if _, err := ParseSchemaMode("ignore"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
@@ -11,6 +11,20 @@
 //
 // Common fields include: title, author, date, tags, description, etc.
 //
+// # Field Paths
+//
+// GetPath, SetPath and DeletePath address nested values with field paths.
+// Keys are separated by dots and list elements are selected with brackets:
+//
+//	fm.GetPath("metadata.version")
+//	fm.SetPath("authors[0].name", "Ada")
+//	fm.DeletePath("tags[2]")
+//
+// A backslash escapes the next character, so "file\.name" addresses the
+// single key "file.name"; EscapePathKey builds such segments. SetPath creates
+// missing maps and lists along the way. Every builtin that takes a field
+// (header fields, InjectFilePath, EnforceFields) uses the same syntax.
+//
 // # Transform System
 //
 // Transforms modify frontmatter during processing:
@@ -36,16 +50,37 @@
 //     - Preserves existing values
 //     - Useful for defaults
 //
-//  3. RequireSummary: Validates that summary field exists
-//     - Returns error if missing
-//     - Useful for enforcing metadata requirements
+//  3. EnforceFields: Declares field rules by field path
+//     - Required fields and expected types
+//     - Defaults and coercions (split, rfc3339, lowercase)
+//     - Supersedes the deprecated RequireSummary
+//
+//  4. InheritDirectoryMeta: Merges _meta.yaml files from ancestor directories
+//     - Nearest directory wins
+//     - Preserves existing values
+//
+//  5. GitMetadata: Adds blob hash and last commit details from git
+//     - Cached per file
+//     - Optional first-commit date
+//
+//  6. ValidateSchema: Validates against a JSON Schema (see the schema subpackage)
+//     - Reports every violation with its JSON path
+//     - Fail or warn mode
 //
 // # FrontMatterView
 //
 // FrontMatterView provides read-only access to frontmatter:
 //   - Prevents external modification
 //   - Used in header generation
-//   - Deep copies data for safety
+//   - Copy-on-read: only values that are read are deep-copied
+//   - GetPath reads nested values with the same field path syntax
+//
+// # Deep Copies
+//
+// Clone and FrontMatterView copy values structurally and keep their Go types:
+// ints stay ints (so "version: 2" is not rendered as "2.0"), and time.Time
+// values stay time.Time. Nested map[interface{}]interface{} values produced by
+// yaml.v2 are converted to map[string]any rather than dropped.
 //
 // # Usage Example
 //
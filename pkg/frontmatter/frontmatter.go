@@ -1,8 +1,9 @@
 package frontmatter
 
 import (
-	"encoding/json"
 	"fmt"
+	"reflect"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -14,16 +15,18 @@ type FrontMatter map[string]any
 // EmptyFrontMatter creates a new empty FrontMatter map.
 func EmptyFrontMatter() FrontMatter { return make(FrontMatter) }
 
-// Clone performs a deep clone of the FrontMatter using JSON serialization.
+// Clone performs a deep clone of the FrontMatter.
+//
+// The copy is structural and keeps Go types: ints stay ints and time.Time
+// values stay time.Time. Nested map[any]any values, as produced by yaml.v2,
+// are converted to map[string]any. See the package documentation for details.
 func (fm FrontMatter) Clone() FrontMatter {
 	if fm == nil {
 		return EmptyFrontMatter()
 	}
-	b, _ := json.Marshal(fm)
-	var out FrontMatter
-	_ = json.Unmarshal(b, &out)
-	if out == nil {
-		out = EmptyFrontMatter()
+	out := make(FrontMatter, len(fm))
+	for k, v := range fm {
+		out[k] = DeepCopy(v)
 	}
 	return out
 }
@@ -37,6 +40,10 @@ type FrontMatterView interface {
 	// The second return value indicates whether the key exists.
 	Get(key string) (any, bool)
 
+	// GetPath retrieves a value by field path (e.g. "authors[0].name"),
+	// returning a deep copy. See FrontMatter.GetPath for the path syntax.
+	GetPath(path string) (any, bool)
+
 	// Keys returns a slice of all frontmatter keys.
 	Keys() []string
 
@@ -51,7 +58,12 @@ type roFrontMatter struct {
 
 func (ro roFrontMatter) Get(key string) (any, bool) {
 	v, ok := ro.m[key]
-	return deepCopyJSON(v), ok
+	return DeepCopy(v), ok
+}
+
+func (ro roFrontMatter) GetPath(path string) (any, bool) {
+	v, ok := ro.m.GetPath(path)
+	return DeepCopy(v), ok
 }
 
 func (ro roFrontMatter) Keys() []string {
@@ -66,17 +78,90 @@ func (ro roFrontMatter) AsMap() map[string]any {
 	return ro.m.Clone()
 }
 
-// View returns a read-only interface over a deep-copied snapshot of the frontmatter.
+// View returns a read-only interface over the frontmatter.
+//
+// The view is copy-on-read: creating it is free, and each Get or AsMap call
+// deep-copies only the values it returns. The view reads the live map, so it
+// reflects later changes made through the FrontMatter itself.
 func (fm FrontMatter) View() FrontMatterView {
-	return roFrontMatter{m: fm.Clone()}
+	return roFrontMatter{m: fm}
 }
 
-// deepCopyJSON is a helper that deep copies a value using JSON serialization.
-func deepCopyJSON(v any) any {
-	b, _ := json.Marshal(v)
-	var out any
-	_ = json.Unmarshal(b, &out)
-	return out
+// DeepCopy returns a structural deep copy of a frontmatter value.
+//
+// Maps and slices are copied recursively; scalars, time.Time and other values
+// without shared mutable state are returned as-is. Maps with non-string keys are
+// converted to map[string]any, formatting keys with fmt.Sprint.
+func DeepCopy(v any) any {
+	switch x := v.(type) {
+	case nil, string, bool,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64, time.Time:
+		return x
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, item := range x {
+			out[k] = DeepCopy(item)
+		}
+		return out
+	case FrontMatter:
+		return x.Clone()
+	case map[any]any:
+		out := make(map[string]any, len(x))
+		for k, item := range x {
+			out[fmt.Sprint(k)] = DeepCopy(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, item := range x {
+			out[i] = DeepCopy(item)
+		}
+		return out
+	}
+
+	// Typed slices and maps such as []string or map[string]int
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			setCopy(out.Index(i), rv.Index(i))
+		}
+		return out.Interface()
+	case reflect.Map:
+		if rv.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			setCopy(elem, iter.Value())
+			out.SetMapIndex(iter.Key(), elem)
+		}
+		return out.Interface()
+	default:
+		return v
+	}
+}
+
+// setCopy stores a deep copy of src in dst, falling back to the original
+// value if the copy's type is not assignable to dst.
+func setCopy(dst, src reflect.Value) {
+	if !src.IsValid() || (src.Kind() == reflect.Interface && src.IsNil()) {
+		return
+	}
+	c := reflect.ValueOf(DeepCopy(src.Interface()))
+	if c.IsValid() && c.Type().AssignableTo(dst.Type()) {
+		dst.Set(c)
+		return
+	}
+	dst.Set(src)
 }
 
 // Serialize converts frontmatter to a YAML block string suitable for markdown.
@@ -3,6 +3,7 @@ package frontmatter
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestClone(t *testing.T) {
@@ -15,13 +16,16 @@ func TestClone(t *testing.T) {
 
 	cloned := original.Clone()
 
-	// Verify basic structure (note: deepCopyJSON via JSON converts int to float64)
+	// Verify basic structure; Go types are preserved
 	if cloned["title"] != "Test" {
 		t.Errorf("title not cloned correctly")
 	}
-	if cloned["count"] != float64(42) { // JSON converts int to float64
+	if cloned["count"] != 42 {
 		t.Errorf("count not cloned correctly, got %v (%T)", cloned["count"], cloned["count"])
 	}
+	if !reflect.DeepEqual(cloned["tags"], []string{"a", "b"}) {
+		t.Errorf("tags not cloned correctly, got %v (%T)", cloned["tags"], cloned["tags"])
+	}
 
 	// Verify it's a deep copy (modifying clone doesn't affect original)
 	cloned["title"] = "Modified"
@@ -67,7 +71,7 @@ func TestGet(t *testing.T) {
 		wantOk  bool
 	}{
 		{"existing string", "string", "value", true},
-		{"existing number", "number", float64(42), true}, // JSON converts int to float64
+		{"existing number", "number", 42, true},
 		{"existing bool", "bool", true, true},
 		{"missing key", "missing", nil, false},
 	}
@@ -130,11 +134,11 @@ func TestAsMap(t *testing.T) {
 	view := fm.View()
 	m := view.AsMap()
 
-	// Verify basic structure (JSON serialization converts int to float64)
+	// Verify basic structure
 	if m["key1"] != "value1" {
 		t.Error("AsMap did not preserve string value")
 	}
-	if m["key2"] != float64(42) { // JSON converts int to float64
+	if m["key2"] != 42 {
 		t.Errorf("AsMap did not preserve number value, got %v (%T)", m["key2"], m["key2"])
 	}
 
@@ -274,61 +278,95 @@ func TestSerialize_WithSpecialCharacters(t *testing.T) {
 	}
 }
 
-func TestDeepCopyJSON(t *testing.T) {
+func TestDeepCopy(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
 	original := map[string]any{
-		"string": "value",
-		"number": 42,
+		"string":  "value",
+		"number":  42,
+		"version": 2.0,
+		"date":    date,
 		"nested": map[string]any{
 			"key": "value",
 		},
-		"array": []any{1, 2, 3},
+		"array":  []any{1, 2, 3},
+		"tags":   []string{"a", "b"},
+		"counts": map[string]int{"a": 1},
 	}
 
-	copied := deepCopyJSON(original)
+	copied, ok := DeepCopy(original).(map[string]any)
+	if !ok {
+		t.Fatalf("expected map[string]any copy")
+	}
 
-	// Verify it's not nil
-	if copied == nil {
-		t.Fatal("expected non-nil copy")
+	// Types are preserved
+	if copied["number"] != 42 {
+		t.Errorf("expected int 42, got %v (%T)", copied["number"], copied["number"])
+	}
+	if copied["version"] != 2.0 {
+		t.Errorf("expected float64 2.0, got %v (%T)", copied["version"], copied["version"])
+	}
+	if copied["date"] != date {
+		t.Errorf("expected time.Time, got %v (%T)", copied["date"], copied["date"])
+	}
+	if !reflect.DeepEqual(copied, original) {
+		t.Errorf("copy differs from original:\n%v\n%v", copied, original)
 	}
 
-	// Type assert to map
-	copiedMap, ok := copied.(map[string]any)
-	if !ok {
-		t.Fatalf("expected copied to be map[string]any, got %T", copied)
+	// Nested structures are copied, not shared
+	copied["nested"].(map[string]any)["key"] = "modified"
+	copied["array"].([]any)[0] = 99
+	copied["tags"].([]string)[0] = "z"
+	copied["counts"].(map[string]int)["a"] = 2
+	if original["nested"].(map[string]any)["key"] != "value" ||
+		original["array"].([]any)[0] != 1 ||
+		original["tags"].([]string)[0] != "a" ||
+		original["counts"].(map[string]int)["a"] != 1 {
+		t.Error("modifying nested copy affected original")
 	}
+}
 
-	// Verify it's a deep copy
-	copiedMap["string"] = "modified"
-	if original["string"] == "modified" {
-		t.Error("modifying copy affected original")
+func TestDeepCopy_ConvertsInterfaceKeyedMaps(t *testing.T) {
+	// yaml.v2 decodes nested mappings as map[interface{}]interface{}
+	original := map[any]any{
+		"name":  "Ada",
+		1:       "one",
+		"inner": map[any]any{"key": []any{map[any]any{"x": 1}}},
 	}
 
-	// Verify nested structures are also copied
-	if nested, ok := copiedMap["nested"].(map[string]any); ok {
-		nested["key"] = "modified"
-		if origNested, ok := original["nested"].(map[string]any); ok {
-			if origNested["key"] == "modified" {
-				t.Error("modifying nested copy affected original")
-			}
-		}
+	copied, ok := DeepCopy(original).(map[string]any)
+	if !ok {
+		t.Fatalf("expected map[string]any, got %T", DeepCopy(original))
+	}
+	if copied["name"] != "Ada" || copied["1"] != "one" {
+		t.Errorf("unexpected keys: %v", copied)
+	}
+	inner := copied["inner"].(map[string]any)
+	item := inner["key"].([]any)[0].(map[string]any)
+	if item["x"] != 1 {
+		t.Errorf("expected nested map to be converted, got %v", item)
 	}
 }
 
-func TestDeepCopyJSON_Nil(t *testing.T) {
-	copied := deepCopyJSON(nil)
-	if copied != nil {
+func TestDeepCopy_Nil(t *testing.T) {
+	if copied := DeepCopy(nil); copied != nil {
 		t.Error("expected nil copy of nil input")
 	}
 }
 
-func TestDeepCopyJSON_Empty(t *testing.T) {
-	original := map[string]any{}
-	copied := deepCopyJSON(original)
+func TestView_CopyOnRead(t *testing.T) {
+	fm := FrontMatter{"title": "Before", "tags": []any{"a"}}
+	view := fm.View()
 
-	// deepCopyJSON may return nil for empty maps after JSON round-trip
-	if copied != nil {
-		if copiedMap, ok := copied.(map[string]any); ok && len(copiedMap) != 0 {
-			t.Errorf("expected empty or nil copy, got %d keys", len(copiedMap))
-		}
+	// The view reads the live map
+	fm["title"] = "After"
+	if v, _ := view.Get("title"); v != "After" {
+		t.Errorf("expected view to reflect later changes, got %v", v)
+	}
+
+	// Values returned by the view are copies
+	tags, _ := view.Get("tags")
+	tags.([]any)[0] = "modified"
+	if fm["tags"].([]any)[0] != "a" {
+		t.Error("modifying a value from Get affected the original")
 	}
 }
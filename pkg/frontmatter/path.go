@@ -0,0 +1,315 @@
+package frontmatter
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Field paths address values inside nested frontmatter.
+//
+// A path is a sequence of map keys separated by dots, each optionally followed
+// by one or more list indexes in brackets:
+//
+//	title
+//	metadata.version
+//	authors[0].name
+//	matrix[1][2]
+//
+// A backslash escapes the next character, so keys containing dots, brackets or
+// backslashes can still be addressed: "file\.name" is the single key "file.name".
+
+// pathSegment is one step of a parsed path: a map key or a list index.
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func (s pathSegment) String() string {
+	if s.isIndex {
+		return fmt.Sprintf("[%d]", s.index)
+	}
+	return s.key
+}
+
+// parsePath splits a field path into segments.
+func parsePath(path string) ([]pathSegment, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	var segs []pathSegment
+	var key strings.Builder
+	hasKey := false // whether the current key segment has started
+	expectKey := true
+
+	flushKey := func(i int) error {
+		if !hasKey {
+			return fmt.Errorf("invalid path %q: empty key at offset %d", path, i)
+		}
+		segs = append(segs, pathSegment{key: key.String()})
+		key.Reset()
+		hasKey = false
+		return nil
+	}
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '\\':
+			if i+1 >= len(path) {
+				return nil, fmt.Errorf("invalid path %q: trailing backslash", path)
+			}
+			i++
+			key.WriteByte(path[i])
+			hasKey, expectKey = true, false
+
+		case c == '.':
+			if expectKey {
+				return nil, fmt.Errorf("invalid path %q: empty key at offset %d", path, i)
+			}
+			if hasKey {
+				if err := flushKey(i); err != nil {
+					return nil, err
+				}
+			}
+			expectKey = true
+
+		case c == '[':
+			if hasKey {
+				if err := flushKey(i); err != nil {
+					return nil, err
+				}
+			} else if expectKey {
+				return nil, fmt.Errorf("invalid path %q: index without key at offset %d", path, i)
+			}
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed '['", path)
+			}
+			n, err := strconv.Atoi(path[i+1 : i+end])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid path %q: index %q is not a non-negative integer", path, path[i+1:i+end])
+			}
+			segs = append(segs, pathSegment{index: n, isIndex: true})
+			i += end
+			expectKey = false
+
+		case c == ']':
+			return nil, fmt.Errorf("invalid path %q: unexpected ']' at offset %d", path, i)
+
+		default:
+			if !expectKey && !hasKey {
+				return nil, fmt.Errorf("invalid path %q: expected '.' or '[' at offset %d", path, i)
+			}
+			key.WriteByte(c)
+			hasKey, expectKey = true, false
+		}
+	}
+
+	if expectKey {
+		return nil, fmt.Errorf("invalid path %q: ends with '.'", path)
+	}
+	if hasKey {
+		segs = append(segs, pathSegment{key: key.String()})
+	}
+	return segs, nil
+}
+
+// ValidatePath reports whether a field path is well-formed.
+func ValidatePath(path string) error {
+	_, err := parsePath(path)
+	return err
+}
+
+// EscapePathKey escapes a literal key for use as one segment of a field path.
+func EscapePathKey(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		switch key[i] {
+		case '.', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(key[i])
+	}
+	return b.String()
+}
+
+// GetPath returns the value at a field path, such as "authors[0].name".
+// The returned value is not a copy. The second return value reports whether
+// the path exists; it is false for malformed paths.
+func (fm FrontMatter) GetPath(path string) (any, bool) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, false
+	}
+	return getPath(map[string]any(fm), segs)
+}
+
+// SetPath sets the value at a field path, creating intermediate maps and lists
+// as needed. An index equal to the length of an existing list appends to it.
+//
+// Returns an error if the path is malformed, an intermediate value has the
+// wrong type (e.g. indexing into a string), or an index is out of range.
+func (fm FrontMatter) SetPath(path string, value any) error {
+	if fm == nil {
+		return fmt.Errorf("cannot set %q on nil frontmatter", path)
+	}
+	segs, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	_, err = setPath(map[string]any(fm), segs, value, path)
+	return err
+}
+
+// DeletePath removes the value at a field path. Map keys are deleted; list
+// elements are removed and later elements shift down.
+// Reports whether a value was removed; it is false for missing or malformed paths.
+func (fm FrontMatter) DeletePath(path string) bool {
+	segs, err := parsePath(path)
+	if err != nil {
+		return false
+	}
+
+	parent, ok := any(map[string]any(fm)), true
+	if len(segs) > 1 {
+		parent, ok = getPath(map[string]any(fm), segs[:len(segs)-1])
+		if !ok {
+			return false
+		}
+	}
+
+	last := segs[len(segs)-1]
+	switch c := parent.(type) {
+	case map[string]any:
+		if _, exists := c[last.key]; !last.isIndex && exists {
+			delete(c, last.key)
+			return true
+		}
+	case map[any]any:
+		if _, exists := c[last.key]; !last.isIndex && exists {
+			delete(c, last.key)
+			return true
+		}
+	case []any:
+		if !last.isIndex || last.index >= len(c) {
+			return false
+		}
+		// Shrinking a list changes its length, so the parent must be updated
+		shrunk := append(c[:last.index:last.index], c[last.index+1:]...)
+		_, err := setPath(map[string]any(fm), segs[:len(segs)-1], shrunk, path)
+		return err == nil
+	}
+	return false
+}
+
+// getPath walks segments from a root value.
+func getPath(cur any, segs []pathSegment) (any, bool) {
+	for _, seg := range segs {
+		next, ok := child(cur, seg)
+		if !ok {
+			return nil, false
+		}
+		cur = next
+	}
+	return cur, true
+}
+
+// child returns the element of a container addressed by one segment.
+func child(cur any, seg pathSegment) (any, bool) {
+	if seg.isIndex {
+		switch c := cur.(type) {
+		case []any:
+			if seg.index < len(c) {
+				return c[seg.index], true
+			}
+			return nil, false
+		}
+		rv := reflect.ValueOf(cur)
+		if rv.Kind() == reflect.Slice && seg.index < rv.Len() {
+			return rv.Index(seg.index).Interface(), true
+		}
+		return nil, false
+	}
+
+	switch c := cur.(type) {
+	case map[string]any:
+		v, ok := c[seg.key]
+		return v, ok
+	case FrontMatter:
+		v, ok := c[seg.key]
+		return v, ok
+	case map[any]any:
+		v, ok := c[seg.key]
+		return v, ok
+	}
+	return nil, false
+}
+
+// setPath sets value at segs below cur and returns the (possibly new) container,
+// which the caller must store back into its parent.
+func setPath(cur any, segs []pathSegment, value any, path string) (any, error) {
+	if len(segs) == 0 {
+		return value, nil
+	}
+	seg, rest := segs[0], segs[1:]
+
+	if seg.isIndex {
+		if cur == nil {
+			cur = []any{}
+		}
+		list, ok := cur.([]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot set %q: cannot index into %T", path, cur)
+		}
+		switch {
+		case seg.index < len(list):
+			v, err := setPath(list[seg.index], rest, value, path)
+			if err != nil {
+				return nil, err
+			}
+			list[seg.index] = v
+		case seg.index == len(list):
+			v, err := setPath(nil, rest, value, path)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		default:
+			return nil, fmt.Errorf("cannot set %q: index %d out of range (length %d)", path, seg.index, len(list))
+		}
+		return list, nil
+	}
+
+	if cur == nil {
+		cur = map[string]any{}
+	}
+	switch c := cur.(type) {
+	case map[string]any:
+		v, err := setPath(c[seg.key], rest, value, path)
+		if err != nil {
+			return nil, err
+		}
+		c[seg.key] = v
+		return c, nil
+	case FrontMatter:
+		v, err := setPath(c[seg.key], rest, value, path)
+		if err != nil {
+			return nil, err
+		}
+		c[seg.key] = v
+		return c, nil
+	case map[any]any:
+		v, err := setPath(c[seg.key], rest, value, path)
+		if err != nil {
+			return nil, err
+		}
+		c[seg.key] = v
+		return c, nil
+	default:
+		return nil, fmt.Errorf("cannot set %q: %q is a %T, not a map", path, seg.key, cur)
+	}
+}
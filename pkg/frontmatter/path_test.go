@@ -0,0 +1,176 @@
+package frontmatter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []pathSegment
+	}{
+		{"title", []pathSegment{{key: "title"}}},
+		{"metadata.version", []pathSegment{{key: "metadata"}, {key: "version"}}},
+		{"authors[0].name", []pathSegment{{key: "authors"}, {index: 0, isIndex: true}, {key: "name"}}},
+		{"matrix[1][2]", []pathSegment{{key: "matrix"}, {index: 1, isIndex: true}, {index: 2, isIndex: true}}},
+		{`file\.name`, []pathSegment{{key: "file.name"}}},
+		{`a\[0\].b\\c`, []pathSegment{{key: "a[0]"}, {key: `b\c`}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := parsePath(tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParsePath_Invalid(t *testing.T) {
+	for _, path := range []string{"", ".a", "a.", "a..b", "[0]", "a[", "a[x]", "a[-1]", "a]", "a[0]b", `a\`} {
+		if _, err := parsePath(path); err == nil {
+			t.Errorf("expected error for %q", path)
+		}
+	}
+}
+
+func TestEscapePathKey(t *testing.T) {
+	key := `weird.key[0]\`
+	segs, err := parsePath(EscapePathKey(key) + ".x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if segs[0].key != key || segs[1].key != "x" {
+		t.Errorf("escaped key did not round-trip, got %v", segs)
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	fm := FrontMatter{
+		"title":     "Doc",
+		"metadata":  map[any]any{"version": 2},
+		"authors":   []any{map[string]any{"name": "Ada"}, map[any]any{"name": "Grace"}},
+		"tags":      []string{"a", "b"},
+		"file.name": "x.md",
+	}
+
+	tests := []struct {
+		path string
+		want any
+		ok   bool
+	}{
+		{"title", "Doc", true},
+		{"metadata.version", 2, true},
+		{"authors[0].name", "Ada", true},
+		{"authors[1].name", "Grace", true},
+		{"tags[1]", "b", true},
+		{`file\.name`, "x.md", true},
+		{"file.name", nil, false},
+		{"authors[2].name", nil, false},
+		{"title.length", nil, false},
+		{"metadata[0]", nil, false},
+		{"bad..path", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := fm.GetPath(tt.path)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	fm := FrontMatter{
+		"metadata": map[any]any{"version": 1},
+		"authors":  []any{map[string]any{"name": "Ada"}},
+	}
+
+	for path, value := range map[string]any{
+		"metadata.version": 2,
+		"owner.team.name":  "docs",
+		"authors[0].name":  "Grace",
+		"authors[1].name":  "Linus",
+		"aliases[0]":       "first",
+		`weird\.key`:       true,
+	} {
+		if err := fm.SetPath(path, value); err != nil {
+			t.Fatalf("SetPath(%q): unexpected error: %v", path, err)
+		}
+		if got, ok := fm.GetPath(path); !ok || !reflect.DeepEqual(got, value) {
+			t.Errorf("GetPath(%q) after set: expected %v, got %v", path, value, got)
+		}
+	}
+
+	if _, ok := fm["weird.key"]; !ok {
+		t.Error("expected escaped key to be stored literally")
+	}
+	if n := len(fm["authors"].([]any)); n != 2 {
+		t.Errorf("expected appended author, got %d authors", n)
+	}
+}
+
+func TestSetPath_Errors(t *testing.T) {
+	fm := FrontMatter{"title": "Doc", "tags": []any{"a"}}
+
+	for _, path := range []string{"title.sub", "tags[5]", "title[0]", "a..b"} {
+		if err := fm.SetPath(path, 1); err == nil {
+			t.Errorf("expected error for %q", path)
+		}
+	}
+	if err := FrontMatter(nil).SetPath("a", 1); err == nil {
+		t.Error("expected error for nil frontmatter")
+	}
+}
+
+func TestDeletePath(t *testing.T) {
+	fm := FrontMatter{
+		"title":    "Doc",
+		"metadata": map[any]any{"version": 1, "owner": "docs"},
+		"authors":  []any{"Ada", "Grace", "Linus"},
+	}
+	authors := fm["authors"].([]any)
+
+	if !fm.DeletePath("metadata.version") {
+		t.Error("expected nested key to be deleted")
+	}
+	if !fm.DeletePath("authors[1]") {
+		t.Error("expected list element to be deleted")
+	}
+	if fm.DeletePath("missing.key") || fm.DeletePath("authors[5]") || fm.DeletePath("title[0]") {
+		t.Error("expected missing paths to report false")
+	}
+
+	if !reflect.DeepEqual(fm["metadata"], map[any]any{"owner": "docs"}) {
+		t.Errorf("unexpected metadata: %v", fm["metadata"])
+	}
+	if !reflect.DeepEqual(fm["authors"], []any{"Ada", "Linus"}) {
+		t.Errorf("unexpected authors: %v", fm["authors"])
+	}
+	if !reflect.DeepEqual(authors, []any{"Ada", "Grace", "Linus"}) {
+		t.Errorf("deleting an element modified the original list: %v", authors)
+	}
+}
+
+func TestView_GetPath(t *testing.T) {
+	fm := FrontMatter{"authors": []any{map[string]any{"name": "Ada", "tags": []any{"x"}}}}
+	view := fm.View()
+
+	name, ok := view.GetPath("authors[0].name")
+	if !ok || name != "Ada" {
+		t.Errorf("expected Ada, got %v", name)
+	}
+
+	tags, _ := view.GetPath("authors[0].tags")
+	tags.([]any)[0] = "mutated"
+	if got, _ := fm.GetPath("authors[0].tags[0]"); got != "x" {
+		t.Errorf("mutating view result changed the frontmatter: %v", got)
+	}
+}
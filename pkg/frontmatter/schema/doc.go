@@ -0,0 +1,43 @@
+// Package schema validates front matter against a JSON Schema.
+//
+// Validation is done by github.com/santhosh-tekuri/jsonschema, which passes
+// the official JSON-Schema-Test-Suite. This package adapts it to front
+// matter: schemas may be written in YAML, decoded YAML values are accepted
+// as instances, and errors are flattened into a list of violations, each
+// with the JSON path of the offending value. Schemas are compiled once and
+// can then validate any number of documents.
+//
+// # Drafts and Formats
+//
+// Schemas use draft 2020-12 unless they declare another draft with $schema.
+// The format keyword is asserted rather than treated as an annotation, so
+// "format": "date" rejects "2024-13-45". References may point into the
+// schema itself ("#/$defs/tag"); references to other documents fail to
+// compile.
+//
+// # Front Matter Values
+//
+// YAML front matter does not map one-to-one onto JSON. Before validation,
+// values are normalized: maps with non-string keys become objects, every
+// numeric type is a number (and an integer if it has no fractional part), and
+// time.Time values are strings that satisfy the date and date-time formats.
+//
+// # Usage Example
+//
+//	s, err := schema.Compile([]byte(`{
+//	    "type": "object",
+//	    "required": ["owner", "status"],
+//	    "properties": {
+//	        "owner":  {"type": "string"},
+//	        "status": {"enum": ["draft", "published"]},
+//	        "date":   {"type": "string", "format": "date"}
+//	    }
+//	}`))
+//	if err != nil {
+//	    return err
+//	}
+//
+//	for _, v := range s.Validate(frontmatter) {
+//	    fmt.Printf("%s: %s\n", v.Path, v.Message)
+//	}
+package schema
@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaURL is the location the schema document is registered under. Its
+// scheme has no loader, so references to other documents fail to compile.
+const schemaURL = "chunky:///frontmatter.schema.json"
+
+// printer renders validator messages.
+var printer = message.NewPrinter(language.English)
+
+// Schema is a compiled JSON Schema.
+// A Schema is immutable and safe for concurrent use.
+type Schema struct {
+	compiled *jsonschema.Schema
+}
+
+// Compile parses and compiles a JSON Schema document.
+// YAML schemas are accepted too, since JSON is a subset of YAML.
+//
+// Returns an error if the document is malformed, is not a valid schema, or
+// contains a $ref that cannot be resolved.
+func Compile(data []byte) (*Schema, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return New(doc)
+}
+
+// New compiles a JSON Schema that has already been decoded, for example a
+// map[string]any built in code.
+func New(doc any) (*Schema, error) {
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	c.AssertFormat()
+	if err := c.AddResource(schemaURL, normalize(doc)); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	compiled, err := c.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return &Schema{compiled: compiled}, nil
+}
+
+// MustCompile is like Compile but panics if the schema cannot be compiled.
+// It simplifies initialization of global variables holding schemas.
+func MustCompile(data []byte) *Schema {
+	s, err := Compile(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Validate checks value against the schema and returns every violation found,
+// ordered by path. Returns nil if the value is valid.
+func (s *Schema) Validate(value any) []Violation {
+	value = normalize(value)
+	err := s.compiled.Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return []Violation{{Path: "$", Message: err.Error()}}
+	}
+	var out []Violation
+	collect(verr, value, &out)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// Check is like Validate but returns a *ValidationError if there are violations.
+func (s *Schema) Check(value any) error {
+	if violations := s.Validate(value); len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// collect flattens the validator's error tree into violations.
+//
+// Errors that only group their causes are skipped in favor of the causes.
+// anyOf and oneOf failures are reported as one violation: their causes
+// explain why each alternative failed, which is noise to the reader.
+func collect(err *jsonschema.ValidationError, root any, out *[]Violation) {
+	switch err.ErrorKind.(type) {
+	case *kind.AnyOf, *kind.OneOf:
+	default:
+		if len(err.Causes) > 0 {
+			for _, cause := range err.Causes {
+				collect(cause, root, out)
+			}
+			return
+		}
+	}
+	*out = append(*out, Violation{
+		Path:    instancePath(root, err.InstanceLocation),
+		Message: err.ErrorKind.LocalizedString(printer),
+	})
+}
@@ -0,0 +1,206 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const contractSchema = `
+type: object
+required: [owner, status]
+additionalProperties: false
+properties:
+  owner: {type: string, minLength: 1}
+  status: {enum: [draft, review, published]}
+  date: {type: string, format: date}
+  weight: {type: integer, minimum: 0, maximum: 10}
+  tags:
+    type: array
+    uniqueItems: true
+    items: {$ref: "#/$defs/tag"}
+  links:
+    type: object
+    additionalProperties: {type: string, format: uri}
+$defs:
+  tag: {type: string, pattern: "^[a-z-]+$"}
+`
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{"malformed", `{type: [`, "failed to parse schema"},
+		{"not an object", `42`, "got number, want boolean or object"},
+		{"unknown type", `{type: strnig}`, "value must be one of 'array'"},
+		{"bad pattern", `{pattern: "("}`, "is not valid regex"},
+		{"remote ref", `{$ref: "https://example.com/s.json"}`, `failing loading "https://example.com/s.json"`},
+		{"dangling ref", `{$ref: "#/$defs/missing"}`, "#/$defs/missing\" not found"},
+		{"bad count", `{minLength: -1}`, "minimum: got -1, want 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile([]byte(tt.schema))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidate_Valid(t *testing.T) {
+	s := MustCompile([]byte(contractSchema))
+
+	// Shapes produced by YAML front matter parsing: int values, nested
+	// map[interface{}]interface{} and time.Time dates
+	doc := map[string]any{
+		"owner":  "docs-team",
+		"status": "published",
+		"date":   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		"weight": 3,
+		"tags":   []any{"api", "how-to"},
+		"links":  map[any]any{"repo": "https://github.com/wyvernzora/chunky"},
+	}
+
+	if v := s.Validate(doc); len(v) != 0 {
+		t.Errorf("expected no violations, got %v", v)
+	}
+}
+
+func TestValidate_ReportsEveryViolation(t *testing.T) {
+	s := MustCompile([]byte(contractSchema))
+
+	doc := map[string]any{
+		"status": "wip",
+		"date":   "2024-13-45",
+		"weight": 2.5,
+		"tags":   []any{"api", "API", "api"},
+		"links":  map[string]any{"repo": "not a url"},
+		"extra":  true,
+	}
+
+	want := []string{
+		"$: missing property 'owner'",
+		"$: additional properties 'extra' not allowed",
+		`$.date: '2024-13-45' is not valid date: parsing time "2024-13-45": month out of range`,
+		"$.links.repo: 'not a url' is not valid uri: relative url",
+		"$.status: value must be one of 'draft', 'review', 'published'",
+		"$.tags: items at 0 and 2 are equal",
+		"$.tags[1]: 'API' does not match pattern '^[a-z-]+$'",
+		"$.weight: got number, want integer",
+	}
+
+	got := s.Validate(doc)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d violations, got %d: %v", len(want), len(got), got)
+	}
+	for i, v := range got {
+		if v.String() != want[i] {
+			t.Errorf("violation %d: expected %q, got %q", i, want[i], v.String())
+		}
+	}
+}
+
+func TestValidate_Composition(t *testing.T) {
+	s := MustCompile([]byte(`
+properties:
+  id:
+    oneOf:
+      - {type: integer}
+      - {type: string, format: uuid}
+  audience:
+    anyOf:
+      - {const: internal}
+      - {type: array, minItems: 1}
+  title:
+    allOf:
+      - {type: string}
+      - {maxLength: 5}
+    not: {const: Draft}
+`))
+
+	tests := []struct {
+		name string
+		doc  map[string]any
+		want string
+	}{
+		{"oneOf ok", map[string]any{"id": 7}, ""},
+		{"oneOf none", map[string]any{"id": "nope"}, "'oneOf' failed, none matched"},
+		{"anyOf ok", map[string]any{"audience": []any{"ops"}}, ""},
+		{"anyOf none", map[string]any{"audience": "external"}, "'anyOf' failed"},
+		{"allOf", map[string]any{"title": "Too long"}, "maxLength: got 8, want 5"},
+		{"not", map[string]any{"title": "Draft"}, "'not' failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Check(tt.doc)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected ValidationError containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidate_PathEscaping(t *testing.T) {
+	s := MustCompile([]byte(`{additionalProperties: {type: string}}`))
+
+	got := s.Validate(map[string]any{"first name": 1, "it's": 2})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 violations, got %v", got)
+	}
+	if got[0].Path != "$['first name']" || got[1].Path != `$['it\'s']` {
+		t.Errorf("unexpected paths: %q, %q", got[0].Path, got[1].Path)
+	}
+}
+
+func TestNew_FromCode(t *testing.T) {
+	s, err := New(map[string]any{
+		"type":     "object",
+		"required": []string{"owner"},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := s.Check(map[string]any{"owner": "me"}); err != nil {
+		t.Errorf("expected valid document, got %v", err)
+	}
+	if err := s.Check(map[string]any{}); err == nil {
+		t.Error("expected missing owner to fail")
+	}
+}
+
+func TestValidate_Conditionals(t *testing.T) {
+	// Keywords beyond the common subset come with the validator
+	s := MustCompile([]byte(`
+if: {properties: {status: {const: published}}}
+then: {required: [date]}
+dependentRequired:
+  reviewer: [reviewed]
+`))
+
+	got := s.Validate(map[string]any{"status": "published", "reviewer": "kim"})
+	want := []string{
+		"$: properties 'reviewed' required, if 'reviewer' exists",
+		"$: missing property 'date'",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d violations, got %v", len(want), got)
+	}
+	for i, v := range got {
+		if v.String() != want[i] {
+			t.Errorf("violation %d: expected %q, got %q", i, want[i], v.String())
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Violation describes one way a value fails its schema.
+type Violation struct {
+	// Path is the JSON path of the offending value, e.g. "$.tags[2]".
+	Path string
+
+	// Message describes the problem, e.g. `expected string, got integer`.
+	Message string
+}
+
+// String returns the violation as "path: message".
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ValidationError reports every violation found in a document.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error implements error, listing each violation on its own line.
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d schema violation(s):", len(e.Violations))
+	for _, v := range e.Violations {
+		b.WriteString("\n  ")
+		b.WriteString(v.String())
+	}
+	return b.String()
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// childPath appends an object key to a JSON path, quoting keys that are not
+// plain identifiers: $.owner, $['first name'].
+func childPath(path, key string) string {
+	if identifierPattern.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "['" + strings.ReplaceAll(strings.ReplaceAll(key, `\`, `\\`), "'", `\'`) + "']"
+}
+
+// instancePath converts a validator instance location (a list of JSON
+// pointer tokens) into a JSON path, using the value to tell array indexes
+// from object keys: ["tags", "2"] becomes $.tags[2].
+func instancePath(value any, location []string) string {
+	path := "$"
+	for _, token := range location {
+		if arr, ok := value.([]any); ok {
+			path += "[" + token + "]"
+			if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(arr) {
+				value = arr[i]
+			} else {
+				value = nil
+			}
+			continue
+		}
+		path = childPath(path, token)
+		if obj, ok := value.(map[string]any); ok {
+			value = obj[token]
+		} else {
+			value = nil
+		}
+	}
+	return path
+}
+
+// normalize converts decoded YAML or Go values into the JSON data model:
+// map[string]any, []any, float64, string, bool and nil.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case nil, bool, string, float64:
+		return v
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 && v.Location() == time.UTC {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339Nano)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = normalize(item)
+		}
+		return out
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Slice, reflect.Array:
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = normalize(rv.Index(i).Interface())
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = normalize(iter.Value().Interface())
+		}
+		return out
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return normalize(rv.Elem().Interface())
+	default:
+		return fmt.Sprint(value)
+	}
+}
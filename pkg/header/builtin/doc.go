@@ -59,6 +59,19 @@
 //   - Numbers and bools: Rendered using Go's default string representation
 //   - Slices: Rendered as compact arrays, e.g., [item1 item2 item3]
 //
+// # Section Metadata and Deep Links
+//
+// Both generators expose the section metadata of the chunk being rendered
+// under "section_meta", its deep link under "section_url" and its links to
+// other documents under "section_links" (see header.WithChunkInfo). FrontMatterYamlHeader prints them with the
+// frontmatter, and KeyValueHeader fields can select them:
+//
+//	gen := builtin.KeyValueHeader(
+//	    builtin.RequiredField("title", "Title"),
+//	    builtin.OptionalField("section_meta.audience", "Audience"),
+//	    builtin.OptionalField("section_url", "Source"),
+//	)
+//
 // # Examples
 //
 // Simple document metadata:
@@ -17,11 +17,15 @@ import (
 //	author: John Doe
 //	---
 //
+// The chunk's section metadata, deep link and outgoing links, if any, are
+// included under "section_meta", "section_url" and "section_links" (see
+// header.WithChunkInfo).
+//
 // If the frontmatter is empty, returns an empty string.
 func FrontMatterYamlHeader() header.ChunkHeader {
 	return func(ctx context.Context, frontmatter fm.FrontMatterView) (string, error) {
 		// Convert view to map for serialization
-		fmMap := frontmatter.AsMap()
+		fmMap := header.WithChunkInfo(ctx, frontmatter).AsMap()
 		if len(fmMap) == 0 {
 			return "", nil
 		}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	cctx "github.com/wyvernzora/chunky/pkg/context"
 	"github.com/wyvernzora/chunky/pkg/frontmatter"
 )
 
@@ -204,3 +205,20 @@ func TestFrontMatterYamlHeader_ContextPropagation(t *testing.T) {
 		t.Errorf("result missing 'title:': %q", result)
 	}
 }
+
+func TestFrontMatterYamlHeader_SectionMeta(t *testing.T) {
+	gen := FrontMatterYamlHeader()
+	fm := frontmatter.FrontMatter{"title": "Doc"}
+
+	ctx := cctx.WithChunkMetadata(context.Background(), map[string]string{"audience": "admins"})
+	result, err := gen(ctx, fm.View())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result, "section_meta:\n  audience: admins\n") {
+		t.Errorf("expected section metadata in header, got %q", result)
+	}
+	if _, ok := fm["section_meta"]; ok {
+		t.Error("expected frontmatter to be left unchanged")
+	}
+}
@@ -14,7 +14,7 @@ import (
 
 // FieldSpec defines a field to include in the key-value header.
 type FieldSpec struct {
-	Path     string // Frontmatter field path (e.g., "title" or "metadata.version")
+	Path     string // Frontmatter field path (e.g., "title", "metadata.version" or "authors[0].name")
 	Label    string // Display label for the field
 	Required bool   // Whether the field must be present and non-empty
 }
@@ -33,6 +33,10 @@ type keyValOpts struct {
 // must be scalars (string, bool, number) or slices of scalars. Maps and
 // nested structures will return an error.
 //
+// Section metadata, the deep link and the outgoing links of the chunk can be
+// printed with the paths "section_meta.<key>", "section_url" and
+// "section_links" (see header.WithChunkInfo).
+//
 // Required fields must be present and non-empty, or an error is returned.
 // Optional fields are silently skipped if missing or empty.
 //
@@ -59,11 +63,12 @@ func KeyValueHeader(opts ...KeyValueHeaderOption) header.ChunkHeader {
 	}
 	fields := slices.Clone(cfg.fields)
 
-	return func(_ context.Context, fm frontmatter.FrontMatterView) (string, error) {
+	return func(ctx context.Context, fm frontmatter.FrontMatterView) (string, error) {
 		var b strings.Builder
+		fm = header.WithChunkInfo(ctx, fm)
 
 		for _, f := range fields {
-			val, ok := fm.Get(f.Path)
+			val, ok := fm.GetPath(f.Path)
 
 			// Validate value type (must be scalar or slice of scalars)
 			if ok {
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	cctx "github.com/wyvernzora/chunky/pkg/context"
 	"github.com/wyvernzora/chunky/pkg/frontmatter"
 )
 
@@ -30,6 +31,30 @@ func TestKeyValueHeader_Basic(t *testing.T) {
 	}
 }
 
+func TestKeyValueHeader_NestedPaths(t *testing.T) {
+	gen := KeyValueHeader(
+		RequiredField("metadata.version", "Version"),
+		OptionalField("authors[0].name", "Author"),
+		OptionalField(`file\.name`, "File"),
+	)
+
+	fm := frontmatter.FrontMatter{
+		"metadata":  map[any]any{"version": 2},
+		"authors":   []any{map[string]any{"name": "Ada"}},
+		"file.name": "doc.md",
+	}
+
+	result, err := gen(context.Background(), fm.View())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "Version: 2\nAuthor: Ada\nFile: doc.md\n\n"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
 func TestKeyValueHeader_MissingRequired(t *testing.T) {
 	gen := KeyValueHeader(
 		RequiredField("title", "Title"),
@@ -318,3 +343,38 @@ func TestIsScalar(t *testing.T) {
 		})
 	}
 }
+
+func TestKeyValueHeader_ChunkInfo(t *testing.T) {
+	gen := KeyValueHeader(
+		RequiredField("title", "Title"),
+		OptionalField("section_meta.audience", "Audience"),
+		OptionalField("section_url", "Source"),
+		OptionalField("section_links", "Links"),
+	)
+	fm := frontmatter.FrontMatter{"title": "Doc"}
+
+	result, err := gen(context.Background(), fm.View())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(result, "Audience") {
+		t.Errorf("expected no audience without section metadata, got %q", result)
+	}
+
+	ctx := cctx.WithChunkMetadata(context.Background(), map[string]string{"audience": "admins"})
+	ctx = cctx.WithChunkURL(ctx, "https://docs.example.com/guide#install")
+	ctx = cctx.WithChunkLinks(ctx, []string{"docs/faq.md"})
+	result, err = gen(ctx, fm.View())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result, "Audience: admins\n") {
+		t.Errorf("expected section metadata in header, got %q", result)
+	}
+	if !strings.Contains(result, "Source: https://docs.example.com/guide#install\n") {
+		t.Errorf("expected deep link in header, got %q", result)
+	}
+	if !strings.Contains(result, "Links: ") || !strings.Contains(result, "docs/faq.md") {
+		t.Errorf("expected outgoing links in header, got %q", result)
+	}
+}
@@ -3,6 +3,7 @@ package header
 import (
 	"context"
 
+	cctx "github.com/wyvernzora/chunky/pkg/context"
 	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
 )
 
@@ -11,10 +12,56 @@ import (
 // to include additional metadata or use different formatting.
 //
 // The generator receives a read-only view of the frontmatter and returns
-// the header text to prepend to each chunk's body content.
+// the header text to prepend to each chunk's body content. The section
+// metadata, deep link and outgoing links of the chunk, if any, are available
+// from the context through context.ChunkMetadataFrom, context.ChunkURLFrom
+// and context.ChunkLinksFrom.
 //
 // Example implementations:
 //   - YAML frontmatter block: "---\nkey: value\n---\n\n"
 //   - JSON frontmatter block: "```json\n{\"key\": \"value\"}\n```\n\n"
 //   - Custom metadata format
 type ChunkHeader func(ctx context.Context, frontmatter fm.FrontMatterView) (string, error)
+
+// Frontmatter keys under which WithChunkInfo exposes per-chunk information.
+const (
+	// SectionMetaKey holds the merged section metadata of the chunk.
+	SectionMetaKey = "section_meta"
+	// SectionURLKey holds the deep link to where the chunk begins.
+	SectionURLKey = "section_url"
+	// SectionLinksKey holds the targets of the chunk's links to other documents.
+	SectionLinksKey = "section_links"
+)
+
+// WithChunkInfo returns a view of frontmatter with the chunk's section
+// metadata, deep link and outgoing links from ctx added under SectionMetaKey,
+// SectionURLKey and SectionLinksKey, replacing any frontmatter values of those
+// keys. Returns frontmatter unchanged if ctx carries none of them.
+func WithChunkInfo(ctx context.Context, frontmatter fm.FrontMatterView) fm.FrontMatterView {
+	meta, hasMeta := cctx.ChunkMetadataFrom(ctx)
+	url, hasURL := cctx.ChunkURLFrom(ctx)
+	links, hasLinks := cctx.ChunkLinksFrom(ctx)
+	if !hasMeta && !hasURL && !hasLinks {
+		return frontmatter
+	}
+
+	merged := fm.FrontMatter(frontmatter.AsMap())
+	if hasMeta {
+		values := make(map[string]any, len(meta))
+		for k, v := range meta {
+			values[k] = v
+		}
+		merged[SectionMetaKey] = values
+	}
+	if hasURL {
+		merged[SectionURLKey] = url
+	}
+	if hasLinks {
+		values := make([]any, len(links))
+		for i, l := range links {
+			values[i] = l
+		}
+		merged[SectionLinksKey] = values
+	}
+	return merged.View()
+}
@@ -0,0 +1,104 @@
+package markdown
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// CodeBlock is a top-level fenced code block located in Markdown source.
+type CodeBlock struct {
+	// Start and End are the byte offsets of the opening fence line and the
+	// end of the closing fence line. End is just past the final line break,
+	// or the end of the source.
+	Start, End int
+
+	// Fence is the opening fence line, e.g. "```go\n", and Close the closing
+	// fence line, which is empty if the block is never closed.
+	Fence, Close string
+
+	// Language is the first word of the info string, e.g. "go". Empty if the
+	// fence has no info string.
+	Language string
+
+	// Lines are the code lines between the fences, each including its line break.
+	Lines []string
+}
+
+// CodeBlocks returns the fenced code blocks among the top-level blocks of
+// doc, which must have been parsed from src. Blocks nested in block quotes
+// or list items are not returned, since their lines carry container
+// prefixes. Empty blocks without an info string cannot be located and are
+// skipped.
+func CodeBlocks(doc ast.Node, src []byte) []CodeBlock {
+	var blocks []CodeBlock
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		fcb, ok := n.(*ast.FencedCodeBlock)
+		if !ok {
+			continue
+		}
+
+		// Locate the opening fence from the info string or the first code line
+		lines := fcb.Lines()
+		var start int
+		switch {
+		case fcb.Info != nil:
+			start = lineStart(src, fcb.Info.Segment.Start)
+		case lines.Len() > 0:
+			start = lineStart(src, lineStart(src, lines.At(0).Start)-1)
+		default:
+			continue
+		}
+		fenceEnd := lineEnd(src, start)
+		b := CodeBlock{
+			Start:    start,
+			Fence:    string(src[start:fenceEnd]),
+			Language: string(fcb.Language(src)),
+		}
+
+		end := fenceEnd
+		for i := 0; i < lines.Len(); i++ {
+			next := lineEnd(src, lineStart(src, lines.At(i).Start))
+			b.Lines = append(b.Lines, string(src[end:next]))
+			end = next
+		}
+		if end < len(src) && isClosingFence(src[end:lineEnd(src, end)], b.Fence) {
+			b.Close = string(src[end:lineEnd(src, end)])
+			end = lineEnd(src, end)
+		}
+		b.End = end
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// isClosingFence reports whether line closes a code block opened by fence:
+// at least as many of the same fence characters, and nothing else.
+func isClosingFence(line []byte, fence string) bool {
+	open := bytes.TrimLeft([]byte(fence), " ")
+	if len(open) == 0 {
+		return false
+	}
+	c := open[0]
+	n := len(open) - len(bytes.TrimLeft(open, string(c)))
+	trimmed := bytes.TrimSpace(line)
+	return len(trimmed) >= n && len(bytes.Trim(trimmed, string(c))) == 0
+}
+
+// CodeLanguages returns the distinct languages of all fenced code blocks in
+// doc, including nested ones, in document order. Blocks without an info
+// string are ignored.
+func CodeLanguages(doc ast.Node, src []byte) []string {
+	var langs []string
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if fcb, ok := n.(*ast.FencedCodeBlock); ok && entering {
+			if lang := string(fcb.Language(src)); lang != "" && !slices.Contains(langs, lang) {
+				langs = append(langs, lang)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return langs
+}
@@ -0,0 +1,48 @@
+package markdown
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yuin/goldmark/text"
+)
+
+func TestCodeBlocks(t *testing.T) {
+	src := "Intro\n```go title=x\na\n\nb\n```\n\n> ```sh\n> nested\n> ```\n\n~~~~\nc\n~~~~~\n\n```py\nopen"
+	b := []byte(src)
+	blocks := CodeBlocks(Default().Parser().Parse(text.NewReader(b)), b)
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 top-level code blocks, got %d", len(blocks))
+	}
+
+	first := blocks[0]
+	if got := src[first.Start:first.End]; got != "```go title=x\na\n\nb\n```\n" {
+		t.Errorf("unexpected first block span %q", got)
+	}
+	if first.Fence != "```go title=x\n" || first.Close != "```\n" || first.Language != "go" {
+		t.Errorf("unexpected first block fences %+v", first)
+	}
+	if want := []string{"a\n", "\n", "b\n"}; !slices.Equal(first.Lines, want) {
+		t.Errorf("expected lines %q, got %q", want, first.Lines)
+	}
+
+	// Located from the first line without an info string
+	second := blocks[1]
+	if second.Fence != "~~~~\n" || second.Close != "~~~~~\n" || second.Language != "" || !slices.Equal(second.Lines, []string{"c\n"}) {
+		t.Errorf("unexpected second block %+v", second)
+	}
+
+	// Never closed
+	third := blocks[2]
+	if third.Close != "" || third.End != len(src) || !slices.Equal(third.Lines, []string{"open"}) {
+		t.Errorf("unexpected third block %+v", third)
+	}
+}
+
+func TestCodeLanguages(t *testing.T) {
+	src := []byte("```go\n```\n\n- item\n\n  ```yaml\n  a: 1\n  ```\n\n```\nplain\n```\n\n```go\n```\n")
+	got := CodeLanguages(Default().Parser().Parse(text.NewReader(src)), src)
+	if want := []string{"go", "yaml"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,22 @@
+// Package markdown provides the goldmark configuration shared by the parser,
+// section transforms and derive transforms.
+//
+// Every stage that inspects Markdown structure must agree on what the blocks
+// are: if the parser sees a GFM table but a transform sees a paragraph, the
+// transform reflows the table rows into a single line. The configuration is
+// therefore built once and carried through the pipeline in the context:
+//
+//	md, err := markdown.New(markdown.GFM, markdown.Footnote)
+//	ctx = context.WithMarkdown(ctx, md)
+//
+// Stages retrieve it with context.Markdown, which falls back to Default when
+// none is set. The chunker does this for every document when configured with
+// chunker.WithMarkdown.
+//
+// # Extensions
+//
+// By default GFM (tables, strikethrough, task lists, autolinks), footnotes and
+// definition lists are enabled. Typographer can be enabled as well; it turns
+// straight quotes and dashes into typographic ones in text extracted from the
+// AST, such as heading titles.
+package markdown
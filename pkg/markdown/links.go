@@ -0,0 +1,130 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/util"
+)
+
+// LinkDestination is the destination of an inline link located in Markdown
+// source.
+type LinkDestination struct {
+	// Start and End are the byte offsets of the destination as written,
+	// without enclosing angle brackets.
+	Start, End int
+
+	// Dest is the destination as written, e.g. "../guide/install.md#linux".
+	Dest string
+}
+
+// LinkDestinations returns the destinations of the inline links in doc,
+// which must have been parsed from src, in document order. Images, autolinks
+// and reference-style links, whose destinations are not written next to
+// them, are not returned; neither are links without text, which cannot be
+// located.
+func LinkDestinations(doc ast.Node, src []byte) []LinkDestination {
+	var dests []LinkDestination
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		link, ok := n.(*ast.Link)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		if d, ok := locateDestination(link, src); ok {
+			dests = append(dests, d)
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return dests
+}
+
+// locateDestination finds the destination of an inline link after the end
+// of its text.
+func locateDestination(link *ast.Link, src []byte) (LinkDestination, bool) {
+	textEnd := -1
+	_ = ast.Walk(link, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := n.(*ast.Text); ok && entering && t.Segment.Stop > textEnd {
+			textEnd = t.Segment.Stop
+		}
+		return ast.WalkContinue, nil
+	})
+	if textEnd < 0 {
+		return LinkDestination{}, false
+	}
+
+	// Only closing emphasis and code span delimiters may follow the text
+	i := bytes.Index(src[textEnd:], []byte("]("))
+	if i < 0 || len(bytes.Trim(src[textEnd:textEnd+i], "*_~`")) > 0 {
+		return LinkDestination{}, false
+	}
+	start := textEnd + i + 2
+	for start < len(src) && (src[start] == ' ' || src[start] == '\t' || src[start] == '\n') {
+		start++
+	}
+	if start < len(src) && src[start] == '<' {
+		start++
+	}
+
+	end := start + len(link.Destination)
+	if end > len(src) || !bytes.Equal(src[start:end], link.Destination) {
+		return LinkDestination{}, false
+	}
+	return LinkDestination{Start: start, End: end, Dest: string(link.Destination)}, true
+}
+
+// definitionPattern matches the label and destination of a link reference
+// definition at the start of a line; the destination may be on the next line.
+var definitionPattern = regexp.MustCompile(`(?m)^ {0,3}\[((?:[^\[\]\\]|\\.)+)\]:[ \t]*\n?[ \t]*(<[^<>\n]*>|[^\s<]\S*)`)
+
+// DefinitionDestinations returns the destinations of the link reference
+// definitions in src, such as "../guide/install.md" in
+// "[guide]: ../guide/install.md", in document order. doc must have been
+// parsed from src with pc, which holds the definitions goldmark found.
+//
+// goldmark drops definitions from the AST, so they are located in the source
+// lines no block covers and checked against pc. Definitions inside block
+// quotes and list items, and destinations written with backslash escapes,
+// are not returned.
+func DefinitionDestinations(doc ast.Node, src []byte, pc parser.Context) []LinkDestination {
+	var covered [][2]int
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && n.Type() == ast.TypeBlock {
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				seg := lines.At(i)
+				covered = append(covered, [2]int{seg.Start, seg.Stop})
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	isCovered := func(pos int) bool {
+		for _, c := range covered {
+			if pos >= c[0] && pos < c[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var dests []LinkDestination
+	for _, m := range definitionPattern.FindAllSubmatchIndex(src, -1) {
+		if isCovered(m[2]) {
+			continue // text of a paragraph, code block or other block
+		}
+		ref, ok := pc.Reference(util.ToLinkReference(src[m[2]:m[3]]))
+		if !ok {
+			continue
+		}
+		start, end := m[4], m[5]
+		if src[start] == '<' {
+			start, end = start+1, end-1
+		}
+		if !bytes.Equal(src[start:end], ref.Destination()) {
+			continue
+		}
+		dests = append(dests, LinkDestination{Start: start, End: end, Dest: string(ref.Destination())})
+	}
+	return dests
+}
@@ -0,0 +1,52 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestLinkDestinations(t *testing.T) {
+	src := "A [plain](a.md) and [*emphasized*](<b c.md> \"Title\"), [`code`](\nc.md#x).\n\n" +
+		"![image](d.png), <https://auto.example.com>, [ref][r], [](empty.md) and [p](e_(1).md).\n\n" +
+		"- [nested](f.md)\n\n[r]: g.md\n"
+	b := []byte(src)
+	dests := LinkDestinations(Default().Parser().Parse(text.NewReader(b)), b)
+
+	want := []string{"a.md", "b c.md", "c.md#x", "e_(1).md", "f.md"}
+	if len(dests) != len(want) {
+		t.Fatalf("expected %d destinations, got %+v", len(want), dests)
+	}
+	for i, d := range dests {
+		if d.Dest != want[i] || src[d.Start:d.End] != want[i] {
+			t.Errorf("destination %d: expected %q, got %q at %q", i, want[i], d.Dest, src[d.Start:d.End])
+		}
+	}
+}
+
+func TestDefinitionDestinations(t *testing.T) {
+	src := "See [guide][g], [API] and [install][].\n\n" +
+		"[g]: ../guide/install.md \"Guide\"\n" +
+		"[API]:\n  <api reference.md>\n" +
+		"  [Install]: install.md#linux\n\n" +
+		"```\n[code]: code.md\n```\n\n" +
+		"    [indented]: indented.md\n\n" +
+		"> [quoted]: quoted.md\n\n" +
+		"[unused]: unused.md\n" +
+		"[g]: duplicate.md\n"
+	b := []byte(src)
+	pc := parser.NewContext()
+	doc := Default().Parser().Parse(text.NewReader(b), parser.WithContext(pc))
+
+	dests := DefinitionDestinations(doc, b, pc)
+	want := []string{"../guide/install.md", "api reference.md", "install.md#linux", "unused.md"}
+	if len(dests) != len(want) {
+		t.Fatalf("expected %d destinations, got %+v", len(want), dests)
+	}
+	for i, d := range dests {
+		if d.Dest != want[i] || src[d.Start:d.End] != want[i] {
+			t.Errorf("destination %d: expected %q, got %q at %q", i, want[i], d.Dest, src[d.Start:d.End])
+		}
+	}
+}
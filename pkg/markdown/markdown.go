@@ -0,0 +1,110 @@
+package markdown
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gparser "github.com/yuin/goldmark/parser"
+)
+
+// Extension names an optional goldmark syntax extension.
+type Extension string
+
+const (
+	// GFM enables GitHub Flavored Markdown: tables, strikethrough, task lists
+	// and bare URL autolinks.
+	GFM Extension = "gfm"
+
+	// Footnote enables "[^1]" footnote references and definitions.
+	Footnote Extension = "footnote"
+
+	// DefinitionList enables "Term\n: Definition" definition lists.
+	DefinitionList Extension = "definitionList"
+
+	// Typographer parses straight quotes, dashes and ellipses as their
+	// typographic counterparts. It only affects plain text extracted from the
+	// AST, such as heading titles and derived descriptions; chunk text is
+	// always taken verbatim from the source.
+	Typographer Extension = "typographer"
+)
+
+// DefaultExtensions are the extensions enabled by Default. Typographer is
+// left out so that heading titles keep the punctuation of the source.
+var DefaultExtensions = []Extension{GFM, Footnote, DefinitionList}
+
+// Extensions lists every supported extension.
+var Extensions = []Extension{GFM, Footnote, DefinitionList, Typographer}
+
+// ParseExtension returns the extension with the given name.
+func ParseExtension(name string) (Extension, error) {
+	for _, e := range Extensions {
+		if string(e) == name {
+			return e, nil
+		}
+	}
+	names := make([]string, len(Extensions))
+	for i, e := range Extensions {
+		names[i] = string(e)
+	}
+	return "", fmt.Errorf("unknown markdown extension %q, expected one of: %s", name, strings.Join(names, ", "))
+}
+
+// extender returns the goldmark extension implementing e, or nil if e is unknown.
+func (e Extension) extender() goldmark.Extender {
+	switch e {
+	case GFM:
+		return extension.GFM
+	case Footnote:
+		return extension.Footnote
+	case DefinitionList:
+		return extension.DefinitionList
+	case Typographer:
+		return extension.Typographer
+	default:
+		return nil
+	}
+}
+
+// New returns a goldmark configuration with exactly the given extensions
+// enabled; with none, only CommonMark is recognized. Heading IDs and
+// attributes are always enabled, since the parser turns them into section
+// anchors and metadata.
+//
+// Returns an error if an extension is unknown.
+//
+// Example:
+//
+//	md, err := markdown.New(markdown.GFM, markdown.Typographer)
+func New(exts ...Extension) (goldmark.Markdown, error) {
+	var extenders []goldmark.Extender
+	for _, e := range exts {
+		if _, err := ParseExtension(string(e)); err != nil {
+			return nil, err
+		}
+		extenders = append(extenders, e.extender())
+	}
+	return goldmark.New(
+		goldmark.WithExtensions(extenders...),
+		goldmark.WithParserOptions(
+			gparser.WithAutoHeadingID(), // heading IDs become section anchors
+			gparser.WithAttribute(),     // heading attributes become section metadata
+		),
+	), nil
+}
+
+// Default returns the shared configuration with DefaultExtensions enabled.
+func Default() goldmark.Markdown {
+	return defaultMarkdown()
+}
+
+var defaultMarkdown = sync.OnceValue(func() goldmark.Markdown {
+	md, err := New(slices.Clone(DefaultExtensions)...)
+	if err != nil {
+		panic(err) // DefaultExtensions are all known
+	}
+	return md
+})
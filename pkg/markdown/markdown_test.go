@@ -0,0 +1,75 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// kinds returns the kinds of the top-level blocks of src parsed with exts.
+func kinds(t *testing.T, exts []Extension, src string) []ast.NodeKind {
+	t.Helper()
+	md, err := New(exts...)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	var out []ast.NodeKind
+	doc := md.Parser().Parse(text.NewReader([]byte(src)))
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		out = append(out, n.Kind())
+	}
+	return out
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		exts []Extension
+		src  string
+		want ast.NodeKind
+	}{
+		{"table with GFM", []Extension{GFM}, "| a |\n| - |\n| 1 |\n", east.KindTable},
+		{"table without GFM", nil, "| a |\n| - |\n| 1 |\n", ast.KindParagraph},
+		{"definition list", []Extension{DefinitionList}, "Term\n: Definition\n", east.KindDefinitionList},
+		{"definition list disabled", []Extension{GFM}, "Term\n: Definition\n", ast.KindParagraph},
+		{"footnote", []Extension{Footnote}, "Text.[^1]\n\n[^1]: Note.\n", east.KindFootnoteList},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := kinds(t, tt.exts, tt.src)
+			if len(got) == 0 || got[len(got)-1] != tt.want {
+				t.Errorf("expected last block %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNew_UnknownExtension(t *testing.T) {
+	if _, err := New(GFM, Extension("mermaid")); err == nil {
+		t.Fatal("expected error for unknown extension")
+	}
+}
+
+func TestParseExtension(t *testing.T) {
+	for _, e := range Extensions {
+		got, err := ParseExtension(string(e))
+		if err != nil || got != e {
+			t.Errorf("ParseExtension(%q) = %q, %v", e, got, err)
+		}
+	}
+	if _, err := ParseExtension("GFM"); err == nil {
+		t.Error("expected error for wrong case")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if Default() != Default() {
+		t.Error("expected Default to return a shared instance")
+	}
+	got := kinds(t, DefaultExtensions, "| a |\n| - |\n| 1 |\n\nTerm\n: Definition\n")
+	if len(got) != 2 || got[0] != east.KindTable || got[1] != east.KindDefinitionList {
+		t.Errorf("expected table and definition list, got %v", got)
+	}
+}
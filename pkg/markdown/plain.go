@@ -0,0 +1,243 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+)
+
+var (
+	htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlTagRe     = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// PlainText renders doc, which must have been parsed from src, as plain text
+// for embedding. Markup that carries no meaning for a reader is dropped:
+// emphasis markers, heading markers, link destinations, images other than
+// their alt text, HTML tags and comments. Structure is kept:
+//
+//   - blocks are separated by blank lines, and HTML blocks are reduced to the
+//     text between their tags;
+//   - list items keep their bullet or number, task list items their checkbox,
+//     and nested content is indented below its item;
+//   - code blocks keep their lines and indentation, without the fences;
+//   - table rows become lines of cells separated by " | ", without the
+//     delimiter row.
+//
+// With linkURLs, link destinations are kept after the link text, e.g.
+// "the docs (https://example.com/docs)". The result has no trailing newline.
+func PlainText(doc ast.Node, src []byte, linkURLs bool) string {
+	r := &plainRenderer{src: src, linkURLs: linkURLs}
+	return strings.Join(r.children(doc), "\n")
+}
+
+// plainRenderer renders goldmark ASTs as plain text lines.
+type plainRenderer struct {
+	src      []byte
+	linkURLs bool
+}
+
+// children renders the block children of n, separated by blank lines.
+func (r *plainRenderer) children(n ast.Node) []string {
+	var lines []string
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		block := r.block(c)
+		if len(block) == 0 {
+			continue
+		}
+		if len(lines) > 0 && !isTightItem(c) {
+			lines = append(lines, "")
+		}
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+// isTightItem reports whether a block follows its previous sibling without a
+// blank line, as the paragraphs of tight list items and the descriptions of
+// definition terms do.
+func isTightItem(n ast.Node) bool {
+	switch n.PreviousSibling().(type) {
+	case *ast.TextBlock:
+		return true
+	case *east.DefinitionTerm:
+		_, ok := n.(*east.DefinitionDescription)
+		return ok
+	}
+	return false
+}
+
+// block renders a block node as lines of text.
+func (r *plainRenderer) block(n ast.Node) []string {
+	switch t := n.(type) {
+	case *ast.Heading, *ast.Paragraph, *ast.TextBlock, *east.DefinitionTerm:
+		return r.textLines(n)
+	case *ast.ThematicBreak:
+		return nil
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		var lines []string
+		for i := 0; i < n.Lines().Len(); i++ {
+			seg := n.Lines().At(i)
+			lines = append(lines, strings.TrimRight(string(seg.Value(r.src)), "\r\n"))
+		}
+		return lines
+	case *ast.HTMLBlock:
+		return r.htmlBlock(t)
+	case *ast.List:
+		return r.list(t)
+	case *east.Table:
+		var lines []string
+		for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+			var cells []string
+			for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+				cells = append(cells, strings.TrimSpace(r.inline(cell)))
+			}
+			lines = append(lines, strings.Join(cells, " | "))
+		}
+		return lines
+	case *east.DefinitionDescription:
+		return indent(r.children(n), "  ")
+	case *east.FootnoteList:
+		var lines []string
+		for fn := n.FirstChild(); fn != nil; fn = fn.NextSibling() {
+			if f, ok := fn.(*east.Footnote); ok {
+				lines = append(lines, prefix(r.children(f), fmt.Sprintf("[%d] ", f.Index))...)
+			}
+		}
+		return lines
+	default:
+		return r.children(n)
+	}
+}
+
+// list renders list items with their markers, indenting continuation lines.
+func (r *plainRenderer) list(l *ast.List) []string {
+	var lines []string
+	number := l.Start
+	for item := l.FirstChild(); item != nil; item = item.NextSibling() {
+		marker := "- "
+		if l.IsOrdered() {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+		if len(lines) > 0 && !l.IsTight {
+			lines = append(lines, "")
+		}
+		body := r.children(item)
+		if len(body) == 0 {
+			body = []string{""}
+		}
+		lines = append(lines, prefix(body, marker)...)
+	}
+	return lines
+}
+
+// htmlBlock renders the text content of an HTML block, dropping comments and
+// tags. Returns nil if nothing is left.
+func (r *plainRenderer) htmlBlock(b *ast.HTMLBlock) []string {
+	var buf bytes.Buffer
+	for i := 0; i < b.Lines().Len(); i++ {
+		seg := b.Lines().At(i)
+		buf.Write(seg.Value(r.src))
+	}
+	if b.HasClosure() {
+		buf.Write(b.ClosureLine.Value(r.src))
+	}
+	text := htmlCommentRe.ReplaceAllString(buf.String(), "")
+	text = html.UnescapeString(htmlTagRe.ReplaceAllString(text, ""))
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// textLines renders the inline content of a block as lines, split at hard
+// line breaks.
+func (r *plainRenderer) textLines(n ast.Node) []string {
+	text := strings.TrimSpace(r.inline(n))
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// inline renders the inline children of n. Soft line breaks become spaces
+// and hard line breaks newlines.
+func (r *plainRenderer) inline(n ast.Node) string {
+	var buf strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			buf.Write(t.Segment.Value(r.src))
+			switch {
+			case t.HardLineBreak():
+				buf.WriteByte('\n')
+			case t.SoftLineBreak():
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.WriteString(html.UnescapeString(string(t.Value)))
+		case *ast.CodeSpan:
+			for cc := t.FirstChild(); cc != nil; cc = cc.NextSibling() {
+				if s, ok := cc.(*ast.Text); ok {
+					buf.Write(s.Segment.Value(r.src))
+				}
+			}
+		case *ast.AutoLink:
+			buf.Write(t.Label(r.src))
+		case *ast.Link:
+			text := r.inline(t)
+			buf.WriteString(text)
+			if dest := string(t.Destination); r.linkURLs && dest != "" && dest != text {
+				buf.WriteString(" (" + dest + ")")
+			}
+		case *ast.RawHTML:
+			// Inline tags are dropped; the text between them is kept
+		case *east.TaskCheckBox:
+			if t.IsChecked {
+				buf.WriteString("[x] ")
+			} else {
+				buf.WriteString("[ ] ")
+			}
+		case *east.FootnoteLink:
+			fmt.Fprintf(&buf, "[%d]", t.Index)
+		case *east.FootnoteBacklink:
+		default:
+			// Emphasis, strikethrough and image alt text
+			buf.WriteString(r.inline(t))
+		}
+	}
+	return buf.String()
+}
+
+// prefix prepends p to the first line and indents the others to match.
+// Without lines, e.g. for an empty footnote, p makes up the only line.
+func prefix(lines []string, p string) []string {
+	if len(lines) == 0 {
+		return []string{strings.TrimRight(p, " ")}
+	}
+	out := indent(lines, strings.Repeat(" ", len(p)))
+	out[0] = p + lines[0]
+	return out
+}
+
+// indent indents every non-empty line.
+func indent(lines []string, in string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		if l != "" {
+			l = in + l
+		}
+		out[i] = l
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark/text"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		linkURLs bool
+		want     string
+	}{
+		{
+			name: "inline markup",
+			src:  "# Title *here*\n\nSome **bold**, _em_, ~~struck~~ and `code`\nwith a [link](https://x.com) and ![alt text](i.png).\n",
+			want: "Title here\n\nSome bold, em, struck and code with a link and alt text.",
+		},
+		{
+			name:     "link URLs",
+			src:      "A [link](https://x.com) and <https://auto.com>.\n",
+			linkURLs: true,
+			want:     "A link (https://x.com) and https://auto.com.",
+		},
+		{
+			name: "html",
+			src:  "<!-- path: A -->\n\nText with <b>inline</b> tags.\n\n<div align=\"center\">\n  <img src=\"x.png\">\n  <p>Centered &amp; text</p>\n</div>\n\n<!-- comment -->\n",
+			want: "Text with inline tags.\n\nCentered & text",
+		},
+		{
+			name: "lists",
+			src:  "- one\n- two\n  - nested\n- [x] done\n\n3. first\n\n   more\n4. second\n",
+			want: "- one\n- two\n  - nested\n- [x] done\n\n3. first\n\n   more\n\n4. second",
+		},
+		{
+			name: "code",
+			src:  "```go\nfunc main() {\n\tx := 1\n}\n```\n\n    indented\n",
+			want: "func main() {\n\tx := 1\n}\n\nindented",
+		},
+		{
+			name: "table and definitions",
+			src:  "| A | *B* |\n| - | - |\n| 1 | 2 |\n\nTerm\n: Meaning\n",
+			want: "A | B\n1 | 2\n\nTerm\n  Meaning",
+		},
+		{
+			name: "quote, break and footnote",
+			src:  "> Quoted.\n\n---\n\nNote.[^n]\n\n[^n]: The note.\n",
+			want: "Quoted.\n\nNote.[1]\n\n[1] The note.",
+		},
+		{
+			name: "empty footnotes",
+			src:  "See[^a] and[^b].\n\n[^a]:\n\n[^b]: <!-- x -->\n",
+			want: "See[1] and[2].\n\n[1]\n[2]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := []byte(tt.src)
+			got := PlainText(Default().Parser().Parse(text.NewReader(src)), src, tt.linkURLs)
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+)
+
+// Table is a top-level GFM table located in Markdown source.
+type Table struct {
+	// Start and End are the byte offsets of the table's first and last line.
+	// End is just past the final line break, or the end of the source.
+	Start, End int
+
+	// Header and Delimiter are the header row and the "| --- |" row,
+	// each including its line break.
+	Header, Delimiter string
+
+	// Rows are the body rows, each including its line break.
+	Rows []string
+}
+
+// Tables returns the GFM tables among the top-level blocks of doc, which
+// must have been parsed from src with the GFM extension. Tables nested in
+// block quotes or list items are not returned, since their lines carry
+// container prefixes.
+//
+// Tables without any cell text cannot be located and are skipped.
+func Tables(doc ast.Node, src []byte) []Table {
+	var tables []Table
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		if n.Kind() != east.KindTable {
+			continue
+		}
+		pos, line := firstCellText(n)
+		if pos < 0 {
+			continue
+		}
+
+		// Walk back from the line holding the text to the header row
+		start := lineStart(src, pos)
+		for ; line > 0 && start > 0; line-- {
+			start = lineStart(src, start-1)
+		}
+
+		lines := make([]string, 0, n.ChildCount()+1)
+		end := start
+		for len(lines) < n.ChildCount()+1 && end < len(src) {
+			next := lineEnd(src, end)
+			lines = append(lines, string(src[end:next]))
+			end = next
+		}
+		if len(lines) < 2 {
+			continue
+		}
+		tables = append(tables, Table{
+			Start:     start,
+			End:       end,
+			Header:    lines[0],
+			Delimiter: lines[1],
+			Rows:      lines[2:],
+		})
+	}
+	return tables
+}
+
+// firstCellText returns the source offset of the first text in a table's
+// cells and the index of its line within the table (0 for the header row,
+// 2 for the first body row). Returns -1 if no cell holds any text.
+func firstCellText(table ast.Node) (int, int) {
+	line := 0
+	for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+		pos := -1
+		_ = ast.Walk(row, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			if t, ok := n.(*ast.Text); ok && entering {
+				pos = t.Segment.Start
+				return ast.WalkStop, nil
+			}
+			return ast.WalkContinue, nil
+		})
+		if pos >= 0 {
+			return pos, line
+		}
+		if line == 0 {
+			line++ // skip the delimiter row
+		}
+		line++
+	}
+	return -1, 0
+}
+
+// SplitRow splits a table row line into its trimmed cell texts. Leading and
+// trailing pipes are optional and escaped pipes ("\|") are unescaped.
+func SplitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// lineStart returns the offset of the start of the line containing pos.
+func lineStart(src []byte, pos int) int {
+	return bytes.LastIndexByte(src[:pos], '\n') + 1
+}
+
+// lineEnd returns the offset just past the line break of the line starting
+// at pos, or len(src) if the line is not terminated.
+func lineEnd(src []byte, pos int) int {
+	if i := bytes.IndexByte(src[pos:], '\n'); i >= 0 {
+		return pos + i + 1
+	}
+	return len(src)
+}
@@ -0,0 +1,56 @@
+package markdown
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yuin/goldmark/text"
+)
+
+func parseTables(t *testing.T, src string) []Table {
+	t.Helper()
+	b := []byte(src)
+	return Tables(Default().Parser().Parse(text.NewReader(b)), b)
+}
+
+func TestTables(t *testing.T) {
+	src := "Intro\n| A | B |\n| - | - |\n| 1 | 2 |\n|   | 3 |\n\n> | Q |\n> | - |\n> | x |\n\n|  |  |\n| - | - |\n|  | z |"
+	tables := parseTables(t, src)
+	if len(tables) != 2 {
+		t.Fatalf("expected 2 top-level tables, got %d", len(tables))
+	}
+
+	first := tables[0]
+	if got := src[first.Start:first.End]; got != "| A | B |\n| - | - |\n| 1 | 2 |\n|   | 3 |\n" {
+		t.Errorf("unexpected first table span %q", got)
+	}
+	if first.Header != "| A | B |\n" || first.Delimiter != "| - | - |\n" {
+		t.Errorf("unexpected header rows %q %q", first.Header, first.Delimiter)
+	}
+	if want := []string{"| 1 | 2 |\n", "|   | 3 |\n"}; !slices.Equal(first.Rows, want) {
+		t.Errorf("expected rows %q, got %q", want, first.Rows)
+	}
+
+	// Located from a body row when the header is empty
+	second := tables[1]
+	if second.Header != "|  |  |\n" || !slices.Equal(second.Rows, []string{"|  | z |"}) || second.End != len(src) {
+		t.Errorf("unexpected second table %+v", second)
+	}
+}
+
+func TestSplitRow(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"| a | b |\n", []string{"a", "b"}},
+		{"a | b", []string{"a", "b"}},
+		{"| `x \\| y` | |", []string{"`x | y`", ""}},
+		{"| a \\|", []string{"a |"}},
+	}
+	for _, tt := range tests {
+		if got := SplitRow(tt.line); !slices.Equal(got, tt.want) {
+			t.Errorf("SplitRow(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
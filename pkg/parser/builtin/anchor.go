@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"bytes"
+	"strconv"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+	gparser "github.com/yuin/goldmark/parser"
+)
+
+// anchorIDs generates heading anchor IDs the way GitHub does: the heading
+// text is lowercased, spaces become hyphens, and everything except letters,
+// digits, hyphens and underscores is dropped. Repeated IDs get a numeric
+// suffix ("install", "install-1").
+//
+// IDs from "{#id}" heading attributes are not generated; the generated set
+// tells them apart.
+type anchorIDs struct {
+	seen      map[string]bool
+	generated map[string]bool
+}
+
+var _ gparser.IDs = (*anchorIDs)(nil)
+
+func newAnchorIDs() *anchorIDs {
+	return &anchorIDs{seen: make(map[string]bool), generated: make(map[string]bool)}
+}
+
+// Generate implements gparser.IDs.
+func (a *anchorIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	var buf bytes.Buffer
+	for _, r := range string(bytes.TrimSpace(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			buf.WriteRune(unicode.ToLower(r))
+		case r == ' ':
+			buf.WriteByte('-')
+		}
+	}
+	base := buf.String()
+	if base == "" {
+		base = "heading"
+	}
+
+	id := base
+	for i := 1; a.seen[id]; i++ {
+		id = base + "-" + strconv.Itoa(i)
+	}
+	a.seen[id] = true
+	a.generated[id] = true
+	return []byte(id)
+}
+
+// Put implements gparser.IDs.
+func (a *anchorIDs) Put(value []byte) {
+	a.seen[string(value)] = true
+}
@@ -5,13 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
 	"log/slog"
+	"strings"
 
 	"github.com/adrg/frontmatter"
 	cctx "github.com/wyvernzora/chunky/pkg/context"
 	cfm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/parser"
 	"github.com/wyvernzora/chunky/pkg/section"
-	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
 	gparser "github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
@@ -19,11 +21,40 @@ import (
 
 // DefaultParser is the default implementation of pkg.Parser.
 //
-// It operates in four stages:
+// It operates in five stages:
 //  1. Extract YAML frontmatter from the document header (delimited by "---")
-//  2. Parse the remaining Markdown into an AST using goldmark
-//  3. Walk the AST to identify heading locations, levels, and titles
+//  2. Parse the remaining Markdown into an AST using goldmark, applying any
+//     chunky directives (see below) and re-parsing if there are some
+//  3. Walk the AST to identify heading locations, levels, and titles; setext
+//     heading spans include their underline, and headings nested in block
+//     quotes or list items are left in content (see WithNestedHeadings)
 //  4. Fold the headings and intervening text into a nested Section structure
+//  5. Attach section metadata from chunky:meta directives
+//
+// Heading attributes such as "## Install {.beta audience=admins}" are removed
+// from the title and become section metadata: classes are stored under
+// "class" (space separated) and other attributes under their own name. The
+// "#id" attribute is not treated as metadata.
+// A chunky:meta directive takes precedence over a heading attribute.
+//
+// Every heading section gets an anchor ID (see Section.Anchor): the explicit
+// "{#id}" attribute if present, otherwise a slug generated from the heading
+// text the way GitHub does, made unique within the document ("install",
+// "install-1").
+//
+// Directives are HTML comments on lines of their own at the top level of the
+// document (not inside code blocks, lists or quotes):
+//   - <!-- chunky:break --> forces a chunk boundary
+//   - <!-- chunky:keep-together --> ... <!-- chunky:end --> is never split
+//   - <!-- chunky:ignore --> ... <!-- chunky:end --> is dropped, headings included
+//   - <!-- chunky:meta key=value --> sets metadata on the enclosing section;
+//     values may be double quoted and several pairs may be given
+//
+// Break and keep-together directives are kept in section content as
+// section.BreakMarker, section.KeepTogetherMarker and section.EndMarker lines,
+// which the chunker honors and removes. All other directives are removed by
+// the parser. Unknown directives are removed with a warning; unmatched or
+// unclosed regions fail the parse.
 //
 // The root section title is derived from context using chunkyctx.FileInfoFrom().
 // If no FileInfo is present in context, the title defaults to "Untitled".
@@ -35,15 +66,77 @@ func DefaultParser(ctx context.Context, markdown []byte) (*section.Section, cfm.
 	return w.parse(markdown)
 }
 
+// Option configures a parser created by NewParser.
+type Option func(*parserOptions)
+
+type parserOptions struct {
+	promoteTitle   bool
+	nestedHeadings bool
+}
+
+// WithPromotedTitle makes a lone leading H1 the root of the section tree.
+//
+// Many documents open with a single "# Title" heading that repeats the file
+// title, which nests every other section one level deeper than needed and
+// produces breadcrumbs like "getting-started / Getting Started / Install".
+// When the document has exactly one H1 and nothing but whitespace precedes
+// it, the H1's title becomes the root title, its body (including the heading
+// line itself) becomes the root content, and its subsections become children
+// of the root. Heading levels are unchanged.
+//
+// Documents with several H1s, or with text before the first H1, are parsed as usual.
+func WithPromotedTitle() Option {
+	return func(o *parserOptions) {
+		o.promoteTitle = true
+	}
+}
+
+// WithNestedHeadings treats headings inside block quotes and list items as
+// sections, like top-level headings.
+//
+// By default such headings are part of the content of the section around
+// them, so that quoting or listing Markdown that contains headings does not
+// split the quote or list across sections.
+func WithNestedHeadings() Option {
+	return func(o *parserOptions) {
+		o.nestedHeadings = true
+	}
+}
+
+// NewParser returns a parser that behaves like DefaultParser with the given options applied.
+//
+// Example:
+//
+//	p := builtin.NewParser(builtin.WithPromotedTitle())
+//	chunker, err := chunker.New(
+//	    chunker.WithChunkTokenBudget(1000),
+//	    chunker.WithParser(p),
+//	)
+func NewParser(opts ...Option) parser.Parser {
+	var cfg parserOptions
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(ctx context.Context, markdown []byte) (*section.Section, cfm.FrontMatter, error) {
+		w := &worker{ctx: ctx, opts: cfg}
+		return w.parse(markdown)
+	}
+}
+
 // worker is the internal parser implementation that holds state during parsing.
 type worker struct {
 	ctx    context.Context
+	opts   parserOptions
 	src    []byte           // source bytes (frontmatter removed)
 	doc    ast.Node         // goldmark AST root
+	ids    *anchorIDs       // anchor IDs assigned while parsing
 	spans  []headingSpan    // ordered headings extracted from AST
 	cursor int              // current byte position during section folding
 	stack  []sectionFrame   // section stack for nesting logic
 	root   *section.Section // final parsed section tree
+
+	sections   []*section.Section // section created for each span
+	lineOffset int                // lines taken by frontmatter, for error messages
 }
 
 func (w *worker) parse(markdown []byte) (*section.Section, cfm.FrontMatter, error) {
@@ -70,6 +163,9 @@ func (w *worker) parse(markdown []byte) (*section.Section, cfm.FrontMatter, erro
 		fm = cfm.EmptyFrontMatter()
 	}
 	w.src = []byte(body)
+	if bytes.HasSuffix(markdown, body) {
+		w.lineOffset = bytes.Count(markdown[:len(markdown)-len(body)], []byte("\n"))
+	}
 	logger.Debug("frontmatter extracted",
 		slog.Int("frontmatter_keys", len(fm)),
 		slog.Int("body_size", len(w.src)))
@@ -81,6 +177,20 @@ func (w *worker) parse(markdown []byte) (*section.Section, cfm.FrontMatter, erro
 	}
 	logger.Debug("markdown AST parsed")
 
+	// 2b) apply chunky directives and re-parse the rewritten source
+	var metas []metaDirective
+	if dirs := w.findDirectives(); len(dirs) > 0 {
+		if w.src, metas, err = w.applyDirectives(dirs); err != nil {
+			logger.Error("invalid chunky directive", slog.Any("error", err))
+			return nil, nil, err
+		}
+		if err := w.parseDoc(); err != nil {
+			logger.Error("markdown AST parsing failed", slog.Any("error", err))
+			return nil, nil, err
+		}
+		logger.Debug("chunky directives applied", slog.Int("directive_count", len(dirs)))
+	}
+
 	// 3) collect heading spans (offsets + titles)
 	w.extractHeadings()
 	logger.Debug("heading spans extracted", slog.Int("heading_count", len(w.spans)))
@@ -93,6 +203,9 @@ func (w *worker) parse(markdown []byte) (*section.Section, cfm.FrontMatter, erro
 	logger.Debug("section tree folded successfully",
 		slog.Int("root_children", len(w.root.Children())))
 
+	// 5) attach section metadata from chunky:meta directives
+	w.attachMeta(metas)
+
 	return w.root, fm, nil
 }
 
@@ -101,22 +214,24 @@ func (w *worker) parse(markdown []byte) (*section.Section, cfm.FrontMatter, erro
 type sectionFrame struct{ s *section.Section }
 
 type headingSpan struct {
-	Node  *ast.Heading // goldmark AST node
-	Start int          // byte offset where heading line begins
-	End   int          // byte offset where heading line ends
-	Level int          // nesting depth (1=h1, 2=h2, etc.)
-	Title string       // rendered heading text with inline formatting stripped
+	Node     *ast.Heading      // goldmark AST node
+	Start    int               // byte offset where heading line begins
+	End      int               // byte offset where heading line ends
+	Text     int               // byte offset where heading text ends
+	Level    int               // nesting depth (1=h1, 2=h2, etc.)
+	Title    string            // rendered heading text with inline formatting stripped
+	Anchor   string            // anchor ID, explicit or generated
+	Explicit bool              // anchor comes from a "{#id}" attribute
+	Meta     map[string]string // metadata from heading attributes
 }
 
 // --- Stage 1: parse the document AST ----------------------------------------
 
 func (w *worker) parseDoc() error {
-	md := goldmark.New(
-		goldmark.WithParserOptions(
-			gparser.WithAutoHeadingID(), // OK to keep; not strictly required
-		),
-	)
-	w.doc = md.Parser().Parse(text.NewReader(w.src))
+	md := cctx.Markdown(w.ctx)
+	w.ids = newAnchorIDs()
+	pc := gparser.NewContext(gparser.WithIDs(w.ids))
+	w.doc = md.Parser().Parse(text.NewReader(w.src), gparser.WithContext(pc))
 	if w.doc == nil {
 		return errors.New("goldmark: empty document root")
 	}
@@ -138,6 +253,13 @@ func (w *worker) extractHeadings() {
 			return ast.WalkContinue, nil
 		}
 
+		// Headings inside block quotes and list items are content unless enabled
+		if h.Parent() != nil && h.Parent().Kind() != ast.KindDocument && !w.opts.nestedHeadings {
+			logger.Debug("nested heading kept as content",
+				slog.String("container", h.Parent().Kind().String()))
+			return ast.WalkContinue, nil
+		}
+
 		lines := h.Lines()
 		if lines.Len() == 0 {
 			return ast.WalkContinue, nil
@@ -153,13 +275,29 @@ func (w *worker) extractHeadings() {
 			lineStart--
 		}
 
-		title := inlineText(h, w.src)
+		// Closing hashes and attribute blocks after the heading text are not part
+		// of the segment; extend the span to the end of the line so that they do
+		// not leak into content. Setext headings may span several lines of text
+		// and end with an underline ("===" or "---") on a line of its own, which
+		// belongs to the span too.
+		last := lines.At(lines.Len() - 1)
+		end := lineEnd(w.src, last.Stop)
+		if !bytes.ContainsRune(w.src[lineStart:seg.Start], '#') && end < len(w.src) {
+			end = lineEnd(w.src, end+1)
+		}
+
+		title := strings.TrimSpace(inlineText(h, w.src))
+		anchor := headingAnchor(h)
 		spans = append(spans, headingSpan{
-			Node:  h,
-			Start: lineStart, // Use line start, not text start
-			End:   seg.Stop,
-			Level: h.Level,
-			Title: title,
+			Node:     h,
+			Start:    lineStart, // Use line start, not text start
+			End:      end,
+			Text:     last.Stop,
+			Level:    h.Level,
+			Title:    title,
+			Meta:     headingMeta(h),
+			Anchor:   anchor,
+			Explicit: anchor != "" && !w.ids.generated[anchor],
 		})
 		logger.Debug("heading discovered",
 			slog.Int("level", h.Level),
@@ -173,24 +311,24 @@ func (w *worker) extractHeadings() {
 }
 
 func inlineText(h *ast.Heading, src []byte) string {
-	var buf bytes.Buffer
-	for n := h.FirstChild(); n != nil; n = n.NextSibling() {
-		switch t := n.(type) {
-		case *ast.Text:
-			buf.Write(t.Segment.Value(src))
-		default:
-			buf.WriteString(extractInlineText(t, src))
-		}
-	}
-	return buf.String()
+	return extractInlineText(h, src)
 }
 
+// extractInlineText returns the text of n's inline children. Strings produced
+// by extensions, such as typographic quotes, are decoded from HTML entities.
 func extractInlineText(n ast.Node, src []byte) string {
 	var buf bytes.Buffer
 	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
 		switch t := c.(type) {
 		case *ast.Text:
 			buf.Write(t.Segment.Value(src))
+			if t.SoftLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.WriteString(html.UnescapeString(string(t.Value)))
+		case *ast.AutoLink:
+			buf.Write(t.Label(src))
 		default:
 			buf.WriteString(extractInlineText(t, src))
 		}
@@ -208,6 +346,11 @@ func (w *worker) fold(docTitle string) error {
 
 	logger.Debug("starting section folding", slog.String("root_title", docTitle))
 
+	promoted := -1
+	if w.opts.promoteTitle {
+		promoted = w.leadingTitle()
+	}
+
 	for i, h := range w.spans {
 		// append pre-heading text to current section
 		if h.Start > w.cursor {
@@ -219,6 +362,26 @@ func (w *worker) fold(docTitle string) error {
 			w.cursor = next
 		}
 
+		// a promoted title heading stays in the root: it retitles the root and
+		// its line is kept as root content, so nothing is lost from the body
+		if i == promoted {
+			line, next := spliceText(w.src, h.Start, h.End)
+			if len(h.Meta) > 0 {
+				// drop the attribute block, keeping a setext underline
+				head, _ := spliceText(w.src, h.Start, h.Text)
+				rest, _ := spliceText(w.src, lineEnd(w.src, h.Text), h.End)
+				line = strings.TrimRight(head, " \t") + rest
+			}
+			w.root.AppendContent(line)
+			w.root.SetTitle(h.Title)
+			setMeta(w.root, h.Meta)
+			setAnchor(w.root, h)
+			w.sections = append(w.sections, w.root)
+			logger.Debug("promoted leading H1 to root", slog.String("title", h.Title))
+			w.cursor = next
+			continue
+		}
+
 		// find parent section for this heading level
 		pi, err := parentForLevel(w.stack, h.Level)
 		if err != nil {
@@ -236,7 +399,10 @@ func (w *worker) fold(docTitle string) error {
 
 		// create new section under parent
 		sec := parent.CreateChild(h.Title, h.Level, "")
+		setMeta(sec, h.Meta)
+		setAnchor(sec, h)
 		w.stack = append(w.stack, sectionFrame{s: sec})
+		w.sections = append(w.sections, sec)
 		logger.Debug("created section",
 			slog.String("title", h.Title),
 			slog.Int("level", h.Level),
@@ -257,8 +423,85 @@ func (w *worker) fold(docTitle string) error {
 	return nil
 }
 
+// leadingTitle returns the index of the document's only H1 if it is the first
+// thing in the document (ignoring whitespace), or -1 otherwise.
+func (w *worker) leadingTitle() int {
+	index := -1
+	for i, h := range w.spans {
+		if h.Level != 1 {
+			continue
+		}
+		if index >= 0 {
+			return -1 // more than one H1
+		}
+		index = i
+	}
+	if index != 0 || len(bytes.TrimSpace(w.src[:w.spans[0].Start])) > 0 {
+		return -1
+	}
+	return index
+}
+
 // --- Pure helpers ------------------------------------------------------------
 
+// headingMeta converts heading attributes other than the anchor id into
+// section metadata, or returns nil if there are none.
+func headingMeta(h *ast.Heading) map[string]string {
+	var meta map[string]string
+	for _, attr := range h.Attributes() {
+		name := string(attr.Name)
+		if name == "id" {
+			continue
+		}
+		var value string
+		if b, ok := attr.Value.([]byte); ok {
+			value = string(b)
+		} else {
+			value = fmt.Sprint(attr.Value)
+		}
+		if meta == nil {
+			meta = make(map[string]string)
+		}
+		meta[name] = value
+	}
+	return meta
+}
+
+// headingAnchor returns the heading's anchor ID, or "" if it has none.
+func headingAnchor(h *ast.Heading) string {
+	if id, ok := h.AttributeString("id"); ok {
+		if b, ok := id.([]byte); ok {
+			return string(b)
+		}
+	}
+	return ""
+}
+
+// setAnchor sets the heading's anchor ID on s, marking explicit ones.
+func setAnchor(s *section.Section, h headingSpan) {
+	if h.Explicit {
+		s.SetExplicitAnchor(h.Anchor)
+	} else {
+		s.SetAnchor(h.Anchor)
+	}
+}
+
+// setMeta sets each metadata value on s.
+func setMeta(s *section.Section, meta map[string]string) {
+	for k, v := range meta {
+		s.SetMeta(k, v)
+	}
+}
+
+// lineEnd returns the offset of the newline ending the line that contains pos,
+// or len(src) if it is the last line.
+func lineEnd(src []byte, pos int) int {
+	for pos < len(src) && src[pos] != '\n' {
+		pos++
+	}
+	return pos
+}
+
 func spliceText(src []byte, start, stop int) (string, int) {
 	if start < 0 {
 		start = 0
@@ -2,10 +2,13 @@ package parser
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"strings"
 	"testing"
 
 	chunkyctx "github.com/wyvernzora/chunky/pkg/context"
+	mdconfig "github.com/wyvernzora/chunky/pkg/markdown"
 	"github.com/wyvernzora/chunky/pkg/section"
 )
 
@@ -548,6 +551,16 @@ func TestInlineTextExtraction(t *testing.T) {
 			"# [Link Text](http://example.com)",
 			"Link Text",
 		},
+		{
+			"bare URL",
+			"# See https://example.com",
+			"See https://example.com",
+		},
+		{
+			"strikethrough",
+			"# ~~Old~~ New",
+			"Old New",
+		},
 	}
 
 	for _, tt := range tests {
@@ -661,3 +674,252 @@ Trailing line after second H1.
 		t.Fatalf("'Second H1' content missing trailing lines, got: %q", sc)
 	}
 }
+
+func TestParserPromotedTitle(t *testing.T) {
+	markdown := `
+# Getting Started
+
+Intro text.
+
+## Install
+
+Install steps.
+
+### Linux
+
+Linux steps.
+
+## Usage
+
+Usage text.`
+
+	ctx := chunkyctx.WithFileInfo(context.Background(), chunkyctx.FileInfo{Title: "getting-started"})
+	root, _, err := NewParser(WithPromotedTitle())(ctx, []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if root.Title() != "Getting Started" {
+		t.Errorf("expected root title 'Getting Started', got %q", root.Title())
+	}
+	if root.Level() != 0 {
+		t.Errorf("expected root level 0, got %d", root.Level())
+	}
+	if content := strings.TrimSpace(root.Content()); content != "# Getting Started\n\nIntro text." {
+		t.Errorf("expected heading line and intro in root content, got %q", content)
+	}
+
+	children := root.Children()
+	if len(children) != 2 {
+		t.Fatalf("expected 2 lifted children, got %d", len(children))
+	}
+	if children[0].Title() != "Install" || children[0].Level() != 2 || children[0].Parent() != root {
+		t.Errorf("unexpected first child: %q level %d", children[0].Title(), children[0].Level())
+	}
+	if grand := children[0].Children(); len(grand) != 1 || grand[0].Title() != "Linux" {
+		t.Errorf("expected Install to keep its Linux subsection, got %d children", len(grand))
+	}
+	if children[1].Title() != "Usage" {
+		t.Errorf("expected second child 'Usage', got %q", children[1].Title())
+	}
+}
+
+func TestParserPromotedTitle_NotApplicable(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+	}{
+		{"multiple H1s", "# One\n\nText\n\n# Two\n\nText"},
+		{"text before H1", "Preamble.\n\n# Title\n\nText"},
+		{"no H1", "## Section\n\nText"},
+		{"H1 after H2", "## Section\n\n# Title\n\nText"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := chunkyctx.WithFileInfo(context.Background(), chunkyctx.FileInfo{Title: "file"})
+			promoted, _, err := NewParser(WithPromotedTitle())(ctx, []byte(tt.markdown))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			plain, _, _ := DefaultParser(ctx, []byte(tt.markdown))
+
+			if promoted.Title() != "file" {
+				t.Errorf("expected root title to be kept, got %q", promoted.Title())
+			}
+			if promoted.Content() != plain.Content() || len(promoted.Children()) != len(plain.Children()) {
+				t.Error("expected tree to match DefaultParser")
+			}
+		})
+	}
+}
+
+func TestParserHeadingAttributes(t *testing.T) {
+	markdown := "# Guide\n\nIntro.\n\n## Install {#setup .beta .internal audience=admins}\n\nSteps.\n\n### Linux ###\n\nLinux steps.\n"
+
+	root, _, err := DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	install := root.Children()[0].Children()[0]
+	if install.Title() != "Install" {
+		t.Errorf("expected attributes removed from title, got %q", install.Title())
+	}
+	if strings.Contains(install.Content(), "{") {
+		t.Errorf("expected attributes removed from content, got %q", install.Content())
+	}
+	want := map[string]string{"class": "beta internal", "audience": "admins"}
+	if got := install.Metadata(); !maps.Equal(got, want) {
+		t.Errorf("expected metadata %v, got %v", want, got)
+	}
+
+	linux := install.Children()[0]
+	if linux.Title() != "Linux" || strings.Contains(linux.Content(), "#") {
+		t.Errorf("expected closing hashes removed, got title %q content %q", linux.Title(), linux.Content())
+	}
+	if got := linux.InheritedMetadata()["audience"]; got != "admins" {
+		t.Errorf("expected inherited audience, got %q", got)
+	}
+}
+
+func TestParserHeadingAttributes_PromotedTitle(t *testing.T) {
+	root, _, err := NewParser(WithPromotedTitle())(context.Background(), []byte("# Guide {product=pro}\n\nIntro.\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got, _ := root.Meta("product"); got != "pro" {
+		t.Errorf("expected root metadata product=pro, got %q", got)
+	}
+	if content := strings.TrimSpace(root.Content()); content != "# Guide\n\nIntro." {
+		t.Errorf("expected heading line without attributes, got %q", content)
+	}
+}
+
+func TestParserHeadingAnchors(t *testing.T) {
+	markdown := "# Guide\n\n## Install\n\n## Install\n\n## Über C++ `code`!\n\n## Setup {#custom-setup}\n"
+
+	root, _, err := DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	guide := root.Children()[0]
+	if guide.Anchor() != "guide" {
+		t.Errorf("expected anchor 'guide', got %q", guide.Anchor())
+	}
+	var got []string
+	var explicit []bool
+	for _, c := range guide.Children() {
+		got = append(got, c.Anchor())
+		explicit = append(explicit, c.ExplicitAnchor())
+	}
+	want := []string{"install", "install-1", "über-c-code", "custom-setup"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected anchors %v, got %v", want, got)
+	}
+	if !slices.Equal(explicit, []bool{false, false, false, true}) {
+		t.Errorf("expected only custom-setup to be explicit, got %v", explicit)
+	}
+	if root.Anchor() != "" {
+		t.Errorf("expected no root anchor, got %q", root.Anchor())
+	}
+
+	promoted, _, err := NewParser(WithPromotedTitle())(context.Background(), []byte("# Guide\n\nIntro.\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if promoted.Anchor() != "guide" {
+		t.Errorf("expected promoted root anchor 'guide', got %q", promoted.Anchor())
+	}
+}
+
+func TestParserSetextHeadings(t *testing.T) {
+	markdown := "Guide\n=====\n\nIntro.\n\nInstall the\ntool\n---\n\nSteps.\n"
+
+	root, _, err := DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	guide := root.Children()[0]
+	if guide.Title() != "Guide" || guide.Level() != 1 {
+		t.Errorf("expected level 1 'Guide', got level %d %q", guide.Level(), guide.Title())
+	}
+	if content := strings.TrimSpace(guide.Content()); content != "Intro." {
+		t.Errorf("expected underline removed from content, got %q", guide.Content())
+	}
+
+	install := guide.Children()[0]
+	if install.Title() != "Install the tool" || install.Level() != 2 {
+		t.Errorf("expected level 2 'Install the tool', got level %d %q", install.Level(), install.Title())
+	}
+	if content := strings.TrimSpace(install.Content()); content != "Steps." {
+		t.Errorf("expected underline removed from content, got %q", install.Content())
+	}
+
+	promoted, _, err := NewParser(WithPromotedTitle())(context.Background(), []byte("Guide {product=pro}\n=====\n\nIntro.\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if content := strings.TrimSpace(promoted.Content()); content != "Guide\n=====\n\nIntro." {
+		t.Errorf("expected promoted setext heading without attributes, got %q", content)
+	}
+}
+
+func TestParserContainerHeadings(t *testing.T) {
+	markdown := "# Guide\n\n> ## Quoted\n> More quote.\n\n- ## Item\n  Item text.\n\n## Real\n\nText.\n"
+
+	root, _, err := DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	guide := root.Children()[0]
+	if got := len(guide.Children()); got != 1 || guide.Children()[0].Title() != "Real" {
+		t.Fatalf("expected only 'Real' as a subsection, got %d children", got)
+	}
+	if !strings.Contains(guide.Content(), "> ## Quoted\n> More quote.") || !strings.Contains(guide.Content(), "- ## Item\n  Item text.") {
+		t.Errorf("expected nested headings kept in content, got %q", guide.Content())
+	}
+
+	root, _, err = NewParser(WithNestedHeadings())(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	var titles []string
+	for _, c := range root.Children()[0].Children() {
+		titles = append(titles, c.Title())
+	}
+	if want := []string{"Quoted", "Item", "Real"}; !slices.Equal(titles, want) {
+		t.Errorf("expected subsections %v, got %v", want, titles)
+	}
+}
+
+func TestParserMarkdownExtensions(t *testing.T) {
+	markdown := "# Guide\n\n| Key | Value |\n| --- | --- |\n| a | 1 |\n\nTerm\n: Definition\n\nText.[^1]\n\n[^1]: A note.\n\n## Don't \"Panic\"\n"
+
+	root, _, err := DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	guide := root.Children()[0]
+	if want := "\n\n| Key | Value |\n| --- | --- |\n| a | 1 |\n\nTerm\n: Definition\n\nText.[^1]\n\n[^1]: A note.\n\n"; guide.Content() != want {
+		t.Errorf("expected content %q, got %q", want, guide.Content())
+	}
+	if got := guide.Children()[0].Title(); got != `Don't "Panic"` {
+		t.Errorf("expected title to keep straight quotes, got %q", got)
+	}
+
+	md, err := mdconfig.New(mdconfig.Typographer)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx := chunkyctx.WithMarkdown(context.Background(), md)
+	root, _, err = DefaultParser(ctx, []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := root.Children()[0].Children()[0].Title(); got != "Don’t “Panic”" {
+		t.Errorf("expected typographic quotes in title, got %q", got)
+	}
+}
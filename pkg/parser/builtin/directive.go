@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	"github.com/wyvernzora/chunky/pkg/section"
+	"github.com/yuin/goldmark/ast"
+)
+
+// directiveRe matches a "<!-- chunky:name args -->" comment.
+var directiveRe = regexp.MustCompile(`(?s)^<!--\s*chunky:([A-Za-z-]+)(?:\s+(.*?))?\s*-->$`)
+
+// directive is a chunky directive comment found in the document body.
+type directive struct {
+	name  string
+	args  string
+	start int // byte offset where the directive's line begins
+	end   int // byte offset just past the directive's last line break
+	line  int // 1-based line number in the original markdown
+}
+
+// metaDirective is section metadata to attach to the section that contains pos.
+type metaDirective struct {
+	pos   int // byte offset in the rewritten source
+	key   string
+	value string
+}
+
+// findDirectives returns the chunky directives in document order. Only
+// top-level HTML blocks are considered, so directives must be on lines of
+// their own and are not recognized inside code blocks, lists or quotes.
+func (w *worker) findDirectives() []directive {
+	var out []directive
+	for n := w.doc.FirstChild(); n != nil; n = n.NextSibling() {
+		block, ok := n.(*ast.HTMLBlock)
+		if !ok || block.Lines().Len() == 0 {
+			continue
+		}
+
+		lines := block.Lines()
+		first, last := lines.At(0), lines.At(lines.Len()-1)
+		end := last.Stop
+		if block.HasClosure() {
+			end = block.ClosureLine.Stop
+		}
+		raw := bytes.TrimSpace(w.src[first.Start:end])
+		m := directiveRe.FindSubmatch(raw)
+		if m == nil {
+			continue
+		}
+
+		start := first.Start
+		for start > 0 && w.src[start-1] != '\n' {
+			start--
+		}
+		for end < len(w.src) && w.src[end-1] != '\n' {
+			end++
+		}
+		out = append(out, directive{
+			name:  string(m[1]),
+			args:  string(m[2]),
+			start: start,
+			end:   end,
+			line:  w.lineOffset + bytes.Count(w.src[:start], []byte("\n")) + 1,
+		})
+	}
+	return out
+}
+
+// applyDirectives rewrites the source according to the directives:
+//   - "ignore" ... "end" regions are removed, including any headings inside;
+//   - "break", "keep-together" and "end" become section directive markers,
+//     which the chunker honors and strips;
+//   - "meta key=value" comments are removed and returned for attachment.
+//
+// Unknown directives are removed with a warning. Unmatched or unterminated
+// regions are errors.
+func (w *worker) applyDirectives(dirs []directive) ([]byte, []metaDirective, error) {
+	logger := cctx.Logger(w.ctx)
+
+	var (
+		out    = make([]byte, 0, len(w.src))
+		metas  []metaDirective
+		open   []directive // unclosed "ignore" and "keep-together" directives
+		ignore = -1        // position in open of the outermost "ignore", or -1
+		cursor int
+	)
+	// replace copies the source up to start, writes repl in place of
+	// src[start:end] and returns the offset of repl in the output.
+	replace := func(start, end int, repl string) int {
+		out = append(out, w.src[cursor:start]...)
+		pos := len(out)
+		out = append(out, repl...)
+		cursor = end
+		return pos
+	}
+
+	for _, d := range dirs {
+		switch d.name {
+		case "ignore", "keep-together":
+			if ignore < 0 {
+				if d.name == "ignore" {
+					ignore = len(open)
+				} else {
+					replace(d.start, d.end, section.KeepTogetherMarker+"\n")
+				}
+			}
+			open = append(open, d)
+
+		case "end":
+			if len(open) == 0 {
+				return nil, nil, fmt.Errorf("line %d: chunky:end without a matching chunky:ignore or chunky:keep-together", d.line)
+			}
+			start := open[len(open)-1]
+			open = open[:len(open)-1]
+			switch {
+			case ignore == len(open):
+				// Closing the outermost ignore drops the whole region
+				replace(start.start, d.end, "")
+				ignore = -1
+				logger.Debug("ignored region removed",
+					slog.Int("from_line", start.line),
+					slog.Int("to_line", d.line))
+			case ignore < 0:
+				replace(d.start, d.end, section.EndMarker+"\n")
+			}
+
+		case "break":
+			if ignore < 0 {
+				replace(d.start, d.end, section.BreakMarker+"\n")
+			}
+
+		case "meta":
+			if ignore >= 0 {
+				continue
+			}
+			pairs, err := parseMetaArgs(d.args)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: chunky:meta: %w", d.line, err)
+			}
+			pos := replace(d.start, d.end, "")
+			for _, kv := range pairs {
+				metas = append(metas, metaDirective{pos: pos, key: kv[0], value: kv[1]})
+			}
+
+		default:
+			if ignore >= 0 {
+				continue
+			}
+			logger.Warn("unknown chunky directive removed",
+				slog.String("directive", d.name),
+				slog.Int("line", d.line))
+			replace(d.start, d.end, "")
+		}
+	}
+
+	if len(open) > 0 {
+		d := open[len(open)-1]
+		return nil, nil, fmt.Errorf("line %d: chunky:%s is never closed with chunky:end", d.line, d.name)
+	}
+
+	out = append(out, w.src[cursor:]...)
+	return out, metas, nil
+}
+
+// parseMetaArgs parses space-separated key=value pairs. Values may be double
+// quoted to include spaces, e.g. `audience="api users" tier=2`.
+func parseMetaArgs(args string) ([][2]string, error) {
+	var pairs [][2]string
+	rest := strings.TrimSpace(args)
+	if rest == "" {
+		return nil, fmt.Errorf("expected key=value")
+	}
+	for rest != "" {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t\n") {
+			return nil, fmt.Errorf("expected key=value, got %q", rest)
+		}
+
+		if strings.HasPrefix(value, `"`) {
+			quoted, err := strconv.QuotedPrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("unterminated quoted value for %q", key)
+			}
+			rest = value[len(quoted):]
+			if value, err = strconv.Unquote(quoted); err != nil {
+				return nil, fmt.Errorf("invalid quoted value for %q: %w", key, err)
+			}
+		} else {
+			value, rest, _ = strings.Cut(value, " ")
+		}
+
+		pairs = append(pairs, [2]string{key, value})
+		rest = strings.TrimSpace(rest)
+	}
+	return pairs, nil
+}
+
+// attachMeta sets each metadata directive on the section whose body contains it.
+func (w *worker) attachMeta(metas []metaDirective) {
+	for _, m := range metas {
+		owner := w.root
+		for i, h := range w.spans {
+			if h.Start > m.pos {
+				break
+			}
+			owner = w.sections[i]
+		}
+		owner.SetMeta(m.key, m.value)
+	}
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/wyvernzora/chunky/pkg/section"
+)
+
+func TestParserDirectives_Ignore(t *testing.T) {
+	markdown := `# Guide
+
+Keep this.
+
+<!-- chunky:ignore -->
+Internal notes.
+
+## Internal
+
+<!-- chunky:break -->
+Also hidden.
+<!-- chunky:end -->
+
+Keep this too.
+
+## Usage
+
+Run it.
+`
+	root, _, err := DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	guide := root.Children()[0]
+	var titles []string
+	for _, c := range guide.Children() {
+		titles = append(titles, c.Title())
+	}
+	if strings.Join(titles, ",") != "Usage" {
+		t.Errorf("expected ignored heading to be dropped, got children %v", titles)
+	}
+	if guide.Content() != "\n\nKeep this.\n\n\nKeep this too.\n\n" {
+		t.Errorf("unexpected content: %q", guide.Content())
+	}
+}
+
+func TestParserDirectives_Markers(t *testing.T) {
+	markdown := "# A\n\nOne.\n\n<!--chunky:break-->\n\nTwo.\n\n<!-- chunky:keep-together -->\n\n## B\n\nThree.\n\n  <!-- chunky:end -->  \n"
+	root, _, err := DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	a := root.Children()[0]
+	expected := "\n\nOne.\n\n" + section.BreakMarker + "\n\nTwo.\n\n" + section.KeepTogetherMarker + "\n\n"
+	if a.Content() != expected {
+		t.Errorf("unexpected content of A:\n got: %q\nwant: %q", a.Content(), expected)
+	}
+	b := a.Children()[0]
+	if b.Content() != "\n\nThree.\n\n"+section.EndMarker+"\n" {
+		t.Errorf("unexpected content of B: %q", b.Content())
+	}
+}
+
+func TestParserDirectives_Meta(t *testing.T) {
+	markdown := `<!-- chunky:meta audience=all -->
+
+# API
+
+<!-- chunky:meta audience="api users" tier=2 -->
+Reference.
+
+## Errors
+
+Codes.
+<!-- chunky:meta stability=beta -->
+`
+	root, _, err := DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if v, _ := root.Meta("audience"); v != "all" {
+		t.Errorf("expected root audience 'all', got %q", v)
+	}
+	api := root.Children()[0]
+	if v, _ := api.Meta("audience"); v != "api users" {
+		t.Errorf("expected API audience 'api users', got %q", v)
+	}
+	if v, _ := api.Meta("tier"); v != "2" {
+		t.Errorf("expected API tier '2', got %q", v)
+	}
+	if strings.Contains(api.Content(), "chunky:") {
+		t.Errorf("expected meta directive to be removed, got %q", api.Content())
+	}
+	errs := api.Children()[0]
+	if md := errs.Metadata(); len(md) != 1 || md["stability"] != "beta" {
+		t.Errorf("expected Errors metadata {stability: beta}, got %v", md)
+	}
+}
+
+func TestParserDirectives_NotInCode(t *testing.T) {
+	markdown := "# Docs\n\n```markdown\n<!-- chunky:ignore -->\n```\n\n- item\n\n  <!-- chunky:break -->\n"
+	root, _, err := DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	content := root.Children()[0].Content()
+	if !strings.Contains(content, "<!-- chunky:ignore -->") || !strings.Contains(content, "  <!-- chunky:break -->") {
+		t.Errorf("expected directives in code and lists to be left alone, got %q", content)
+	}
+}
+
+func TestParserDirectives_Unknown(t *testing.T) {
+	markdown := "# A\n\nText.\n\n<!-- chunky:frobnicate -->\n\nMore.\n"
+	root, _, err := DefaultParser(context.Background(), []byte(markdown))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if content := root.Children()[0].Content(); strings.Contains(content, "chunky:") {
+		t.Errorf("expected unknown directive to be removed, got %q", content)
+	}
+}
+
+func TestParserDirectives_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		wantErr  string
+	}{
+		{"unmatched end", "Text.\n\n<!-- chunky:end -->\n", "line 3: chunky:end without a matching"},
+		{"unclosed ignore", "Text.\n\n<!-- chunky:ignore -->\n\nMore.\n", "line 3: chunky:ignore is never closed"},
+		{"unclosed keep-together", "<!-- chunky:keep-together -->\n", "line 1: chunky:keep-together is never closed"},
+		{"bad meta", "<!-- chunky:meta audience -->\n", "line 1: chunky:meta: expected key=value"},
+		{"unterminated quote", "<!-- chunky:meta a=\"b -->\n", "unterminated quoted value"},
+		{"line after frontmatter", "---\ntitle: x\n---\n\n<!-- chunky:end -->\n", "line 5:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := DefaultParser(context.Background(), []byte(tt.markdown))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
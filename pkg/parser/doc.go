@@ -24,9 +24,25 @@
 //
 // The builtin.DefaultParser provides:
 //   - YAML frontmatter extraction
-//   - Hierarchical section tree based on heading levels
+//   - Hierarchical section tree based on ATX and setext heading levels
 //   - Content preservation with proper nesting
 //   - Support for documents without frontmatter
+//   - Inline "<!-- chunky:... -->" directives
+//   - GitHub-style heading anchor IDs, honoring explicit "{#id}" attributes
+//
+// Directives are HTML comments on their own line. "ignore" ... "end" regions
+// are removed and "meta key=value" pairs are attached to the enclosing section
+// (see Section.Meta). "break", "keep-together" and "end" are kept in section
+// content as canonical marker lines (section.BreakMarker and friends) for the
+// chunker to honor and strip.
+//
+// builtin.NewParser builds a parser with options. WithPromotedTitle makes a
+// lone leading H1 the root of the tree instead of its only child:
+//
+//	p := builtin.NewParser(builtin.WithPromotedTitle())
+//
+// Headings inside block quotes and list items are left in the content of the
+// enclosing section; WithNestedHeadings makes them start sections instead.
 //
 // # Usage Example
 //
@@ -6,17 +6,17 @@ import (
 	"regexp"
 	"sort"
 
+	cctx "github.com/wyvernzora/chunky/pkg/context"
 	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
 	"github.com/wyvernzora/chunky/pkg/section"
-	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
-	gparser "github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
 )
 
 // CollapseBlankLinesTransform returns a transform that collapses 3+ consecutive
 // blank lines into exactly two blank lines, preserving verbatim content inside
-// fenced/code blocks. It uses goldmark to accurately identify code block boundaries.
+// fenced/code blocks. It uses goldmark, configured from the context (see
+// context.Markdown), to accurately identify code block boundaries.
 //
 // The transform is idempotent: applying it multiple times produces the same result.
 //
@@ -29,17 +29,14 @@ func CollapseBlankLinesTransform() section.Transform {
 	}
 }
 
-func collapseBlankLinesImpl(_ context.Context, s *section.Section) error {
+func collapseBlankLinesImpl(ctx context.Context, s *section.Section) error {
 	src := []byte(s.Content())
 	if len(src) == 0 {
 		return nil
 	}
 
 	// Parse with goldmark to obtain accurate block boundaries.
-	md := goldmark.New(goldmark.WithParserOptions(
-		gparser.WithAutoHeadingID(), // harmless here
-	))
-	doc := md.Parser().Parse(text.NewReader(src))
+	doc := cctx.Markdown(ctx).Parser().Parse(text.NewReader(src))
 
 	// Exclusion ranges (byte offsets) for blocks where we must NOT edit.
 	exclude := collectNoEditRanges(doc)
@@ -0,0 +1,59 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+	"github.com/wyvernzora/chunky/pkg/tokenizer"
+)
+
+// MergeTinySectionsTransform merges sections whose heading and content measure
+// fewer than minTokens tokens into the previous sibling, or into the parent
+// if that sibling has subsections. Merged headings stay inline as Markdown heading
+// lines (see Section.MergeIntoParent), so no text is lost.
+//
+// Only sections without subsections are merged; sections are visited bottom-up,
+// so a section whose subsections were all merged into it may be merged in turn.
+// The root section, sections with metadata of their own (see Section.Meta) and
+// sections with an explicit "{#id}" anchor (see Section.ExplicitAnchor) are
+// never merged, since merging would drop that metadata or break links to the
+// anchor. A minTokens of 0
+// or less disables merging.
+//
+// Use the same tokenizer as the chunker so that the threshold is measured in
+// the units of the chunk budget.
+func MergeTinySectionsTransform(tok tokenizer.Tokenizer, minTokens int) section.TreeTransform {
+	return func(ctx context.Context, _ fm.FrontMatterView, root *section.Section) error {
+		if minTokens <= 0 {
+			return nil
+		}
+		return mergeTiny(ctx, root, tok, minTokens)
+	}
+}
+
+func mergeTiny(ctx context.Context, s *section.Section, tok tokenizer.Tokenizer, minTokens int) error {
+	for _, c := range s.Children() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := mergeTiny(ctx, c, tok, minTokens); err != nil {
+			return err
+		}
+	}
+	if s.IsRoot() || len(s.Children()) > 0 || s.Metadata() != nil || s.ExplicitAnchor() {
+		return nil
+	}
+
+	text := strings.Repeat("#", s.Level()) + " " + s.Title() + "\n\n" + s.Content()
+	tokens, err := tok.Count(text)
+	if err != nil {
+		return fmt.Errorf("failed to count tokens in section %q: %w", s.Title(), err)
+	}
+	if tokens >= minTokens {
+		return nil
+	}
+	return s.MergeIntoParent()
+}
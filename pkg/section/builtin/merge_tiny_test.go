@@ -0,0 +1,109 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+	tbuiltin "github.com/wyvernzora/chunky/pkg/tokenizer/builtin"
+)
+
+func TestMergeTinySectionsTransform(t *testing.T) {
+	root := section.NewRoot("API")
+	fn := root.CreateChild("Open", 2, "Opens a file at the given path and returns a handle for reading.\n")
+	fn.CreateChild("Returns", 3, "A handle.\n")
+	fn.CreateChild("Throws", 3, "NotFound.\n")
+	fn.CreateChild("Example", 3, "Call open with the path of an existing file, then read from the handle until it is exhausted.\n")
+	root.CreateChild("Close", 2, "Closes it.\n")
+
+	tok := tbuiltin.NewWordCountTokenizer()
+	transform := MergeTinySectionsTransform(tok, 8)
+	if err := section.ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, transform); err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+
+	if got := childTitles(fn); got != "Example" {
+		t.Errorf("expected only Example to remain under Open, got %q", got)
+	}
+	expected := "Opens a file at the given path and returns a handle for reading.\n\n### Returns\n\nA handle.\n\n### Throws\n\nNotFound.\n"
+	if fn.Content() != expected {
+		t.Errorf("unexpected merged content:\n got: %q\nwant: %q", fn.Content(), expected)
+	}
+
+	// Close is tiny, but Open has subsections, so it joins the root instead
+	if got := childTitles(root); got != "Open" {
+		t.Errorf("expected only Open under root, got %q", got)
+	}
+	if root.Content() != "## Close\n\nCloses it.\n" {
+		t.Errorf("unexpected root content: %q", root.Content())
+	}
+	example := fn.Children()[0]
+	if example.Content() != "Call open with the path of an existing file, then read from the handle until it is exhausted.\n" {
+		t.Errorf("expected Example unchanged, got %q", example.Content())
+	}
+}
+
+func TestMergeTinySectionsTransform_KeepsSectionsWithChildren(t *testing.T) {
+	root := section.NewRoot("Doc")
+	params := root.CreateChild("Parameters", 2, "")
+	params.CreateChild("path", 3, "The path of the file to open, relative to the working directory.\n")
+
+	transform := MergeTinySectionsTransform(tbuiltin.NewWordCountTokenizer(), 5)
+	if err := section.ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, transform); err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+
+	if got := childTitles(root); got != "Parameters" {
+		t.Errorf("expected Parameters to be kept, got %q", got)
+	}
+	if got := childTitles(params); got != "path" {
+		t.Errorf("expected path to be kept, got %q", got)
+	}
+}
+
+func TestMergeTinySectionsTransform_Disabled(t *testing.T) {
+	root := section.NewRoot("Doc")
+	root.CreateChild("A", 1, "a\n")
+
+	transform := MergeTinySectionsTransform(tbuiltin.NewWordCountTokenizer(), 0)
+	if err := section.ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, transform); err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+	if got := childTitles(root); got != "A" {
+		t.Errorf("expected no merges, got %q", got)
+	}
+}
+
+func TestMergeTinySectionsTransform_KeepsSectionsWithMetadata(t *testing.T) {
+	root := section.NewRoot("Doc")
+	a := root.CreateChild("A", 1, "Some content that is long enough to stay.\n")
+	tagged := a.CreateChild("Admin", 2, "Tiny.\n")
+	tagged.SetMeta("audience", "admins")
+	a.CreateChild("Other", 2, "Tiny.\n")
+
+	transform := MergeTinySectionsTransform(tbuiltin.NewWordCountTokenizer(), 8)
+	if err := section.ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, transform); err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+
+	if got := childTitles(a); got != "Admin" {
+		t.Errorf("expected Admin to keep its section, got %q", got)
+	}
+}
+
+func TestMergeTinySectionsTransform_KeepsExplicitAnchors(t *testing.T) {
+	root := section.NewRoot("Doc")
+	a := root.CreateChild("A", 1, "Some content that is long enough to stay.\n")
+	a.CreateChild("Setup", 2, "Tiny.\n").SetExplicitAnchor("setup")
+	a.CreateChild("Other", 2, "Tiny.\n").SetAnchor("other")
+
+	transform := MergeTinySectionsTransform(tbuiltin.NewWordCountTokenizer(), 8)
+	if err := section.ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, transform); err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+
+	if got := childTitles(a); got != "Setup" {
+		t.Errorf("expected Setup to keep its section, got %q", got)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"sort"
+	"unicode/utf8"
 
+	"github.com/wyvernzora/chunky/internal/cjk"
+	cctx "github.com/wyvernzora/chunky/pkg/context"
 	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
 	"github.com/wyvernzora/chunky/pkg/section"
-	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/text"
 )
@@ -15,7 +17,9 @@ import (
 // NormalizeHardWrapsTransform returns a transform that collapses single newlines
 // inside paragraph blocks into a single space, preserving blank-line paragraph breaks
 // and leaving code blocks (and any non-paragraph blocks) untouched. It uses goldmark
-// to accurately identify paragraph boundaries.
+// to accurately identify paragraph boundaries, with the configuration from the
+// context (see context.Markdown), so GFM tables and other structural blocks are
+// never reflowed.
 //
 // The transform is idempotent: applying it multiple times produces the same result.
 //
@@ -23,6 +27,7 @@ import (
 //   - "Line 1\nLine 2"           -> "Line 1 Line 2"
 //   - "Line 1\n   Line 2"        -> "Line 1 Line 2"
 //   - "Line 1\n\nLine 2"         -> unchanged (paragraph break)
+//   - "日本語の\n文章"            -> "日本語の文章" (no space between CJK characters)
 //
 // Outside paragraphs (e.g., fenced code), content is not modified.
 func NormalizeHardWrapsTransform() section.Transform {
@@ -31,14 +36,13 @@ func NormalizeHardWrapsTransform() section.Transform {
 	}
 }
 
-func normalizeHardWrapsImpl(_ context.Context, s *section.Section) error {
+func normalizeHardWrapsImpl(ctx context.Context, s *section.Section) error {
 	src := []byte(s.Content())
 	if len(src) == 0 {
 		return nil
 	}
 
-	md := goldmark.New()
-	doc := md.Parser().Parse(text.NewReader(src))
+	doc := cctx.Markdown(ctx).Parser().Parse(text.NewReader(src))
 
 	// Collect paragraph spans (byte ranges) to edit.
 	type span struct{ start, end int }
@@ -98,6 +102,8 @@ func normalizeHardWrapsImpl(_ context.Context, s *section.Section) error {
 
 // joinSingleNewlines replaces single '\n' (optionally followed by spaces/tabs) that
 // are NOT part of a blank-line paragraph break (i.e., not '\n\n') with a single space.
+// When the characters on both sides of the break are CJK, the lines are joined
+// without a space, since Chinese and Japanese do not separate words with spaces.
 //
 // No regex lookbehind in Go, so we do a single pass with local context.
 func joinSingleNewlines(b []byte) []byte {
@@ -132,7 +138,11 @@ func joinSingleNewlines(b []byte) []byte {
 		// Single hard wrap inside a paragraph: replace newline + following indent with a space.
 		// Emit one space (avoid doubling if there is already a space at end).
 		if len(out) == 0 || out[len(out)-1] != ' ' {
-			out = append(out, ' ')
+			prev, _ := utf8.DecodeLastRune(out)
+			next, _ := utf8.DecodeRune(b[j:])
+			if !cjk.IsWide(prev) || !cjk.IsWide(next) {
+				out = append(out, ' ')
+			}
 		}
 		i = j // skip newline and any following spaces/tabs
 	}
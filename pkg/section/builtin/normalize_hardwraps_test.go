@@ -4,7 +4,9 @@ import (
 	"context"
 	"testing"
 
+	cctx "github.com/wyvernzora/chunky/pkg/context"
 	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/markdown"
 	"github.com/wyvernzora/chunky/pkg/section"
 )
 
@@ -64,6 +66,46 @@ func TestNormalizeHardWrapsTransform(t *testing.T) {
 			input:    "Text line\n# Heading",
 			expected: "Text line\n# Heading",
 		},
+		{
+			name:     "join chinese lines without space",
+			input:    "这是一个很长的\n句子。",
+			expected: "这是一个很长的句子。",
+		},
+		{
+			name:     "join japanese lines without space",
+			input:    "これは長い\n  文章です。",
+			expected: "これは長い文章です。",
+		},
+		{
+			name:     "join after CJK punctuation without space",
+			input:    "最初の文。\n次の文。",
+			expected: "最初の文。次の文。",
+		},
+		{
+			name:     "join korean lines with space",
+			input:    "안녕하세요\n세계",
+			expected: "안녕하세요 세계",
+		},
+		{
+			name:     "join CJK and latin with space",
+			input:    "使用\nChunky\n工具",
+			expected: "使用 Chunky 工具",
+		},
+		{
+			name:     "preserve GFM tables",
+			input:    "| Key | Value |\n| --- | --- |\n| a | 1 |\n| b | 2 |\n\nText after\nwrapped.",
+			expected: "| Key | Value |\n| --- | --- |\n| a | 1 |\n| b | 2 |\n\nText after wrapped.",
+		},
+		{
+			name:     "preserve definition lists",
+			input:    "Term\n: Definition that\n  wraps.",
+			expected: "Term\n: Definition that\n  wraps.",
+		},
+		{
+			name:     "join wrapped footnote definitions",
+			input:    "Text.[^1]\n\n[^1]: A note that\n    wraps.",
+			expected: "Text.[^1]\n\n[^1]: A note that wraps.",
+		},
 		{
 			name:     "empty content",
 			input:    "",
@@ -97,3 +139,21 @@ func TestNormalizeHardWrapsTransform(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeHardWrapsTransform_ContextMarkdown(t *testing.T) {
+	// Without GFM the table is a paragraph and gets reflowed
+	md, err := markdown.New()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := cctx.WithMarkdown(context.Background(), md)
+
+	s := section.NewRoot("Test")
+	s.SetContent("| Key | Value |\n| --- | --- |\n| a | 1 |")
+	if err := NormalizeHardWrapsTransform()(ctx, fm.EmptyFrontMatter().View(), s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "| Key | Value | | --- | --- | | a | 1 |"; s.Content() != want {
+		t.Errorf("expected %q, got %q", want, s.Content())
+	}
+}
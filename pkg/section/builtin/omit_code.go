@@ -0,0 +1,82 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/markdown"
+	"github.com/wyvernzora/chunky/pkg/section"
+	"github.com/wyvernzora/chunky/pkg/tokenizer"
+	"github.com/yuin/goldmark/text"
+)
+
+// DefaultCodePlaceholder is a placeholder for OmitLargeCodeBlocksTransform.
+const DefaultCodePlaceholder = "_Code sample omitted ({lines} lines)._"
+
+// OmitLargeCodeBlocksTransform returns a transform that removes fenced code
+// blocks measuring more than maxTokens tokens, fences included. Long generated
+// listings and data dumps rarely help retrieval and would otherwise fill
+// whole chunks.
+//
+// Each removed block is replaced by the placeholder line, in which "{language}"
+// and "{lines}" are replaced with the block's language tag and number of code
+// lines, e.g. DefaultCodePlaceholder. An empty placeholder drops blocks without
+// a trace. Only top-level blocks are considered (see markdown.CodeBlocks).
+// A maxTokens of 0 or less disables the transform.
+//
+// Use the same tokenizer as the chunker so that the threshold is measured in
+// the units of the chunk budget.
+func OmitLargeCodeBlocksTransform(tok tokenizer.Tokenizer, maxTokens int, placeholder string) section.Transform {
+	return func(ctx context.Context, _ fm.FrontMatterView, s *section.Section) error {
+		if maxTokens <= 0 {
+			return nil
+		}
+		src := []byte(s.Content())
+		if len(src) == 0 {
+			return nil
+		}
+		doc := cctx.Markdown(ctx).Parser().Parse(text.NewReader(src))
+
+		var out strings.Builder
+		cursor, omitted := 0, 0
+		for _, b := range markdown.CodeBlocks(doc, src) {
+			tokens, err := tok.Count(string(src[b.Start:b.End]))
+			if err != nil {
+				return fmt.Errorf("failed to count tokens in code block of section %q: %w", s.Title(), err)
+			}
+			if tokens <= maxTokens {
+				continue
+			}
+			out.Write(src[cursor:b.Start])
+			cursor = b.End
+			omitted++
+			if placeholder == "" {
+				// Drop the blank line after the block along with it
+				if rest := src[cursor:]; len(rest) > 0 && rest[0] == '\n' {
+					cursor++
+				}
+				continue
+			}
+			out.WriteString(strings.NewReplacer(
+				"{language}", b.Language,
+				"{lines}", strconv.Itoa(len(b.Lines)),
+			).Replace(placeholder))
+			out.WriteString("\n")
+		}
+		if omitted == 0 {
+			return nil
+		}
+		out.Write(src[cursor:])
+		s.SetContent(out.String())
+
+		cctx.Logger(ctx).Debug("omitted large code blocks",
+			slog.String("section", s.Title()),
+			slog.Int("count", omitted))
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+	tbuiltin "github.com/wyvernzora/chunky/pkg/tokenizer/builtin"
+)
+
+func TestOmitLargeCodeBlocksTransform(t *testing.T) {
+	large := "```json\n{\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3\n}\n```\n"
+	tests := []struct {
+		name        string
+		input       string
+		placeholder string
+		expected    string
+	}{
+		{
+			name:        "large block replaced",
+			input:       "Sample:\n\n" + large + "\nAfter.",
+			placeholder: "_{language} sample omitted ({lines} lines)_",
+			expected:    "Sample:\n\n_json sample omitted (5 lines)_\n\nAfter.",
+		},
+		{
+			name:        "large block dropped",
+			input:       "Sample:\n\n" + large + "\nAfter.",
+			placeholder: "",
+			expected:    "Sample:\n\nAfter.",
+		},
+		{
+			name:        "small block kept",
+			input:       "```sh\nmake\n```\n",
+			placeholder: DefaultCodePlaceholder,
+			expected:    "```sh\nmake\n```\n",
+		},
+		{
+			name:        "nested blocks kept",
+			input:       "> " + large[:8] + "> 1 2 3 4 5 6 7 8\n> ```\n",
+			placeholder: DefaultCodePlaceholder,
+			expected:    "> " + large[:8] + "> 1 2 3 4 5 6 7 8\n> ```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := section.NewRoot("Test")
+			s.SetContent(tt.input)
+
+			transform := OmitLargeCodeBlocksTransform(tbuiltin.NewWordCountTokenizer(), 6, tt.placeholder)
+			if err := transform(context.Background(), fm.EmptyFrontMatter().View(), s); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s.Content() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, s.Content())
+			}
+		})
+	}
+}
@@ -0,0 +1,504 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+)
+
+// bracketRe matches bracketed text, optionally followed by a second
+// bracketed reference label.
+var bracketRe = regexp.MustCompile(`\[([^\[\]]*)\](?:\[([^\[\]]*)\])?`)
+
+// ResolveReferencesTransform returns a transform that makes every section
+// self-contained with respect to reference-style links and footnotes, whose
+// definitions usually sit at the bottom of a document and would otherwise be
+// missing from the chunks that use them.
+//
+// Reference links ("[text][label]", "[label][]" and "[label]") are rewritten
+// as inline links with the destination and title of their definition, and
+// the definitions they used are removed. A footnote definition is copied to
+// the end of every other section citing it, and removed from its own section
+// unless that section cites it too. Definitions that are never used are left
+// in place.
+//
+// The sections are parsed together with the goldmark configuration from the
+// context, so definitions are recognized exactly where the document defines
+// them. Footnotes are appended per section: when a section is split across
+// chunks, only the chunk holding its end carries them. The added text is
+// counted against the budget like any other content. Apply it before
+// transforms that move content between sections, such as
+// MergeTinySectionsTransform.
+//
+// Link definitions inside block quotes and list items are used for inlining
+// but left in place.
+func ResolveReferencesTransform() section.TreeTransform {
+	return func(ctx context.Context, _ fm.FrontMatterView, root *section.Section) error {
+		var sections []*section.Section
+		var collect func(s *section.Section)
+		collect = func(s *section.Section) {
+			sections = append(sections, s)
+			for _, c := range s.Children() {
+				collect(c)
+			}
+		}
+		collect(root)
+
+		d := parseReferences(cctx.Markdown(ctx), sections)
+		footnotes := d.footnotes()
+		if len(d.pc.References()) == 0 && len(footnotes) == 0 {
+			return nil
+		}
+
+		edits, used := d.linkEdits()
+		edits = append(edits, d.definitionEdits(used)...)
+
+		// Locate the cited footnotes and remove those whose own section
+		// does not cite them
+		cited := d.citations()
+		type footnote struct {
+			label      []byte
+			start, end int
+		}
+		located := make(map[int]footnote)
+		for index, f := range footnotes {
+			start, end, ok := d.footnoteExtent(f)
+			if !ok {
+				continue
+			}
+			located[index] = footnote{label: f.Ref, start: start, end: end}
+			if !slices.Contains(cited[d.sectionAt(start)], index) {
+				edits = append(edits, refEdit{start: start, end: end})
+			}
+		}
+		sort.SliceStable(edits, func(i, j int) bool {
+			if edits[i].start != edits[j].start {
+				return edits[i].start < edits[j].start
+			}
+			return edits[i].end > edits[j].end
+		})
+
+		resolved := 0
+		for i, s := range sections {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			content := d.rewrite(d.starts[i], d.sectionEnd(i), edits)
+
+			var notes strings.Builder
+			for _, index := range cited[i] {
+				f, ok := located[index]
+				if !ok || d.definesFootnote(i, f.label, f.start) {
+					continue
+				}
+				notes.WriteString(strings.TrimRight(d.rewrite(f.start, f.end, edits), " \t\r\n"))
+				notes.WriteString("\n")
+			}
+			if notes.Len() > 0 {
+				content = strings.TrimRight(content, "\n")
+				if content != "" {
+					content += "\n\n"
+				}
+				content += notes.String()
+			}
+
+			if content != s.Content() {
+				s.SetContent(content)
+				resolved++
+			}
+		}
+
+		cctx.Logger(ctx).Debug("resolved references",
+			slog.Int("links", len(used)),
+			slog.Int("sections", resolved))
+		return nil
+	}
+}
+
+// refDocument is the content of all sections joined into one markdown source
+// and parsed at once, so that references resolve across sections as they do
+// in the document.
+type refDocument struct {
+	md       goldmark.Markdown
+	src      []byte
+	root     ast.Node
+	pc       parser.Context
+	sections []*section.Section
+	starts   []int  // offset of each section's content in src
+	lines    []int  // offset of each line in src
+	covered  []bool // per line: holds text of a block of the parsed tree
+}
+
+// refEdit replaces a range of the joined source. Edits without text remove
+// whole lines.
+type refEdit struct {
+	start, end int
+	text       string
+}
+
+// parseReferences joins the content of the sections, separated by blank
+// lines, and parses it.
+func parseReferences(md goldmark.Markdown, sections []*section.Section) *refDocument {
+	d := &refDocument{md: md, sections: sections, pc: parser.NewContext()}
+
+	var b bytes.Buffer
+	for _, s := range sections {
+		d.starts = append(d.starts, b.Len())
+		b.WriteString(s.Content())
+		if s.Content() != "" && !strings.HasSuffix(s.Content(), "\n") {
+			b.WriteByte('\n')
+		}
+		b.WriteByte('\n')
+	}
+	d.src = b.Bytes()
+	d.root = md.Parser().Parse(text.NewReader(d.src), parser.WithContext(d.pc))
+
+	d.lines = []int{0}
+	for i, c := range d.src {
+		if c == '\n' && i+1 < len(d.src) {
+			d.lines = append(d.lines, i+1)
+		}
+	}
+	d.covered = make([]bool, len(d.lines))
+	_ = ast.Walk(d.root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Type() != ast.TypeBlock {
+			return ast.WalkContinue, nil
+		}
+		for i := 0; i < n.Lines().Len(); i++ {
+			seg := n.Lines().At(i)
+			for l := d.lineOf(seg.Start); l <= d.lineOf(max(seg.Stop-1, seg.Start)); l++ {
+				d.covered[l] = true
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return d
+}
+
+// lineOf returns the index of the line holding the offset.
+func (d *refDocument) lineOf(pos int) int {
+	return sort.Search(len(d.lines), func(i int) bool { return d.lines[i] > pos }) - 1
+}
+
+// lineEnd returns the offset just past the line break of a line.
+func (d *refDocument) lineEnd(l int) int {
+	if l+1 < len(d.lines) {
+		return d.lines[l+1]
+	}
+	return len(d.src)
+}
+
+// line returns the text of a line without its line break.
+func (d *refDocument) line(l int) string {
+	return strings.TrimRight(string(d.src[d.lines[l]:d.lineEnd(l)]), "\r\n")
+}
+
+// sectionAt returns the index of the section holding the offset.
+func (d *refDocument) sectionAt(pos int) int {
+	return sort.Search(len(d.starts), func(i int) bool { return d.starts[i] > pos }) - 1
+}
+
+// sectionEnd returns the offset just past the content of a section.
+func (d *refDocument) sectionEnd(i int) int {
+	return d.starts[i] + len(d.sections[i].Content())
+}
+
+// linkEdits returns the edits rewriting reference links as inline links and
+// the normalized labels of the definitions they used.
+func (d *refDocument) linkEdits() ([]refEdit, map[string]bool) {
+	var prose []text.Segment
+	var skip [][2]int // code spans and raw HTML
+	_ = ast.Walk(d.root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock:
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeSpan:
+			if r, ok := d.codeSpanRange(n); ok {
+				skip = append(skip, r)
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.RawHTML:
+			if n.Segments.Len() > 0 {
+				skip = append(skip, [2]int{n.Segments.At(0).Start, n.Segments.At(n.Segments.Len() - 1).Stop})
+			}
+		}
+		if n.Type() == ast.TypeBlock {
+			prose = append(prose, n.Lines().Sliced(0, n.Lines().Len())...)
+		}
+		return ast.WalkContinue, nil
+	})
+	skipped := func(pos int) bool {
+		return slices.ContainsFunc(skip, func(r [2]int) bool { return pos >= r[0] && pos < r[1] })
+	}
+
+	var edits []refEdit
+	used := make(map[string]bool)
+	for _, seg := range prose {
+		line := string(seg.Value(d.src))
+		for _, m := range bracketRe.FindAllStringSubmatchIndex(line, -1) {
+			start, end := seg.Start+m[0], seg.Start+m[1]
+			if (m[0] > 0 && line[m[0]-1] == '\\') || skipped(start) {
+				continue
+			}
+			linkText := line[m[2]:m[3]]
+			var label string
+			switch {
+			case m[4] >= 0 && m[4] < m[5]:
+				label = line[m[4]:m[5]] // [text][label]
+			case m[4] >= 0:
+				label = linkText // [label][]
+			case m[1] < len(line) && line[m[1]] == '(':
+				continue // text of an inline link
+			default:
+				label = linkText // [label]
+			}
+			if strings.HasPrefix(label, "^") {
+				continue // footnote citation
+			}
+			key := util.ToLinkReference([]byte(label))
+			ref, ok := d.pc.Reference(key)
+			if key == "" || !ok {
+				continue
+			}
+			used[key] = true
+			edits = append(edits, refEdit{start: start, end: end, text: "[" + linkText + "](" + inlineTarget(ref) + ")"})
+		}
+	}
+	return edits, used
+}
+
+// codeSpanRange returns the range of a code span including its backticks.
+func (d *refDocument) codeSpanRange(n *ast.CodeSpan) ([2]int, bool) {
+	first, ok := n.FirstChild().(*ast.Text)
+	last, ok2 := n.LastChild().(*ast.Text)
+	if !ok || !ok2 {
+		return [2]int{}, false
+	}
+	start, end := first.Segment.Start, last.Segment.Stop
+	if start > 1 && d.src[start-1] == ' ' && d.src[start-2] == '`' {
+		start--
+	}
+	for start > 0 && d.src[start-1] == '`' {
+		start--
+	}
+	if end+1 < len(d.src) && d.src[end] == ' ' && d.src[end+1] == '`' {
+		end++
+	}
+	for end < len(d.src) && d.src[end] == '`' {
+		end++
+	}
+	return [2]int{start, end}, true
+}
+
+// definitionEdits returns the edits removing the link reference definitions
+// whose label is used. Definitions are the lines that no block of the parsed
+// tree covers and that parse into nothing but a single definition.
+func (d *refDocument) definitionEdits(used map[string]bool) []refEdit {
+	var edits []refEdit
+	for l := 0; l < len(d.lines); l++ {
+		if d.covered[l] || !isDefinitionStart(d.line(l)) || strings.HasPrefix(strings.TrimLeft(d.line(l), " "), "[^") {
+			continue
+		}
+		// A definition may continue on the following lines
+		sec := d.sectionAt(d.lines[l])
+		last := l
+		for last+1 < len(d.lines) && !d.covered[last+1] && strings.TrimSpace(d.line(last+1)) != "" &&
+			!isDefinitionStart(d.line(last+1)) && d.sectionAt(d.lines[last+1]) == sec {
+			last++
+		}
+
+		start, end := d.lines[l], min(d.lineEnd(last), d.sectionEnd(sec))
+		pc := parser.NewContext()
+		doc := d.md.Parser().Parse(text.NewReader(d.src[start:end]), parser.WithContext(pc))
+		refs := pc.References()
+		if len(refs) == 1 && used[util.ToLinkReference(refs[0].Label())] && !hasText(doc) {
+			edits = append(edits, refEdit{start: start, end: end})
+		}
+		l = last
+	}
+	return edits
+}
+
+// isDefinitionStart reports whether a line may start a link reference
+// definition.
+func isDefinitionStart(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	return len(line)-len(trimmed) <= 3 && strings.HasPrefix(trimmed, "[")
+}
+
+// hasText reports whether any block of a parsed tree holds text.
+func hasText(doc ast.Node) bool {
+	found := false
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+			found = true
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return found
+}
+
+// footnotes returns the cited footnote definitions by index. Definitions
+// that are never cited are not part of the parsed tree.
+func (d *refDocument) footnotes() map[int]*east.Footnote {
+	footnotes := make(map[int]*east.Footnote)
+	_ = ast.Walk(d.root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if f, ok := n.(*east.Footnote); ok && entering && f.Index > 0 {
+			footnotes[f.Index] = f
+		}
+		return ast.WalkContinue, nil
+	})
+	return footnotes
+}
+
+// citations returns the indices of the footnotes cited by each section, in
+// order of first citation.
+func (d *refDocument) citations() map[int][]int {
+	cited := make(map[int][]int)
+	_ = ast.Walk(d.root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		link, ok := n.(*east.FootnoteLink)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		for p := n.Parent(); p != nil; p = p.Parent() {
+			if p.Type() == ast.TypeBlock && p.Lines().Len() > 0 {
+				sec := d.sectionAt(p.Lines().At(0).Start)
+				if !slices.Contains(cited[sec], link.Index) {
+					cited[sec] = append(cited[sec], link.Index)
+				}
+				break
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return cited
+}
+
+// footnoteExtent returns the range of whole lines holding a footnote
+// definition. Footnotes without content cannot be located.
+func (d *refDocument) footnoteExtent(f *east.Footnote) (int, int, bool) {
+	first, last := -1, -1
+	_ = ast.Walk(f, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+			if start := n.Lines().At(0).Start; first < 0 || start < first {
+				first = start
+			}
+			last = max(last, n.Lines().At(n.Lines().Len()-1).Stop)
+		}
+		return ast.WalkContinue, nil
+	})
+	if first < 0 {
+		return 0, 0, false
+	}
+
+	// The definition starts at its label, which may precede the first block
+	sec := d.sectionAt(first)
+	l := d.lineOf(first)
+	for !isFootnoteDefinition(d.line(l), f.Ref) {
+		l--
+		if l < 0 || strings.TrimSpace(d.line(l)) == "" || d.sectionAt(d.lines[l]) != sec {
+			return 0, 0, false
+		}
+	}
+
+	// Closing code fences of the definition are not part of any block
+	e := d.lineOf(max(last-1, first))
+	for e+1 < len(d.lines) && !d.covered[e+1] && d.sectionAt(d.lines[e+1]) == sec &&
+		strings.TrimSpace(d.line(e+1)) != "" && (strings.HasPrefix(d.line(e+1), "    ") || strings.HasPrefix(d.line(e+1), "\t")) {
+		e++
+	}
+	return d.lines[l], min(d.lineEnd(e), d.sectionEnd(sec)), true
+}
+
+// definesFootnote reports whether a section already holds a definition of
+// the footnote, either the one starting at start or a duplicate.
+func (d *refDocument) definesFootnote(sec int, label []byte, start int) bool {
+	for l := d.lineOf(d.starts[sec]); l < len(d.lines) && d.lines[l] < d.sectionEnd(sec); l++ {
+		if (d.lines[l] == start || !d.covered[l]) && isFootnoteDefinition(d.line(l), label) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFootnoteDefinition reports whether a line starts the definition of the
+// footnote with the label.
+func isFootnoteDefinition(line string, label []byte) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	return len(line)-len(trimmed) <= 3 && strings.HasPrefix(trimmed, "[^"+string(label)+"]:")
+}
+
+// rewrite returns the source between start and end with the edits inside it
+// applied. A blank line following removed lines is dropped when the text
+// before them already ends with one.
+func (d *refDocument) rewrite(start, end int, edits []refEdit) string {
+	var b strings.Builder
+	cursor := start
+	for _, e := range edits {
+		if e.start < cursor || e.end > end {
+			continue
+		}
+		b.Write(d.src[cursor:e.start])
+		b.WriteString(e.text)
+		cursor = e.end
+		if e.text == "" && cursor < end && d.src[cursor] == '\n' &&
+			(b.Len() == 0 || strings.HasSuffix(b.String(), "\n\n")) {
+			cursor++
+		}
+	}
+	b.Write(d.src[cursor:end])
+	return b.String()
+}
+
+// inlineTarget formats the destination and title of a link reference
+// definition for an inline link.
+func inlineTarget(ref parser.Reference) string {
+	target := string(ref.Destination())
+	if target == "" || strings.ContainsAny(target, " \t()") {
+		target = "<" + target + ">"
+	}
+	title := string(ref.Title())
+	if title == "" {
+		return target
+	}
+	for _, delims := range []string{`""`, `''`, `()`} {
+		if !containsUnescaped(title, delims[1]) {
+			return target + " " + delims[:1] + title + delims[1:]
+		}
+	}
+	return target + ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+}
+
+// containsUnescaped reports whether s holds the byte c without a preceding
+// backslash.
+func containsUnescaped(s string, c byte) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == c {
+			return true
+		}
+	}
+	return false
+}
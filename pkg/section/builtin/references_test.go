@@ -0,0 +1,97 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+)
+
+func TestResolveReferencesTransform(t *testing.T) {
+	root := section.NewRoot("Test")
+	root.SetContent("See [the docs][Docs], [API][] and [Guide], ![logo][img] or [inline](http://x).\n")
+	usage := root.CreateChild("Usage", 2, "Run it.[^run] Not `[docs]` or \\[docs].\n\n```\n[docs]\n```\n")
+	notes := root.CreateChild("Notes", 2, "Again[^run], and more.[^multi]\n\n"+
+		"[docs]: https://example.com/docs \"Docs\"\n"+
+		"[api]: <https://example.com/a p i>\n"+
+		"[guide]: /guide\n"+
+		"[img]: /logo.png\n"+
+		"[unused]: /nowhere\n\n"+
+		"[^run]: Use `chunky run`.\n"+
+		"[^multi]: First line\n    continued.\n\n"+
+		"[^orphan]: Never cited.\n")
+
+	transform := ResolveReferencesTransform()
+	if err := transform(context.Background(), fm.EmptyFrontMatter().View(), root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		s        *section.Section
+		expected string
+	}{
+		{
+			name:     "links inlined",
+			s:        root,
+			expected: "See [the docs](https://example.com/docs \"Docs\"), [API](<https://example.com/a p i>) and [Guide](/guide), ![logo](/logo.png) or [inline](http://x).\n",
+		},
+		{
+			name:     "footnote appended, code untouched",
+			s:        usage,
+			expected: "Run it.[^run] Not `[docs]` or \\[docs].\n\n```\n[docs]\n```\n\n[^run]: Use `chunky run`.\n",
+		},
+		{
+			name: "used link definitions removed, cited footnotes kept",
+			s:    notes,
+			expected: "Again[^run], and more.[^multi]\n\n[unused]: /nowhere\n\n" +
+				"[^run]: Use `chunky run`.\n[^multi]: First line\n    continued.\n\n[^orphan]: Never cited.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.s.Content() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, tt.s.Content())
+			}
+		})
+	}
+
+	// Test idempotency
+	before := notes.Content()
+	if err := transform(context.Background(), fm.EmptyFrontMatter().View(), root); err != nil {
+		t.Fatalf("unexpected error on second pass: %v", err)
+	}
+	if notes.Content() != before {
+		t.Errorf("not idempotent: expected %q, got %q", before, notes.Content())
+	}
+}
+
+func TestResolveReferencesTransform_Blocks(t *testing.T) {
+	root := section.NewRoot("Test")
+	root.SetContent("Intro.\n")
+	code := root.CreateChild("Code", 2, "Indented:\n\n    [docs]\n    [^note]\n\nSee [docs].\n")
+	defs := root.CreateChild("Defs", 2, "Quote[^note].\n\n"+
+		"[docs]:\n  https://example.com/docs\n  'Docs \"here\"'\n\n"+
+		"    [^note]: not a definition\n\n"+
+		"[^note]: Read [docs].\n\n"+
+		"[^empty]:\n")
+	defs.CreateChild("More", 3, "Also[^note][^empty].\n")
+
+	transform := ResolveReferencesTransform()
+	if err := transform(context.Background(), fm.EmptyFrontMatter().View(), root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	link := "[docs](https://example.com/docs 'Docs \"here\"')"
+	if want := "Indented:\n\n    [docs]\n    [^note]\n\nSee " + link + ".\n"; code.Content() != want {
+		t.Errorf("expected %q, got %q", want, code.Content())
+	}
+	if want := "Quote[^note].\n\n    [^note]: not a definition\n\n[^note]: Read " + link + ".\n\n[^empty]:\n"; defs.Content() != want {
+		t.Errorf("expected %q, got %q", want, defs.Content())
+	}
+	more := defs.Children()[0]
+	if want := "Also[^note][^empty].\n\n[^note]: Read " + link + ".\n"; more.Content() != want {
+		t.Errorf("expected %q, got %q", want, more.Content())
+	}
+}
@@ -0,0 +1,53 @@
+package builtin
+
+import (
+	"context"
+	"regexp"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+)
+
+// DropSectionsTransform removes every section whose title matches pattern,
+// together with all of its subsections. The root section is never removed.
+//
+// Example: DropSectionsTransform(regexp.MustCompile(`(?i)^changelog$`))
+func DropSectionsTransform(pattern *regexp.Regexp) section.TreeTransform {
+	return func(ctx context.Context, _ fm.FrontMatterView, root *section.Section) error {
+		dropSections(root, pattern)
+		return nil
+	}
+}
+
+func dropSections(s *section.Section, pattern *regexp.Regexp) {
+	for _, c := range s.Children() {
+		if pattern.MatchString(c.Title()) {
+			s.RemoveChild(c)
+			continue
+		}
+		dropSections(c, pattern)
+	}
+}
+
+// FlattenTransform merges every section deeper than maxLevel into its parent,
+// so that the tree is at most maxLevel headings deep. Merged headings are kept
+// as Markdown heading lines in the content and document order is preserved.
+// A maxLevel of 0 flattens the whole document into the root section.
+func FlattenTransform(maxLevel int) section.TreeTransform {
+	return func(ctx context.Context, _ fm.FrontMatterView, root *section.Section) error {
+		return flatten(root, maxLevel)
+	}
+}
+
+func flatten(s *section.Section, maxLevel int) error {
+	// Merge bottom-up so each section absorbs its subsections before it is merged itself
+	for _, c := range s.Children() {
+		if err := flatten(c, maxLevel); err != nil {
+			return err
+		}
+	}
+	if s.IsRoot() || s.Level() <= maxLevel {
+		return nil
+	}
+	return s.MergeIntoParent()
+}
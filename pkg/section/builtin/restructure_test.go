@@ -0,0 +1,85 @@
+package builtin
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+)
+
+func childTitles(s *section.Section) string {
+	var out []string
+	for _, c := range s.Children() {
+		out = append(out, c.Title())
+	}
+	return strings.Join(out, ",")
+}
+
+func TestDropSectionsTransform(t *testing.T) {
+	root := section.NewRoot("Doc")
+	guide := root.CreateChild("Guide", 1, "Guide body\n")
+	guide.CreateChild("Changelog", 2, "v1\n").CreateChild("1.0", 3, "Initial\n")
+	guide.CreateChild("Usage", 2, "Usage body\n")
+	root.CreateChild("CHANGELOG", 1, "v2\n")
+
+	transform := DropSectionsTransform(regexp.MustCompile(`(?i)^changelog$`))
+	if err := section.ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, transform); err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+
+	if got := childTitles(root); got != "Guide" {
+		t.Errorf("expected root children Guide, got %q", got)
+	}
+	if got := childTitles(guide); got != "Usage" {
+		t.Errorf("expected Guide children Usage, got %q", got)
+	}
+}
+
+func TestFlattenTransform(t *testing.T) {
+	root := section.NewRoot("Doc")
+	a := root.CreateChild("A", 1, "Body A\n")
+	b := a.CreateChild("B", 2, "Body B\n")
+	c := b.CreateChild("C", 3, "Body C\n")
+	c.CreateChild("D", 4, "Body D\n")
+	b.CreateChild("E", 3, "Body E\n")
+	a.CreateChild("F", 2, "Body F\n")
+
+	transform := FlattenTransform(2)
+	if err := section.ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, transform); err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+
+	if got := childTitles(a); got != "B,F" {
+		t.Errorf("expected A children B,F, got %q", got)
+	}
+	if len(b.Children()) != 0 {
+		t.Errorf("expected B to have no children, got %q", childTitles(b))
+	}
+	expected := "Body B\n\n### C\n\nBody C\n\n#### D\n\nBody D\n\n### E\n\nBody E\n"
+	if b.Content() != expected {
+		t.Errorf("unexpected flattened content:\n got: %q\nwant: %q", b.Content(), expected)
+	}
+}
+
+func TestFlattenTransform_Zero(t *testing.T) {
+	root := section.NewRoot("Doc")
+	root.SetContent("Intro\n")
+	root.CreateChild("A", 1, "Body A\n").CreateChild("B", 2, "Body B\n")
+	root.CreateChild("C", 1, "Body C\n")
+
+	transform := FlattenTransform(0)
+	if err := section.ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, transform); err != nil {
+		t.Fatalf("transform failed: %v", err)
+	}
+
+	if len(root.Children()) != 0 {
+		t.Errorf("expected no children, got %q", childTitles(root))
+	}
+	expected := "Intro\n\n# A\n\nBody A\n\n## B\n\nBody B\n\n# C\n\nBody C\n"
+	if root.Content() != expected {
+		t.Errorf("unexpected flattened content:\n got: %q\nwant: %q", root.Content(), expected)
+	}
+}
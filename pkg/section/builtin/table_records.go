@@ -0,0 +1,92 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cctx "github.com/wyvernzora/chunky/pkg/context"
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/markdown"
+	"github.com/wyvernzora/chunky/pkg/section"
+	"github.com/yuin/goldmark/text"
+)
+
+// TableRecordsTransform returns a transform that rewrites GFM tables as
+// lists of row-wise "Column: value" records, which embed better than
+// pipe-delimited rows because every value sits next to its column name.
+//
+// Example:
+//
+//	| Code | Meaning   |          - Code: E1; Meaning: Not found
+//	| ---- | --------- |    ->    - Code: E2; Meaning: Forbidden
+//	| E1   | Not found |
+//	| E2   | Forbidden |
+//
+// Empty cells are left out of a record, and cells without a column name are
+// labeled "Column N". Tables without body rows, or whose body cells are all
+// empty, have no records and are left unchanged. Only top-level tables are
+// rewritten (see markdown.Tables); tables are recognized with the goldmark
+// configuration from the context, so this transform does nothing if GFM is
+// disabled.
+//
+// The transform is idempotent: applying it multiple times produces the same result.
+func TableRecordsTransform() section.Transform {
+	return func(ctx context.Context, _ fm.FrontMatterView, s *section.Section) error {
+		src := []byte(s.Content())
+		if len(src) == 0 {
+			return nil
+		}
+		doc := cctx.Markdown(ctx).Parser().Parse(text.NewReader(src))
+		tables := markdown.Tables(doc, src)
+		if len(tables) == 0 {
+			return nil
+		}
+
+		var out strings.Builder
+		cursor := 0
+		for _, t := range tables {
+			records := tableRecords(t)
+			if records == "" {
+				continue // a table without records stays as it is
+			}
+			out.Write(src[cursor:t.Start])
+			out.WriteString(records)
+			cursor = t.End
+		}
+		out.Write(src[cursor:])
+		s.SetContent(out.String())
+		return nil
+	}
+}
+
+// tableRecords renders the rows of a table as a Markdown list of records.
+// Returns "" if no row has a non-empty cell.
+func tableRecords(t markdown.Table) string {
+	columns := markdown.SplitRow(t.Header)
+	var out strings.Builder
+	for _, row := range t.Rows {
+		var fields []string
+		for i, cell := range markdown.SplitRow(row) {
+			if cell == "" {
+				continue
+			}
+			name := fmt.Sprintf("Column %d", i+1)
+			if i < len(columns) && columns[i] != "" {
+				name = columns[i]
+			}
+			fields = append(fields, name+": "+cell)
+		}
+		if len(fields) > 0 {
+			out.WriteString("- " + strings.Join(fields, "; ") + "\n")
+		}
+	}
+	if out.Len() == 0 {
+		return ""
+	}
+	// Keep a table at the very end of the content unterminated
+	if !strings.HasSuffix(t.Rows[len(t.Rows)-1], "\n") {
+		return strings.TrimSuffix(out.String(), "\n")
+	}
+	return out.String()
+}
@@ -0,0 +1,76 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	fm "github.com/wyvernzora/chunky/pkg/frontmatter"
+	"github.com/wyvernzora/chunky/pkg/section"
+)
+
+func TestTableRecordsTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "rows become records",
+			input:    "Codes:\n\n| Code | Meaning |\n| --- | --- |\n| E1 | Not found |\n| E2 | Forbidden |\n\nAfter.",
+			expected: "Codes:\n\n- Code: E1; Meaning: Not found\n- Code: E2; Meaning: Forbidden\n\nAfter.",
+		},
+		{
+			name:     "empty cells and unnamed columns",
+			input:    "| Key | |\n| - | - |\n| a | x |\n| | y |",
+			expected: "- Key: a; Column 2: x\n- Column 2: y",
+		},
+		{
+			name:     "escaped pipes",
+			input:    "| Op | Meaning |\n| - | - |\n| `a \\| b` | or |\n",
+			expected: "- Op: `a | b`; Meaning: or\n",
+		},
+		{
+			name:     "header without rows unchanged",
+			input:    "| A | B |\n| - | - |\n",
+			expected: "| A | B |\n| - | - |\n",
+		},
+		{
+			name:     "empty rows unchanged",
+			input:    "Before.\n\n| A | B |\n| - | - |\n| | |\n\nAfter.",
+			expected: "Before.\n\n| A | B |\n| - | - |\n| | |\n\nAfter.",
+		},
+		{
+			name:     "tables in block quotes unchanged",
+			input:    "> | A |\n> | - |\n> | 1 |\n",
+			expected: "> | A |\n> | - |\n> | 1 |\n",
+		},
+		{
+			name:     "no tables",
+			input:    "Plain | text",
+			expected: "Plain | text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := section.NewRoot("Test")
+			s.SetContent(tt.input)
+
+			transform := TableRecordsTransform()
+			if err := transform(context.Background(), fm.EmptyFrontMatter().View(), s); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s.Content() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, s.Content())
+			}
+
+			// Test idempotency
+			if err := transform(context.Background(), fm.EmptyFrontMatter().View(), s); err != nil {
+				t.Fatalf("unexpected error on second pass: %v", err)
+			}
+			if s.Content() != tt.expected {
+				t.Errorf("not idempotent: expected %q, got %q", tt.expected, s.Content())
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package section
+
+import "strings"
+
+// Directive markers are lines in section content that control how the chunker
+// packs it. Parsers emit them for "<!-- chunky:... -->" comments in the source
+// (see parser/builtin), and the chunker removes them from the emitted text.
+// Each marker must be on a line of its own.
+const (
+	// BreakMarker forces a chunk boundary at its position.
+	BreakMarker = "<!-- chunky:break -->"
+
+	// KeepTogetherMarker starts a region, ending at the next EndMarker, that
+	// is never split across chunks, even when it spans several sections.
+	KeepTogetherMarker = "<!-- chunky:keep-together -->"
+
+	// EndMarker closes the region started by KeepTogetherMarker.
+	EndMarker = "<!-- chunky:end -->"
+)
+
+// HasDirectiveMarkers reports whether content contains any directive marker.
+func HasDirectiveMarkers(content string) bool {
+	return strings.Contains(content, "<!-- chunky:")
+}
+
+// IsDirectiveMarker reports whether a line, ignoring surrounding whitespace,
+// is one of the directive markers.
+func IsDirectiveMarker(line string) bool {
+	switch strings.TrimSpace(line) {
+	case BreakMarker, KeepTogetherMarker, EndMarker:
+		return true
+	}
+	return false
+}
@@ -10,6 +10,7 @@
 //
 //	type Section struct {
 //	    // Title: Heading text
+//	    // Anchor: Heading anchor ID for deep links
 //	    // Level: Heading level (1-6, 0 for root)
 //	    // Content: Text content before first child
 //	    // Children: Subsections
@@ -21,6 +22,23 @@
 //   - Content includes text up to first subheading
 //   - Children maintain document order
 //
+// # Directive Markers
+//
+// Content may contain BreakMarker, KeepTogetherMarker and EndMarker lines
+// left by the parser for inline chunky directives. The chunker splits and
+// groups content at these lines and drops them; transforms should leave them
+// on lines of their own.
+//
+// # Metadata
+//
+// Sections carry string metadata, set by "chunky:meta" directives, heading
+// attributes or transforms via SetMeta. Meta and Metadata return a section's
+// own values; InheritedMetadata merges in those of its ancestors, which the
+// chunker attaches to each chunk:
+//
+//	install.SetMeta("audience", "admins")
+//	linux.InheritedMetadata() // map[audience:admins] for a subsection of install
+//
 // # Transform System
 //
 // Transforms modify section content during processing:
@@ -49,6 +67,24 @@
 //  6. HeadingPrefixTransform: Add Section heading to its content
 //  7. HeadingPathCommentTransform: Adds "<!-- heading.path -->" comments
 //
+// TableRecordsTransform is not applied by default; it rewrites GFM tables as
+// lists of "Column: value" records, one per row. Neither is
+// OmitLargeCodeBlocksTransform, which replaces fenced code blocks over a token
+// limit with a placeholder line.
+//
+// # Tree Transforms
+//
+// TreeTransform has the same signature as Transform but is called once with
+// the root section instead of once per node. Tree transforms restructure the
+// document using the Section mutation methods (RemoveChild, InsertChildAt,
+// MergeIntoParent, Detach, SetTitle, SetLevel) and are applied with
+// ApplyTreeTransform. The builtin subpackage provides:
+//
+//  1. DropSectionsTransform: Removes sections whose title matches a pattern
+//  2. FlattenTransform: Merges sections deeper than a level into their parents
+//  3. ResolveReferencesTransform: Inlines reference links and copies footnote
+//     definitions to the sections citing them
+//
 // # Tree Operations
 //
 // Common patterns for working with section trees:
@@ -67,8 +103,10 @@
 //	section.SetContent("New content")
 //	section.AppendContent("\nAdditional text")
 //
-//	// Get hierarchical path
-//	path := section.Path() // ["Document Title", "Section 1", "Subsection"]
+//	// Restructure tree
+//	grandchild.MergeIntoParent() // "## Subsection" heading joins child's content
+//	root.InsertChildAt(0, other) // move or re-parent a section
+//	root.RemoveChild(child)      // drop a section and its subtree
 //
 // # Usage with Transforms
 //
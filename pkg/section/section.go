@@ -1,12 +1,22 @@
 package section
 
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
 // Section represents a Markdown heading section and its associated body content.
 // The root section is synthetic (Level = 0) and represents the entire file.
 type Section struct {
 	parent   *Section
 	title    string
+	anchor   string
+	explicit bool
 	level    int
 	content  string
+	meta     map[string]string
 	children []*Section
 }
 
@@ -36,11 +46,37 @@ func (s *Section) Title() string {
 	return s.title
 }
 
+// SetTitle replaces the title. For the root section this is the document title.
+func (s *Section) SetTitle(title string) { s.title = title }
+
+// Anchor is the heading's anchor ID for deep links, e.g. "getting-started"
+// for "## Getting Started". Empty if the section has no heading or the parser
+// did not assign one.
+func (s *Section) Anchor() string {
+	return s.anchor
+}
+
+// SetAnchor replaces the anchor ID with a generated one.
+func (s *Section) SetAnchor(anchor string) { s.anchor, s.explicit = anchor, false }
+
+// ExplicitAnchor reports whether the anchor ID was written in the document,
+// e.g. "## Install {#setup}", rather than generated from the title. Links
+// elsewhere may depend on it, so the heading should not be dissolved.
+func (s *Section) ExplicitAnchor() bool {
+	return s.explicit
+}
+
+// SetExplicitAnchor replaces the anchor ID with one written in the document.
+func (s *Section) SetExplicitAnchor(anchor string) { s.anchor, s.explicit = anchor, true }
+
 // Level is the Markdown heading depth (root = 0).
 func (s *Section) Level() int {
 	return s.level
 }
 
+// SetLevel changes the heading depth. Children are not adjusted.
+func (s *Section) SetLevel(level int) { s.level = level }
+
 // Content returns the accumulated Markdown body text.
 func (s *Section) Content() string {
 	return s.content
@@ -58,6 +94,49 @@ func (s *Section) AppendContent(fragment string) { s.content += fragment }
 // PrependContent prepends to the body.
 func (s *Section) PrependContent(fragment string) { s.content = fragment + s.content }
 
+// Meta returns the section-level metadata value for key, e.g. one set by a
+// "<!-- chunky:meta key=value -->" directive.
+func (s *Section) Meta(key string) (string, bool) {
+	v, ok := s.meta[key]
+	return v, ok
+}
+
+// SetMeta sets a section-level metadata value.
+func (s *Section) SetMeta(key, value string) {
+	if s.meta == nil {
+		s.meta = make(map[string]string)
+	}
+	s.meta[key] = value
+}
+
+// Metadata returns a copy of the section-level metadata, or nil if there is none.
+// Metadata is not inherited from parent sections.
+func (s *Section) Metadata() map[string]string {
+	if len(s.meta) == 0 {
+		return nil
+	}
+	return maps.Clone(s.meta)
+}
+
+// InheritedMetadata returns the section-level metadata of the section merged
+// over that of its ancestors, so that a value set on a section applies to all
+// of its subsections unless they set the key themselves. Returns nil if
+// neither the section nor any ancestor has metadata.
+func (s *Section) InheritedMetadata() map[string]string {
+	var merged map[string]string
+	for cur := s; cur != nil; cur = cur.parent {
+		for k, v := range cur.meta {
+			if merged == nil {
+				merged = make(map[string]string)
+			}
+			if _, ok := merged[k]; !ok {
+				merged[k] = v
+			}
+		}
+	}
+	return merged
+}
+
 // Children returns a copy of the section's children.
 func (s *Section) Children() []*Section {
 	out := make([]*Section, len(s.children))
@@ -77,3 +156,109 @@ func (s *Section) CreateChild(title string, level int, content string) *Section
 	s.children = append(s.children, child)
 	return child
 }
+
+// IndexOf returns the position of child among the section's children, or -1.
+func (s *Section) IndexOf(child *Section) int {
+	for i, c := range s.children {
+		if c == child {
+			return i
+		}
+	}
+	return -1
+}
+
+// RemoveChild removes a direct child and detaches it from this section.
+// Reports whether child was found.
+func (s *Section) RemoveChild(child *Section) bool {
+	i := s.IndexOf(child)
+	if i < 0 {
+		return false
+	}
+	s.children = append(s.children[:i:i], s.children[i+1:]...)
+	child.parent = nil
+	return true
+}
+
+// InsertChildAt inserts child so that it ends up at position index among the
+// section's children. A child that belongs to a section (including this one)
+// is detached from it first, so children can be moved and reordered.
+//
+// Returns an error if index is out of range or child is this section or one
+// of its ancestors, which would create a cycle.
+func (s *Section) InsertChildAt(index int, child *Section) error {
+	for a := s; a != nil; a = a.parent {
+		if a == child {
+			return fmt.Errorf("cannot insert section %q into its own subtree", child.title)
+		}
+	}
+
+	n := len(s.children)
+	if child.parent == s {
+		n-- // moving within this section
+	}
+	if index < 0 || index > n {
+		return fmt.Errorf("index %d out of range [0, %d]", index, n)
+	}
+
+	child.Detach()
+	s.children = slices.Insert(s.children, index, child)
+	child.parent = s
+	return nil
+}
+
+// Detach removes the section from its parent. The section keeps its children
+// and becomes the root of its own tree. Detaching a root does nothing.
+func (s *Section) Detach() {
+	if s.parent != nil {
+		s.parent.RemoveChild(s)
+	}
+}
+
+// MergeIntoParent dissolves the section into the surrounding document:
+//   - its heading becomes a Markdown heading line (e.g. "### Title") at the
+//     top of its content, unless the content already starts with it;
+//   - its content is appended to the previous sibling, or to the parent if it
+//     is the first child or the previous sibling has subsections;
+//   - its children take its place among the parent's children.
+//
+// A sibling's content precedes its subsections, so a sibling with subsections
+// cannot take the content without it landing inside the wrong subtree; the
+// parent takes it instead, which moves the content ahead of that sibling in
+// document order. Otherwise document order is preserved. The section's own
+// metadata is discarded.
+// Returns an error for root sections.
+func (s *Section) MergeIntoParent() error {
+	parent := s.parent
+	if parent == nil {
+		return fmt.Errorf("cannot merge root section %q into a parent", s.title)
+	}
+	i := parent.IndexOf(s)
+
+	// Merge into the previous sibling if its content directly precedes this one
+	prev := parent
+	if i > 0 && len(parent.children[i-1].children) == 0 {
+		prev = parent.children[i-1]
+	}
+
+	content := s.content
+	if s.level > 0 {
+		heading := strings.Repeat("#", s.level) + " " + s.title
+		if first, _, _ := strings.Cut(strings.TrimLeft(content, "\n"), "\n"); strings.TrimSpace(first) != heading {
+			content = heading + "\n\n" + strings.TrimLeft(content, "\n")
+		}
+	}
+	// Keep a blank line between the preceding content and the merged heading
+	if prev.content != "" {
+		prev.content = strings.TrimRight(prev.content, "\n") + "\n\n"
+	}
+	prev.content += content
+
+	// Splice children into the parent in place of this section
+	for _, c := range s.children {
+		c.parent = parent
+	}
+	parent.children = slices.Replace(parent.children, i, i+1, s.children...)
+	s.children = make([]*Section, 0)
+	s.parent = nil
+	return nil
+}
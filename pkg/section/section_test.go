@@ -1,6 +1,8 @@
 package section
 
 import (
+	"maps"
+	"strings"
 	"testing"
 )
 
@@ -319,3 +321,238 @@ func TestContentManipulation(t *testing.T) {
 		t.Errorf("expected 'After reset', got %q", s.Content())
 	}
 }
+
+func titles(sections []*Section) []string {
+	out := make([]string, len(sections))
+	for i, s := range sections {
+		out[i] = s.Title()
+	}
+	return out
+}
+
+func TestSetLevel(t *testing.T) {
+	root := NewRoot("Root")
+	child := root.CreateChild("Child", 3, "")
+	child.SetLevel(2)
+
+	if child.Level() != 2 {
+		t.Errorf("expected level 2, got %d", child.Level())
+	}
+}
+
+func TestRemoveChild(t *testing.T) {
+	root := NewRoot("Root")
+	a := root.CreateChild("A", 1, "")
+	b := root.CreateChild("B", 1, "")
+	c := root.CreateChild("C", 1, "")
+
+	if !root.RemoveChild(b) {
+		t.Fatal("expected RemoveChild to find B")
+	}
+	if got := titles(root.Children()); strings.Join(got, ",") != "A,C" {
+		t.Errorf("expected children A,C, got %v", got)
+	}
+	if b.Parent() != nil || !b.IsRoot() {
+		t.Error("expected removed child to be detached")
+	}
+	if root.RemoveChild(b) {
+		t.Error("expected RemoveChild to report false for a non-child")
+	}
+	if a.Parent() != root || c.Parent() != root {
+		t.Error("expected remaining children to keep their parent")
+	}
+}
+
+func TestDetach(t *testing.T) {
+	root := NewRoot("Root")
+	a := root.CreateChild("A", 1, "")
+	sub := a.CreateChild("Sub", 2, "")
+
+	a.Detach()
+
+	if len(root.Children()) != 0 {
+		t.Errorf("expected root to have no children, got %d", len(root.Children()))
+	}
+	if !a.IsRoot() {
+		t.Error("expected detached section to be a root")
+	}
+	if sub.Parent() != a || len(a.Children()) != 1 {
+		t.Error("expected detached section to keep its children")
+	}
+
+	// Detaching a root is a no-op
+	root.Detach()
+	if !root.IsRoot() {
+		t.Error("expected root to remain a root")
+	}
+}
+
+func TestInsertChildAt(t *testing.T) {
+	root := NewRoot("Root")
+	a := root.CreateChild("A", 1, "")
+	b := root.CreateChild("B", 1, "")
+	c := root.CreateChild("C", 1, "")
+
+	// Reorder within the same parent
+	if err := root.InsertChildAt(0, c); err != nil {
+		t.Fatalf("InsertChildAt failed: %v", err)
+	}
+	if got := titles(root.Children()); strings.Join(got, ",") != "C,A,B" {
+		t.Errorf("expected C,A,B, got %v", got)
+	}
+	if err := root.InsertChildAt(2, c); err != nil {
+		t.Fatalf("InsertChildAt failed: %v", err)
+	}
+	if got := titles(root.Children()); strings.Join(got, ",") != "A,B,C" {
+		t.Errorf("expected A,B,C, got %v", got)
+	}
+
+	// Re-parent
+	if err := a.InsertChildAt(0, b); err != nil {
+		t.Fatalf("InsertChildAt failed: %v", err)
+	}
+	if got := titles(root.Children()); strings.Join(got, ",") != "A,C" {
+		t.Errorf("expected root children A,C, got %v", got)
+	}
+	if b.Parent() != a || a.IndexOf(b) != 0 {
+		t.Error("expected B to be moved under A")
+	}
+
+	// Insert a new detached section
+	d := NewRoot("D")
+	if err := root.InsertChildAt(2, d); err != nil {
+		t.Fatalf("InsertChildAt failed: %v", err)
+	}
+	if d.Parent() != root || root.IndexOf(d) != 2 {
+		t.Error("expected D to be appended to root")
+	}
+}
+
+func TestInsertChildAt_Errors(t *testing.T) {
+	root := NewRoot("Root")
+	a := root.CreateChild("A", 1, "")
+	sub := a.CreateChild("Sub", 2, "")
+
+	if err := root.InsertChildAt(2, a); err == nil {
+		t.Error("expected error for out of range index")
+	}
+	if err := root.InsertChildAt(-1, NewRoot("X")); err == nil {
+		t.Error("expected error for negative index")
+	}
+	if err := sub.InsertChildAt(0, a); err == nil {
+		t.Error("expected error when inserting an ancestor")
+	}
+	if err := a.InsertChildAt(0, a); err == nil {
+		t.Error("expected error when inserting a section into itself")
+	}
+
+	// Failed inserts leave the tree untouched
+	if a.Parent() != root || sub.Parent() != a {
+		t.Error("expected tree to be unchanged after errors")
+	}
+}
+
+func TestMergeIntoParent(t *testing.T) {
+	root := NewRoot("Root")
+	a := root.CreateChild("A", 1, "Intro A\n")
+	b := a.CreateChild("B", 2, "Body B\n")
+	b.CreateChild("B1", 3, "Body B1\n")
+	b.CreateChild("B2", 3, "Body B2\n")
+	a.CreateChild("C", 2, "Body C\n")
+
+	if err := b.MergeIntoParent(); err != nil {
+		t.Fatalf("MergeIntoParent failed: %v", err)
+	}
+
+	if got := titles(a.Children()); strings.Join(got, ",") != "B1,B2,C" {
+		t.Errorf("expected children spliced in place, got %v", got)
+	}
+	for _, c := range a.Children() {
+		if c.Parent() != a {
+			t.Errorf("expected %q to be re-parented to A", c.Title())
+		}
+	}
+	if a.Content() != "Intro A\n\n## B\n\nBody B\n" {
+		t.Errorf("unexpected merged content: %q", a.Content())
+	}
+	if !b.IsRoot() || len(b.Children()) != 0 {
+		t.Error("expected merged section to be detached and empty")
+	}
+}
+
+func TestMergeIntoParent_PrecedingSibling(t *testing.T) {
+	root := NewRoot("Root")
+	a := root.CreateChild("A", 1, "Body A\n")
+	b := root.CreateChild("B", 1, "# B\n\nBody B\n")
+
+	if err := b.MergeIntoParent(); err != nil {
+		t.Fatalf("MergeIntoParent failed: %v", err)
+	}
+
+	// Content goes to the previous sibling, which precedes it in document
+	// order; an existing heading line is not repeated
+	if a.Content() != "Body A\n\n# B\n\nBody B\n" {
+		t.Errorf("unexpected merged content: %q", a.Content())
+	}
+	if got := titles(root.Children()); strings.Join(got, ",") != "A" {
+		t.Errorf("expected only A under root, got %v", got)
+	}
+}
+
+func TestMergeIntoParent_SiblingWithChildren(t *testing.T) {
+	root := NewRoot("Root")
+	root.AppendContent("Intro\n")
+	a := root.CreateChild("A", 1, "Body A\n")
+	deep := a.CreateChild("Deep", 2, "Body Deep\n")
+	b := root.CreateChild("B", 1, "Body B\n")
+
+	if err := b.MergeIntoParent(); err != nil {
+		t.Fatalf("MergeIntoParent failed: %v", err)
+	}
+
+	// A's content precedes Deep, so B cannot join A without landing inside
+	// A's subtree; the parent takes it instead
+	if root.Content() != "Intro\n\n# B\n\nBody B\n" {
+		t.Errorf("unexpected root content: %q", root.Content())
+	}
+	if a.Content() != "Body A\n" || deep.Content() != "Body Deep\n" {
+		t.Errorf("expected A and Deep unchanged, got %q and %q", a.Content(), deep.Content())
+	}
+	if got := titles(root.Children()); strings.Join(got, ",") != "A" {
+		t.Errorf("expected only A under root, got %v", got)
+	}
+}
+
+func TestMergeIntoParent_Root(t *testing.T) {
+	root := NewRoot("Root")
+	if err := root.MergeIntoParent(); err == nil {
+		t.Error("expected error when merging root")
+	}
+}
+
+func TestInheritedMetadata(t *testing.T) {
+	root := NewRoot("Root")
+	a := root.CreateChild("A", 1, "")
+	b := a.CreateChild("B", 2, "")
+	c := root.CreateChild("C", 1, "")
+
+	if meta := b.InheritedMetadata(); meta != nil {
+		t.Errorf("expected no metadata, got %v", meta)
+	}
+
+	root.SetMeta("product", "base")
+	a.SetMeta("audience", "admins")
+	b.SetMeta("product", "pro")
+
+	got := b.InheritedMetadata()
+	want := map[string]string{"product": "pro", "audience": "admins"}
+	if !maps.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := c.InheritedMetadata(); !maps.Equal(got, map[string]string{"product": "base"}) {
+		t.Errorf("expected only root metadata on sibling, got %v", got)
+	}
+	if _, ok := b.Meta("audience"); ok {
+		t.Error("expected Meta to return only the section's own metadata")
+	}
+}
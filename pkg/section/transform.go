@@ -47,3 +47,28 @@ func walk(ctx context.Context, fm fm.FrontMatterView, s *Section, ts []Transform
 
 	return nil
 }
+
+// TreeTransform is a function that restructures a whole section tree.
+// Unlike Transform, which is applied to every node, a TreeTransform is called
+// once with the root and may remove, reorder, merge or re-parent sections
+// using the mutation methods on Section.
+//
+// The root itself must stay the root; transforms may change its title and
+// content but not detach it.
+type TreeTransform func(ctx context.Context, fm fm.FrontMatterView, root *Section) error
+
+// ApplyTreeTransform applies the given tree transforms to root in order.
+// Stops and returns on the first error.
+//
+// Example:
+//
+//	err := section.ApplyTreeTransform(ctx, frontmatter, root, dropChangelog, flatten)
+func ApplyTreeTransform(ctx context.Context, fm fm.FrontMatter, root *Section, ts ...TreeTransform) error {
+	view := fm.View()
+	for _, t := range ts {
+		if err := t(ctx, view, root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -548,3 +548,78 @@ func TestApplyTransform_MultipleTransformsAllNodes(t *testing.T) {
 		t.Errorf("grandchild: expected 'PCS', got %q", grandchild.Content())
 	}
 }
+
+func TestApplyTreeTransform_CalledOnceWithRoot(t *testing.T) {
+	root := NewRoot("root")
+	root.CreateChild("child1", 1, "")
+	root.CreateChild("child2", 1, "")
+
+	var calls []string
+	record := func(ctx context.Context, _ fm.FrontMatterView, s *Section) error {
+		calls = append(calls, s.Title())
+		return nil
+	}
+
+	err := ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, record)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Join(calls, ",") != "root" {
+		t.Errorf("expected a single call with root, got %v", calls)
+	}
+}
+
+func TestApplyTreeTransform_Restructure(t *testing.T) {
+	root := NewRoot("root")
+	a := root.CreateChild("a", 1, "")
+	b := root.CreateChild("b", 1, "")
+
+	reverse := func(ctx context.Context, _ fm.FrontMatterView, r *Section) error {
+		return r.InsertChildAt(0, b)
+	}
+	drop := func(ctx context.Context, _ fm.FrontMatterView, r *Section) error {
+		r.RemoveChild(a)
+		return nil
+	}
+
+	var seen []string
+	after := func(ctx context.Context, _ fm.FrontMatterView, r *Section) error {
+		for _, c := range r.Children() {
+			seen = append(seen, c.Title())
+		}
+		return nil
+	}
+
+	err := ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, reverse, after, drop)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Join(seen, ",") != "b,a" {
+		t.Errorf("expected later transforms to see earlier changes, got %v", seen)
+	}
+	if len(root.Children()) != 1 || root.Children()[0] != b {
+		t.Errorf("expected only b to remain, got %d children", len(root.Children()))
+	}
+}
+
+func TestApplyTreeTransform_ErrorStops(t *testing.T) {
+	root := NewRoot("root")
+	expectedErr := errors.New("tree failed")
+
+	called := false
+	fail := func(ctx context.Context, _ fm.FrontMatterView, r *Section) error {
+		return expectedErr
+	}
+	never := func(ctx context.Context, _ fm.FrontMatterView, r *Section) error {
+		called = true
+		return nil
+	}
+
+	err := ApplyTreeTransform(context.Background(), fm.EmptyFrontMatter(), root, fail, never)
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("expected %v, got %v", expectedErr, err)
+	}
+	if called {
+		t.Error("expected transforms after the failing one not to run")
+	}
+}
@@ -2,8 +2,8 @@ package builtin
 
 import (
 	"strings"
-	"unicode"
 
+	"github.com/wyvernzora/chunky/internal/cjk"
 	"github.com/wyvernzora/chunky/pkg/tokenizer"
 )
 
@@ -39,7 +39,10 @@ func WithWordsPerToken(wpt float64) WordCountOption {
 //   - Splits on any Unicode whitespace (spaces, tabs, newlines, etc.)
 //   - Counts sequences of non-whitespace as words
 //   - Handles punctuation attached to words (e.g., "hello," counts as one word)
-//   - Works with Unicode text (CJK, Arabic, Cyrillic, etc.)
+//   - Counts each CJK ideograph and kana character as one word, since Chinese
+//     and Japanese do not separate words with spaces
+//   - Treats CJK punctuation (e.g., "、", "。", "「") as a word separator
+//   - Counts Hangul like Latin text, since Korean separates words with spaces
 //
 // This provides a more accurate approximation than character counting for
 // languages that use spaces to separate words, and is faster than actual
@@ -68,40 +71,11 @@ func NewWordCountTokenizer(opts ...WordCountOption) tokenizer.Tokenizer {
 		opt(cfg)
 	}
 	return tokenizer.MakeTokenizer(func(s string) (int, error) {
-		words := countWords(s)
+		words := cjk.CountWords(s, nil)
 		return int(float64(words) / cfg.wordsPerToken), nil
 	})
 }
 
-// countWords counts the number of words in the text using Unicode-aware
-// whitespace splitting.
-func countWords(text string) int {
-	if text == "" {
-		return 0
-	}
-
-	words := 0
-	inWord := false
-
-	for _, r := range text {
-		if unicode.IsSpace(r) {
-			if inWord {
-				words++
-				inWord = false
-			}
-		} else {
-			inWord = true
-		}
-	}
-
-	// Count the last word if we ended in the middle of one
-	if inWord {
-		words++
-	}
-
-	return words
-}
-
 // countWordsSimple is an alternative implementation using strings.Fields
 // for comparison and validation purposes.
 func countWordsSimple(text string) int {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/wyvernzora/chunky/internal/cjk"
 	"github.com/wyvernzora/chunky/pkg/section"
 )
 
@@ -181,11 +182,11 @@ func TestWordCountTokenizer_Count_Unicode(t *testing.T) {
 		text     string
 		expected int
 	}{
-		{"chinese", "你好 世界", 2},
-		{"japanese", "こんにちは 世界", 2},
+		{"chinese", "你好 世界", 4},
+		{"japanese", "こんにちは 世界", 7},
 		{"arabic", "مرحبا بالعالم", 2},
 		{"cyrillic", "Привет мир", 2},
-		{"mixed", "Hello 世界", 2},
+		{"mixed", "Hello 世界", 3},
 		{"emoji with text", "Hello 🌍 world", 3},
 		{"only emoji", "🌍🚀🎉", 1}, // Emojis without spaces count as one word
 		{"emoji separated", "🌍 🚀 🎉", 3},
@@ -204,6 +205,39 @@ func TestWordCountTokenizer_Count_Unicode(t *testing.T) {
 	}
 }
 
+func TestWordCountTokenizer_Count_CJK(t *testing.T) {
+	tok := NewWordCountTokenizer()
+
+	testCases := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{"chinese sentence", "我们发布了新版本", 8},
+		{"chinese punctuation", "你好，世界。", 4},
+		{"japanese mixed scripts", "チャンクを作成します", 10},
+		{"japanese prolonged sound mark", "データ", 3},
+		{"japanese brackets", "「設定」を開く", 5},
+		{"korean space-delimited", "안녕하세요 세계", 2},
+		{"latin adjacent to han", "Go言語", 3},
+		{"han adjacent to latin", "使用API", 3},
+		{"ideographic space", "東京　大阪", 4},
+		{"fullwidth punctuation", "はい！いいえ？", 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			count, err := tok.Count(tc.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count != tc.expected {
+				t.Errorf("expected %d, got %d", tc.expected, count)
+			}
+		})
+	}
+}
+
 func TestWordCountTokenizer_Count_Multiline(t *testing.T) {
 	tok := NewWordCountTokenizer()
 
@@ -384,7 +418,7 @@ func TestWordCountTokenizer_SpecialCases(t *testing.T) {
 }
 
 func TestWordCountTokenizer_ConsistentWithSimple(t *testing.T) {
-	// Verify countWords produces same results as strings.Fields for basic cases
+	// Verify cjk.CountWords produces same results as strings.Fields for basic cases
 	testCases := []string{
 		"hello world",
 		"  hello  world  ",
@@ -395,10 +429,10 @@ func TestWordCountTokenizer_ConsistentWithSimple(t *testing.T) {
 
 	for _, text := range testCases {
 		t.Run(text, func(t *testing.T) {
-			got := countWords(text)
+			got := cjk.CountWords(text, nil)
 			expected := countWordsSimple(text)
 			if got != expected {
-				t.Errorf("countWords=%d, countWordsSimple=%d", got, expected)
+				t.Errorf("CountWords=%d, countWordsSimple=%d", got, expected)
 			}
 		})
 	}
@@ -36,6 +36,7 @@
 //
 //  2. WordCountTokenizer: Approximates tokens by counting words
 //     - Configurable words-per-token ratio
+//     - Counts each CJK ideograph and kana character as a word
 //     - Fast, no external dependencies
 //
 //  3. CharacterCountTokenizer: Approximates tokens by counting characters